@@ -23,12 +23,31 @@ module.exports = {
           'cli/packageless',
           {
             Subcommands: [
+              'cli/subcommands/completion',
+              'cli/subcommands/config',
+              'cli/subcommands/doctor',
+              'cli/subcommands/export',
+              'cli/subcommands/help',
+              'cli/subcommands/info',
+              'cli/subcommands/init',
               'cli/subcommands/install',
+              'cli/subcommands/list',
+              'cli/subcommands/outdated',
+              'cli/subcommands/pin',
+              'cli/subcommands/plugins',
+              'cli/subcommands/prune',
+              'cli/subcommands/ps',
+              'cli/subcommands/rollback',
               'cli/subcommands/uninstall',
+              'cli/subcommands/unpin',
               'cli/subcommands/run',
+              'cli/subcommands/search',
+              'cli/subcommands/stop',
+              'cli/subcommands/sync',
               'cli/subcommands/update',
               'cli/subcommands/upgrade',
-              'cli/subcommands/version'
+              'cli/subcommands/version',
+              'cli/subcommands/which'
             ]
           },
         ]
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -10,46 +11,110 @@ import (
 )
 
 func main() {
-	exitCode, exitErr := wrappedMain()
+	//Create the utils for the subcommands
+	util := utils.NewUtility()
 
-	if exitErr != nil {
-		errString := "# ERROR\n" + "**Encountered an error:** *%s*\n"
-		utils.NewUtility().RenderErrorMarkdown(fmt.Sprintf(errString, exitErr.Error()))
-	}
+	exitCode, exitErr := wrappedMain(util)
+
+	//Output the error, and the final result for the formats that have one
+	util.Finish(exitCode, exitErr)
 
 	os.Exit(exitCode)
 }
 
-func wrappedMain() (int, error) {
-	//Create the utils for the subcommands
-	util := utils.NewUtility()
+//Subcommands that never use the config so it is not loaded for them
+var configFree = map[string]bool{
+	"version":    true,
+	"init":       true,
+	"completion": true,
+	"config":     true,
+}
+
+//Subcommands that handle a missing or invalid config themselves, the default config is used for them instead.
+//The help lists the plugins found in the base_dir of the config, but it is shown even if the config can not be loaded
+var configLenient = map[string]bool{
+	"":           true,
+	"-h":         true,
+	"-help":      true,
+	"--help":     true,
+	"help":       true,
+	"doctor":     true,
+	"__complete": true,
+}
 
+func wrappedMain(util *utils.Utility) (int, error) {
 	//Create the copier for the subcommands
 	cp := &utils.CopyTool{}
 
-	//Config file location
-	configLoc, err := os.UserHomeDir()
+	//Parse the global options that come before the subcommand
+	opts, args, parseErr := subcommands.ParseGlobalOptions(os.Args[1:])
 
-	if err != nil {
-		return 1, err
-	}
+	subcommand := ""
 
-	configLoc = configLoc + "/.packageless/config.hcl"
+	if len(args) > 0 {
+		subcommand = args[0]
+	}
 
-	configBody, err := util.GetHCLBody(configLoc)
+	//The options parsed before an invalid one are used so that the error is output in the requested format
+	output, err := utils.NewOutput(opts.Output, subcommand, opts.Quiet, opts.NoColor)
 
 	if err != nil {
-		return 1, err
+		return 1, utils.WithErrorCode(utils.ErrorCodeInvalidArguments, err)
 	}
 
-	//Parse the config file
-	parseOut, err := util.ParseBody(configBody, utils.Config{})
+	util.Output = output
 
-	if err != nil {
-		return 1, err
+	if parseErr != nil {
+		return 1, utils.WithErrorCode(utils.ErrorCodeInvalidArguments, parseErr)
+	}
+
+	util.Verbose = opts.Verbose
+
+	//Config file location
+	configLoc := opts.ConfigPath
+
+	if configLoc == "" {
+		configLoc, err = os.UserHomeDir()
+
+		if err != nil {
+			return 1, err
+		}
+
+		configLoc = configLoc + "/.packageless/config.hcl"
+	}
+
+	config := utils.DefaultConfig()
+
+	//The help of a subcommand is shown without loading the config, the subcommands are only created to parse the arguments
+	helpOnly := subcommands.HelpRequested(args, newRunners(util, cp, config, configLoc, opts))
+
+	if !configFree[subcommand] && !helpOnly {
+		if util.FileExists(configLoc) {
+			util.Verbosef("loading the configuration file %s", configLoc)
+
+			config, err = loadConfig(util, configLoc)
+
+			if err != nil {
+				if !configLenient[subcommand] {
+					return 1, utils.WithErrorCode(utils.ErrorCodeInvalidConfig, err)
+				}
+
+				config = utils.DefaultConfig()
+			}
+		} else if opts.ConfigPath != "" && !configLenient[subcommand] {
+			//A configuration file that was explicitly passed has to exist
+			return 1, utils.WithErrorCode(utils.ErrorCodeInvalidConfig, fmt.Errorf("The configuration file %s does not exist", configLoc))
+		} else if !configLenient[subcommand] && !opts.Quiet {
+			warnString := "# WARNING\n" + "**No configuration file found at** *%s*\n\nUsing the default configuration. Run *packageless init* to create the configuration file.\n"
+			util.Emit(utils.Event{
+				Type:     utils.EventWarning,
+				Message:  "No configuration file found at " + configLoc + ", using the default configuration. Run packageless init to create the configuration file.",
+				Markdown: fmt.Sprintf(warnString, configLoc),
+			})
+		}
 	}
 
-	config := parseOut.(utils.Config)
+	opts.Apply(&config)
 
 	if strings.Contains(config.BaseDir, "~") {
 		homeDir, err := os.UserHomeDir()
@@ -59,20 +124,89 @@ func wrappedMain() (int, error) {
 		config.BaseDir = strings.Replace(config.BaseDir, "~", homeDir, 1)
 	}
 
+	util.Verbosef("using the base directory %s", config.BaseDir)
+
+	scmds := newRunners(util, cp, config, configLoc, opts)
+
+	//Run the subcommands
+	if err := subcommands.SubCommand(args, scmds, util); err != nil {
+		//Programs ran by packageless, such as plugins, report their own errors so only their exit code is used
+		var exitErr *subcommands.ExitError
+
+		if errors.As(err, &exitErr) {
+			return exitErr.Code, nil
+		}
+
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+//newRunners - Creates the list of subcommands with the config they are run with
+func newRunners(util *utils.Utility, cp utils.Copier, config utils.Config, configLoc string, opts subcommands.GlobalOptions) []subcommands.Runner {
+	//Create the subcommands that need to be given the list of subcommands
+	help := subcommands.NewHelpCommand(util)
+	completion := subcommands.NewCompletionCommand(util)
+	complete := subcommands.NewCompleteCommand(util, config)
+
+	configCmd := subcommands.NewConfigCommand(util, configLoc)
+
+	if opts.BaseDir != "" {
+		configCmd.Override("base_dir", config.BaseDir, "--base-dir")
+	}
+
 	//Create the list of subcommands
 	scmds := []subcommands.Runner{
 		subcommands.NewInstallCommand(util, cp, config),
 		subcommands.NewUninstallCommand(util, config),
 		subcommands.NewUpgradeCommand(util, cp, config),
+		subcommands.NewRollbackCommand(util, config),
+		subcommands.NewPinCommand(util, config),
+		subcommands.NewUnpinCommand(util, config),
 		subcommands.NewRunCommand(util, config),
+		subcommands.NewWhichCommand(util, config),
+		subcommands.NewPsCommand(util),
+		subcommands.NewStopCommand(util),
 		subcommands.NewVersionCommand(util),
 		subcommands.NewUpdateCommand(util, config),
+		subcommands.NewPruneCommand(util, config),
+		subcommands.NewListCommand(util, config),
+		subcommands.NewOutdatedCommand(util, config),
+		subcommands.NewExportCommand(util, config),
+		subcommands.NewSyncCommand(util, cp, config),
+		subcommands.NewSearchCommand(util, config),
+		subcommands.NewInfoCommand(util, config),
+		subcommands.NewDoctorCommand(util, config, configLoc),
+		subcommands.NewInitCommand(util, configLoc),
+		subcommands.NewPluginsCommand(util, config, configLoc, opts),
+		configCmd,
+		help,
+		completion,
+		complete,
 	}
 
-	//Run the subcommands
-	if err := subcommands.SubCommand(os.Args[1:], scmds); err != nil {
-		return 1, err
+	help.SetRunners(scmds)
+	completion.SetRunners(scmds)
+	complete.SetRunners(scmds)
+
+	return scmds
+}
+
+//loadConfig - Parses the config file at the given location
+func loadConfig(util *utils.Utility, configLoc string) (utils.Config, error) {
+	configBody, err := util.GetHCLBody(configLoc)
+
+	if err != nil {
+		return utils.Config{}, err
 	}
 
-	return 0, nil
+	//Parse the config file
+	parseOut, err := util.ParseBody(configBody, utils.Config{})
+
+	if err != nil {
+		return utils.Config{}, err
+	}
+
+	return parseOut.(utils.Config), nil
 }
@@ -182,7 +182,7 @@ func TestMain_OsArgs(t *testing.T) {
 			os.Args = tc.Args
 
 			//Run the main function
-			exit, err := wrappedMain()
+			exit, err := wrappedMain(utils.NewUtility())
 
 			if (exit != 0) != tc.Err {
 				t.Fatalf("Fail - Exit code did not match the expected | Received Error: %s", err)
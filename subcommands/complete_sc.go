@@ -0,0 +1,333 @@
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Complete Sub-Command Object
+//The completion scripts call this hidden subcommand to complete the arguments of the command line
+type CompleteCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Words of the command line after packageless, the last one being the word to complete
+	words []string
+
+	//Subcommands that can be completed
+	runners []Runner
+
+	tools utils.Tools
+
+	config utils.Config
+
+	//Writer that the completion candidates are written to
+	out io.Writer
+}
+
+//Sources that the pim arguments of a subcommand can be completed from
+const (
+	installedPims  = "installed"
+	repositoryPims = "repository"
+)
+
+//argCompletion - How the arguments of a subcommand are completed
+type argCompletion struct {
+	//Source of the pims to complete
+	source string
+
+	//Only the first argument is a pim, the following ones are not completed
+	single bool
+}
+
+//argCompletions - How the arguments of each subcommand that takes pims are completed
+var argCompletions = map[string]argCompletion{
+	"install":   {source: repositoryPims},
+	"info":      {source: repositoryPims, single: true},
+	"uninstall": {source: installedPims},
+	"upgrade":   {source: installedPims},
+	"run":       {source: installedPims, single: true},
+	"stop":      {source: installedPims},
+	"pin":       {source: installedPims},
+	"unpin":     {source: installedPims},
+	"rollback":  {source: installedPims},
+	"which":     {source: installedPims, single: true},
+}
+
+//Instantiation method for a new CompleteCommand
+func NewCompleteCommand(tools utils.Tools, config utils.Config) *CompleteCommand {
+	//Create a new CompleteCommand and set the FlagSet
+	cc := &CompleteCommand{
+		fs:     flag.NewFlagSet("__complete", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+		out:    os.Stdout,
+	}
+
+	return cc
+}
+
+//SetRunners - Sets the subcommands that can be completed
+func (cc *CompleteCommand) SetRunners(runners []Runner) {
+	cc.runners = runners
+}
+
+//Name - Gets the name of the Sub-Command
+func (cc *CompleteCommand) Name() string {
+	return cc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (cc *CompleteCommand) Description() string {
+	return "Print the completion candidates for a command line"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (cc *CompleteCommand) Usage() string {
+	return "packageless __complete [WORDS]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (cc *CompleteCommand) Flags() *flag.FlagSet {
+	return cc.fs
+}
+
+//Hidden - The Complete subcommand is only used by the completion scripts
+func (cc *CompleteCommand) Hidden() bool {
+	return true
+}
+
+//Init - Populates values of the Complete subcommand
+func (cc *CompleteCommand) Init(args []string) error {
+	//The arguments are the words of the command line being completed so they are not parsed as flags
+	cc.words = args
+
+	//There is always a word to complete, even if it is empty
+	if len(cc.words) == 0 {
+		cc.words = []string{""}
+	}
+
+	return nil
+}
+
+//Run - Runs the Complete subcommand
+func (cc *CompleteCommand) Run() error {
+	toComplete := cc.words[len(cc.words)-1]
+
+	candidates, err := cc.candidates(cc.words[:len(cc.words)-1], toComplete)
+
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range filterCandidates(candidates, toComplete) {
+		fmt.Fprintln(cc.out, candidate)
+	}
+
+	return nil
+}
+
+//candidates - Gets all the values that the word after the preceding words could be completed to
+func (cc *CompleteCommand) candidates(preceding []string, toComplete string) ([]string, error) {
+	var candidates []string
+
+	//The global options come before the subcommand
+	preceding, valueExpected := skipGlobalOptions(preceding)
+
+	//The values of the global options are paths so they are left to the shell
+	if valueExpected {
+		return nil, nil
+	}
+
+	//Complete the global options
+	if len(preceding) == 0 && strings.HasPrefix(toComplete, "-") {
+		for _, f := range globalCompletionFlags() {
+			candidates = append(candidates, f.name)
+		}
+
+		return candidates, nil
+	}
+
+	//Complete the subcommand itself
+	if len(preceding) == 0 {
+		for _, cmd := range visibleRunners(cc.runners) {
+			candidates = append(candidates, cmd.Name())
+		}
+
+		for _, plugin := range findPlugins(cc.runners) {
+			candidates = append(candidates, plugin.Name())
+		}
+
+		return candidates, nil
+	}
+
+	var cmd Runner
+
+	for _, runner := range cc.runners {
+		if runner.Name() == preceding[0] {
+			cmd = runner
+		}
+	}
+
+	if cmd == nil {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(toComplete, "-") {
+		for _, f := range completionFlags(cmd) {
+			candidates = append(candidates, f.name)
+		}
+
+		return candidates, nil
+	}
+
+	//Count the positional arguments that have already been passed to the subcommand
+	positional := 0
+
+	for _, word := range preceding[1:] {
+		if !strings.HasPrefix(word, "-") {
+			positional++
+		}
+	}
+
+	switch cmd.Name() {
+	case "help":
+		if positional == 0 {
+			for _, runner := range visibleRunners(cc.runners) {
+				candidates = append(candidates, runner.Name())
+			}
+		}
+
+		return candidates, nil
+	case "completion":
+		if positional == 0 {
+			candidates = append(candidates, completionShells...)
+		}
+
+		return candidates, nil
+	}
+
+	completion, ok := argCompletions[cmd.Name()]
+
+	if !ok || (completion.single && positional > 0) {
+		return nil, nil
+	}
+
+	if completion.source == repositoryPims {
+		return cc.repositoryPims()
+	}
+
+	return cc.installedPims()
+}
+
+//installedPims - Gets the names of the installed pims along with the name:version of each of their versions
+func (cc *CompleteCommand) installedPims() ([]string, error) {
+	var candidates []string
+
+	pimConfigDir := cc.config.BaseDir + cc.config.PimsConfigDir
+
+	pimNames, err := cc.tools.GetListOfInstalledPimConfigs(pimConfigDir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pimName := range pimNames {
+		pimListBody, err := cc.tools.GetHCLBody(pimConfigDir + pimName + ".hcl")
+
+		if err != nil {
+			return nil, err
+		}
+
+		//Parse the pim list
+		parseOut, err := cc.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		pims := parseOut.(utils.PimHCLUtil)
+
+		for _, pim := range pims.Pims {
+			candidates = append(candidates, pim.Name)
+
+			for _, ver := range pim.Versions {
+				candidates = append(candidates, pim.Name+":"+ver.Version)
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+//repositoryPims - Gets the names of the pims in the repository index along with the name:version of each of their versions
+func (cc *CompleteCommand) repositoryPims() ([]string, error) {
+	var candidates []string
+
+	indexPath := cc.config.BaseDir + utils.IndexFile
+
+	//Fetch the repository index if it has not been cached by the update subcommand yet
+	if !cc.tools.FileExists(indexPath) {
+		err := cc.tools.MakeDir(cc.config.BaseDir)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = cc.tools.FetchIndex(cc.config.RepositoryHost, cc.config.BaseDir)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	indexBody, err := cc.tools.GetHCLBody(indexPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	//Parse the repository index
+	parseOut, err := cc.tools.ParseBody(indexBody, utils.RepositoryIndex{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	index := parseOut.(utils.RepositoryIndex)
+
+	for _, pim := range index.Pims {
+		candidates = append(candidates, pim.Name)
+
+		for _, version := range pim.Versions {
+			candidates = append(candidates, pim.Name+":"+version)
+		}
+	}
+
+	return candidates, nil
+}
+
+//filterCandidates - Gets the sorted unique candidates that start with the word being completed
+func filterCandidates(candidates []string, toComplete string) []string {
+	var filtered []string
+	seen := make(map[string]bool)
+
+	for _, candidate := range candidates {
+		if seen[candidate] || !strings.HasPrefix(candidate, toComplete) {
+			continue
+		}
+
+		seen[candidate] = true
+		filtered = append(filtered, candidate)
+	}
+
+	sort.Strings(filtered)
+
+	return filtered
+}
@@ -0,0 +1,180 @@
+package subcommands
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Run the complete subcommand for the words and return the completion candidates
+func runComplete(t *testing.T, mu *utils.MockUtility, words []string) []string {
+	scmds := testCompletionRunners(mu)
+	cc := scmds[4].(*CompleteCommand)
+
+	out := &bytes.Buffer{}
+	cc.out = out
+
+	err := cc.Init(words)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Fields(out.String())
+}
+
+//Test to make sure the complete subcommand has the proper name upon creation and is hidden
+func TestCompleteName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	cc := NewCompleteCommand(mu, mu.Conf)
+
+	if cc.Name() != "__complete" {
+		t.Fatal("The complete subcommand's name should be: __complete | Subcommand Name: " + cc.Name())
+	}
+
+	if !cc.Hidden() {
+		t.Fatal("The complete subcommand should be hidden")
+	}
+
+	if strings.Contains(renderUsage([]Runner{cc}), "__complete") {
+		t.Fatal("The complete subcommand should not be listed in the usage")
+	}
+}
+
+//Test the completion of the subcommand names
+func TestCompleteSubcommands(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	candidates := runComplete(t, mu, []string{""})
+	expected := []string{"completion", "install", "list", "run"}
+
+	if !reflect.DeepEqual(expected, candidates) {
+		t.Fatalf("Expected candidates: %v | Received: %v", expected, candidates)
+	}
+
+	candidates = runComplete(t, mu, []string{"i"})
+	expected = []string{"install"}
+
+	if !reflect.DeepEqual(expected, candidates) {
+		t.Fatalf("Expected candidates: %v | Received: %v", expected, candidates)
+	}
+}
+
+//Test the completion of the flags of a subcommand
+func TestCompleteFlags(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	candidates := runComplete(t, mu, []string{"list", "--"})
+	expected := []string{"--all", "--help"}
+
+	if !reflect.DeepEqual(expected, candidates) {
+		t.Fatalf("Expected candidates: %v | Received: %v", expected, candidates)
+	}
+}
+
+//Test the completion of the installed pims
+func TestCompleteInstalledPims(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.InstalledPims = []string{"python"}
+
+	candidates := runComplete(t, mu, []string{"run", "py"})
+	expected := []string{"python", "python:latest"}
+
+	if !reflect.DeepEqual(expected, candidates) {
+		t.Fatalf("Expected candidates: %v | Received: %v", expected, candidates)
+	}
+
+	//Only the first argument of run is a pim
+	candidates = runComplete(t, mu, []string{"run", "python", ""})
+
+	if len(candidates) != 0 {
+		t.Fatalf("Expected no candidates but received: %v", candidates)
+	}
+}
+
+//Test the completion of the pims in the repository index
+func TestCompleteRepositoryPims(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.Index = testIndex()
+
+	candidates := runComplete(t, mu, []string{"install", "node", "py"})
+	expected := []string{"pypy", "pypy:latest", "python", "python:3.9", "python:latest"}
+
+	if !reflect.DeepEqual(expected, candidates) {
+		t.Fatalf("Expected candidates: %v | Received: %v", expected, candidates)
+	}
+
+	callStack := []string{"FileExists", "GetHCLBody", "ParseBody"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	//The repository index should be fetched when it has not been cached
+	mu.Calls = nil
+	mu.PimConfigShouldExist = false
+
+	runComplete(t, mu, []string{"install", ""})
+
+	callStack = []string{"FileExists", "MakeDir", "FetchIndex", "GetHCLBody", "ParseBody"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+}
+
+//Test the completion of the shells of the completion subcommand
+func TestCompleteShells(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	candidates := runComplete(t, mu, []string{"completion", ""})
+
+	if !reflect.DeepEqual(completionShells, candidates) {
+		t.Fatalf("Expected candidates: %v | Received: %v", completionShells, candidates)
+	}
+}
+
+//Test the global options before the subcommand are completed and skipped
+func TestCompleteGlobalOptions(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	candidates := runComplete(t, mu, []string{"--c"})
+	expected := []string{"--config"}
+
+	if !reflect.DeepEqual(expected, candidates) {
+		t.Fatalf("Expected candidates: %v | Received: %v", expected, candidates)
+	}
+
+	//The value of an option is not completed
+	candidates = runComplete(t, mu, []string{"--config", ""})
+
+	if len(candidates) != 0 {
+		t.Fatalf("Expected no candidates but received: %v", candidates)
+	}
+
+	candidates = runComplete(t, mu, []string{"--config", "config.hcl", "--quiet", "l"})
+	expected = []string{"list"}
+
+	if !reflect.DeepEqual(expected, candidates) {
+		t.Fatalf("Expected candidates: %v | Received: %v", expected, candidates)
+	}
+
+	candidates = runComplete(t, mu, []string{"--base-dir=/tmp/", "list", "--a"})
+	expected = []string{"--all"}
+
+	if !reflect.DeepEqual(expected, candidates) {
+		t.Fatalf("Expected candidates: %v | Received: %v", expected, candidates)
+	}
+}
@@ -0,0 +1,312 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Completion Sub-Command Object
+type CompletionCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Shell to generate the completion script for
+	shell string
+
+	//Subcommands that the completion script is generated from
+	runners []Runner
+
+	tools utils.Tools
+
+	//Writer that the completion script is written to
+	out io.Writer
+}
+
+//Shells that completion scripts can be generated for
+var completionShells = []string{"bash", "fish", "zsh"}
+
+//Instantiation method for a new CompletionCommand
+func NewCompletionCommand(tools utils.Tools) *CompletionCommand {
+	//Create a new CompletionCommand and set the FlagSet
+	cc := &CompletionCommand{
+		fs:    flag.NewFlagSet("completion", flag.ContinueOnError),
+		tools: tools,
+		out:   os.Stdout,
+	}
+
+	return cc
+}
+
+//SetRunners - Sets the subcommands that the completion script is generated from
+func (cc *CompletionCommand) SetRunners(runners []Runner) {
+	cc.runners = runners
+}
+
+//Name - Gets the name of the Sub-Command
+func (cc *CompletionCommand) Name() string {
+	return cc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (cc *CompletionCommand) Description() string {
+	return "Generate the completion script for bash, zsh or fish"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (cc *CompletionCommand) Usage() string {
+	return "packageless completion [bash|zsh|fish]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (cc *CompletionCommand) Flags() *flag.FlagSet {
+	return cc.fs
+}
+
+//Init - Parses and Populates values of the Completion subcommand
+func (cc *CompletionCommand) Init(args []string) error {
+	err := cc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = cc.fs.Args()
+
+	if len(args) <= 0 {
+		return errors.New("No shell was found. You must include the shell you wish to generate the completion script for: " + strings.Join(completionShells, ", "))
+	}
+
+	cc.shell = args[0]
+
+	return nil
+}
+
+//Run - Runs the Completion subcommand
+func (cc *CompletionCommand) Run() error {
+	var script string
+
+	switch cc.shell {
+	case "bash":
+		script = bashCompletion(cc.runners)
+	case "zsh":
+		script = zshCompletion(cc.runners)
+	case "fish":
+		script = fishCompletion(cc.runners)
+	default:
+		return fmt.Errorf("Shell %s is currently unsupported. Supported shells: %s", cc.shell, strings.Join(completionShells, ", "))
+	}
+
+	_, err := io.WriteString(cc.out, script)
+
+	return err
+}
+
+//completionFlag - A flag of a subcommand that can be completed
+type completionFlag struct {
+	name        string
+	description string
+
+	//The flag takes a value, these are only completed for the global options
+	takesValue bool
+}
+
+//globalCompletionFlags - Gets the global options that can be completed before the subcommand
+func globalCompletionFlags() []completionFlag {
+	var flags []completionFlag
+
+	globalFlags(&GlobalOptions{}).VisitAll(func(f *flag.Flag) {
+		valueName, usage := flag.UnquoteUsage(f)
+		flags = append(flags, completionFlag{name: "--" + f.Name, description: usage, takesValue: valueName != ""})
+	})
+
+	return flags
+}
+
+//completionFlags - Gets the flags of a subcommand that can be completed including the ones handled by SubCommand
+func completionFlags(cmd Runner) []completionFlag {
+	var flags []completionFlag
+
+	cmd.Flags().VisitAll(func(f *flag.Flag) {
+		_, usage := flag.UnquoteUsage(f)
+		flags = append(flags, completionFlag{name: "--" + f.Name, description: usage})
+	})
+
+	if batcher, ok := cmd.(Batcher); ok && batcher.Batch() {
+		flags = append(flags, completionFlag{name: "--fail-fast", description: "stop processing pims as soon as one of them fails"})
+	}
+
+	flags = append(flags, completionFlag{name: "--help", description: "show the usage of the subcommand"})
+
+	sort.SliceStable(flags, func(i, j int) bool {
+		return flags[i].name < flags[j].name
+	})
+
+	return flags
+}
+
+//bashCompletion - Generates the bash completion script
+func bashCompletion(scmds []Runner) string {
+	var sb strings.Builder
+	var names []string
+	var globals []string
+
+	for _, cmd := range visibleRunners(scmds) {
+		names = append(names, cmd.Name())
+	}
+
+	for _, f := range globalCompletionFlags() {
+		globals = append(globals, f.name)
+	}
+
+	sb.WriteString("# bash completion for packageless\n")
+	sb.WriteString("# Load it by adding the following line to ~/.bashrc:\n")
+	sb.WriteString("#   source <(packageless completion bash)\n\n")
+	sb.WriteString("# pim versions are separated by a colon so it can not be a word break\n")
+	sb.WriteString("COMP_WORDBREAKS=${COMP_WORDBREAKS//:}\n\n")
+	sb.WriteString("_packageless() {\n")
+	sb.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n\n")
+	sb.WriteString("    if [[ ${COMP_CWORD} -eq 1 ]]; then\n")
+	sb.WriteString("        if [[ ${cur} == -* ]]; then\n")
+	sb.WriteString(fmt.Sprintf("            COMPREPLY=($(compgen -W \"%s\" -- \"${cur}\"))\n", strings.Join(globals, " ")))
+	sb.WriteString("        else\n")
+	sb.WriteString(fmt.Sprintf("            COMPREPLY=($(compgen -W \"%s\" -- \"${cur}\"))\n", strings.Join(names, " ")))
+	sb.WriteString("        fi\n")
+	sb.WriteString("        return\n")
+	sb.WriteString("    fi\n\n")
+	sb.WriteString("    if [[ ${cur} == -* ]]; then\n")
+	sb.WriteString("        case \"${COMP_WORDS[1]}\" in\n")
+
+	for _, cmd := range visibleRunners(scmds) {
+		var flags []string
+
+		for _, f := range completionFlags(cmd) {
+			flags = append(flags, f.name)
+		}
+
+		sb.WriteString(fmt.Sprintf("            %s)\n", cmd.Name()))
+		sb.WriteString(fmt.Sprintf("                COMPREPLY=($(compgen -W \"%s\" -- \"${cur}\"))\n", strings.Join(flags, " ")))
+		sb.WriteString("                ;;\n")
+	}
+
+	sb.WriteString("        esac\n")
+	sb.WriteString("        return\n")
+	sb.WriteString("    fi\n\n")
+	sb.WriteString("    COMPREPLY=($(compgen -W \"$(packageless __complete \"${COMP_WORDS[@]:1:COMP_CWORD}\" 2>/dev/null)\" -- \"${cur}\"))\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("complete -F _packageless packageless\n")
+
+	return sb.String()
+}
+
+//zshCompletion - Generates the zsh completion script
+func zshCompletion(scmds []Runner) string {
+	var sb strings.Builder
+
+	sb.WriteString("#compdef packageless\n")
+	sb.WriteString("# zsh completion for packageless\n")
+	sb.WriteString("# Load it by adding the following line to ~/.zshrc:\n")
+	sb.WriteString("#   source <(packageless completion zsh)\n\n")
+	sb.WriteString("_packageless() {\n")
+	sb.WriteString("    local -a candidates\n\n")
+	sb.WriteString("    if (( CURRENT == 2 )) && [[ ${words[CURRENT]} == -* ]]; then\n")
+	sb.WriteString("        candidates=(\n")
+
+	for _, f := range globalCompletionFlags() {
+		sb.WriteString(fmt.Sprintf("            '%s:%s'\n", f.name, zshEscape(f.description)))
+	}
+
+	sb.WriteString("        )\n")
+	sb.WriteString("        _describe 'global option' candidates\n")
+	sb.WriteString("        return\n")
+	sb.WriteString("    fi\n\n")
+	sb.WriteString("    if (( CURRENT == 2 )); then\n")
+	sb.WriteString("        candidates=(\n")
+
+	for _, cmd := range visibleRunners(scmds) {
+		sb.WriteString(fmt.Sprintf("            '%s:%s'\n", cmd.Name(), zshEscape(cmd.Description())))
+	}
+
+	sb.WriteString("        )\n")
+	sb.WriteString("        _describe 'subcommand' candidates\n")
+	sb.WriteString("        return\n")
+	sb.WriteString("    fi\n\n")
+	sb.WriteString("    if [[ ${words[CURRENT]} == -* ]]; then\n")
+	sb.WriteString("        case \"${words[2]}\" in\n")
+
+	for _, cmd := range visibleRunners(scmds) {
+		sb.WriteString(fmt.Sprintf("            %s)\n", cmd.Name()))
+		sb.WriteString("                candidates=(\n")
+
+		for _, f := range completionFlags(cmd) {
+			sb.WriteString(fmt.Sprintf("                    '%s:%s'\n", f.name, zshEscape(f.description)))
+		}
+
+		sb.WriteString("                )\n")
+		sb.WriteString("                ;;\n")
+	}
+
+	sb.WriteString("        esac\n")
+	sb.WriteString("        _describe 'option' candidates\n")
+	sb.WriteString("        return\n")
+	sb.WriteString("    fi\n\n")
+	sb.WriteString("    candidates=(${(f)\"$(packageless __complete \"${(@)words[2,CURRENT]}\" 2>/dev/null)\"})\n")
+	sb.WriteString("    compadd -a candidates\n")
+	sb.WriteString("}\n\n")
+	sb.WriteString("compdef _packageless packageless\n")
+
+	return sb.String()
+}
+
+//fishCompletion - Generates the fish completion script
+func fishCompletion(scmds []Runner) string {
+	var sb strings.Builder
+
+	sb.WriteString("# fish completion for packageless\n")
+	sb.WriteString("# Load it by running:\n")
+	sb.WriteString("#   packageless completion fish > ~/.config/fish/completions/packageless.fish\n\n")
+	sb.WriteString("complete -c packageless -f\n")
+
+	for _, cmd := range visibleRunners(scmds) {
+		sb.WriteString(fmt.Sprintf("complete -c packageless -n '__fish_use_subcommand' -a %s -d '%s'\n", cmd.Name(), fishEscape(cmd.Description())))
+	}
+
+	//Options that take a value are completed with a path
+	for _, f := range globalCompletionFlags() {
+		value := ""
+
+		if f.takesValue {
+			value = " -r -F"
+		}
+
+		sb.WriteString(fmt.Sprintf("complete -c packageless -n '__fish_use_subcommand' -l %s%s -d '%s'\n", strings.TrimPrefix(f.name, "--"), value, fishEscape(f.description)))
+	}
+
+	for _, cmd := range visibleRunners(scmds) {
+		for _, f := range completionFlags(cmd) {
+			sb.WriteString(fmt.Sprintf("complete -c packageless -n '__fish_seen_subcommand_from %s' -l %s -d '%s'\n", cmd.Name(), strings.TrimPrefix(f.name, "--"), fishEscape(f.description)))
+		}
+	}
+
+	sb.WriteString("complete -c packageless -n 'not __fish_use_subcommand; and not string match -q -- \"-*\" (commandline -ct)' -a '(packageless __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'\n")
+
+	return sb.String()
+}
+
+//zshEscape - Escapes a description so that it can be used in a single quoted zsh _describe candidate
+func zshEscape(description string) string {
+	description = strings.ReplaceAll(description, ":", "\\:")
+	return strings.ReplaceAll(description, "'", "'\\''")
+}
+
+//fishEscape - Escapes a description so that it can be used in a single quoted fish string
+func fishEscape(description string) string {
+	description = strings.ReplaceAll(description, "\\", "\\\\")
+	return strings.ReplaceAll(description, "'", "\\'")
+}
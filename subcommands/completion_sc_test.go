@@ -0,0 +1,143 @@
+package subcommands
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Regenerate the golden completion scripts with: go test ./subcommands -run TestCompletionGolden -update
+var update = flag.Bool("update", false, "update the golden files")
+
+//Create the list of subcommands that the golden completion scripts are generated from
+func testCompletionRunners(mu *utils.MockUtility) []Runner {
+	completion := NewCompletionCommand(mu)
+	complete := NewCompleteCommand(mu, mu.Conf)
+
+	scmds := []Runner{
+		NewInstallCommand(mu, &utils.MockCopyTool{}, mu.Conf),
+		NewListCommand(mu, mu.Conf),
+		NewRunCommand(mu, mu.Conf),
+		completion,
+		complete,
+	}
+
+	completion.SetRunners(scmds)
+	complete.SetRunners(scmds)
+
+	return scmds
+}
+
+//Test to make sure the completion subcommand has the proper name upon creation
+func TestCompletionName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	cc := NewCompletionCommand(mu)
+
+	if cc.Name() != "completion" {
+		t.Fatal("The completion subcommand's name should be: completion | Subcommand Name: " + cc.Name())
+	}
+}
+
+//Test to make sure the completion subcommand initializes correctly
+func TestCompletionInit(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	cc := NewCompletionCommand(mu)
+
+	err := cc.Init([]string{})
+
+	if err == nil {
+		t.Fatal("Expected an error when no shell is passed but did not receive one")
+	}
+
+	err = cc.Init([]string{"bash"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cc.shell != "bash" {
+		t.Fatal("Shell should have been initialized as: bash but is: " + cc.shell)
+	}
+}
+
+//Test the completion subcommand with an unsupported shell
+func TestCompletionUnsupportedShell(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	cc := NewCompletionCommand(mu)
+	cc.out = &bytes.Buffer{}
+
+	err := cc.Init([]string{"powershell"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	exErr := "Shell powershell is currently unsupported. Supported shells: bash, fish, zsh"
+
+	if err == nil {
+		t.Fatalf("Expected to have error: %s | Received No Error", exErr)
+	}
+
+	if err.Error() != exErr {
+		t.Fatalf("Expected to have error: %s | Received: %s", exErr, err.Error())
+	}
+}
+
+//Test the generated completion scripts match the golden files
+func TestCompletionGolden(t *testing.T) {
+	for _, shell := range completionShells {
+		mu := utils.NewMockUtility()
+
+		scmds := testCompletionRunners(mu)
+		cc := scmds[3].(*CompletionCommand)
+
+		out := &bytes.Buffer{}
+		cc.out = out
+
+		err := cc.Init([]string{shell})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = cc.Run()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		golden := filepath.Join("testdata", "completion."+shell)
+
+		if *update {
+			err = ioutil.WriteFile(golden, out.Bytes(), 0644)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		expected, err := ioutil.ReadFile(golden)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(expected, out.Bytes()) {
+			t.Fatalf("The %s completion script does not match %s. Received:\n%s", shell, golden, out.String())
+		}
+
+		//The completion scripts should not call any of the utilities
+		if len(mu.Calls) != 0 {
+			t.Fatalf("Expected no calls to be made but received: %v", mu.Calls)
+		}
+	}
+}
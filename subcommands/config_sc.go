@@ -0,0 +1,378 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+//Config Sub-Command Object
+type ConfigCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//The action to take on the configuration: get, set, list or edit
+	action string
+
+	//The arguments of the action
+	args []string
+
+	//Path to the configuration file
+	configPath string
+
+	//Values overridden by global options, they take precedence over the configuration file
+	overrides map[string]configOverride
+
+	tools utils.Tools
+}
+
+//configOverride - A configuration value overridden outside of the configuration file
+type configOverride struct {
+	value string
+
+	//Where the value came from
+	source string
+}
+
+//ConfigEntry - The effective value of a configuration value and where it came from
+type ConfigEntry struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+//Actions that the config subcommand supports
+var configActions = []string{"get", "set", "list", "edit"}
+
+//Instantiation method for a new ConfigCommand
+func NewConfigCommand(tools utils.Tools, configPath string) *ConfigCommand {
+	//Create a new ConfigCommand and set the FlagSet
+	cc := &ConfigCommand{
+		fs:         flag.NewFlagSet("config", flag.ContinueOnError),
+		configPath: configPath,
+		tools:      tools,
+	}
+
+	return cc
+}
+
+//Override - Overrides a configuration value, the source describing where the value came from
+func (cc *ConfigCommand) Override(name string, value string, source string) {
+	if cc.overrides == nil {
+		cc.overrides = make(map[string]configOverride)
+	}
+
+	cc.overrides[name] = configOverride{value: value, source: source}
+}
+
+//Name - Gets the name of the Sub-Command
+func (cc *ConfigCommand) Name() string {
+	return cc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (cc *ConfigCommand) Description() string {
+	return "Get, set, list or edit the configuration values"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (cc *ConfigCommand) Usage() string {
+	return "packageless config [get NAME | set NAME VALUE | list | edit]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (cc *ConfigCommand) Flags() *flag.FlagSet {
+	return cc.fs
+}
+
+//Init - Parses and Populates values of the Config subcommand
+func (cc *ConfigCommand) Init(args []string) error {
+	err := cc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = cc.fs.Args()
+
+	if len(args) <= 0 {
+		return errors.New("No action was found. You must include the action you wish to take: " + strings.Join(configActions, ", "))
+	}
+
+	cc.action = args[0]
+	cc.args = args[1:]
+
+	expectedArgs := map[string]int{"get": 1, "set": 2, "list": 0, "edit": 0}
+
+	count, ok := expectedArgs[cc.action]
+
+	if !ok {
+		return fmt.Errorf("Unknown config action %s. Supported actions: %s", cc.action, strings.Join(configActions, ", "))
+	}
+
+	if len(cc.args) != count {
+		return fmt.Errorf("Wrong number of arguments for config %s. Usage: %s", cc.action, cc.Usage())
+	}
+
+	return nil
+}
+
+//Run - Runs the Config subcommand
+func (cc *ConfigCommand) Run() error {
+	switch cc.action {
+	case "get":
+		return cc.get(cc.args[0])
+	case "set":
+		return cc.set(cc.args[0], cc.args[1])
+	case "list":
+		return cc.list()
+	case "edit":
+		return cc.edit()
+	}
+
+	return nil
+}
+
+//load - Loads the effective configuration along with the location in the configuration file of the values set in it
+func (cc *ConfigCommand) load() (utils.Config, map[string]string, error) {
+	config := utils.DefaultConfig()
+	sources := make(map[string]string)
+
+	if cc.tools.FileExists(cc.configPath) {
+		content, err := cc.tools.ReadFile(cc.configPath)
+
+		if err != nil {
+			return utils.Config{}, nil, err
+		}
+
+		var diags hcl.Diagnostics
+
+		config, diags = utils.LoadConfigHCL([]byte(content), cc.configPath)
+
+		if diags.HasErrors() {
+			return utils.Config{}, nil, errors.New("The configuration file is invalid, fix it with packageless config edit: " + utils.FormatDiagnostics(diags))
+		}
+
+		for name, rng := range utils.ConfigLocations([]byte(content), cc.configPath) {
+			sources[name] = fmt.Sprintf("%s:%d", rng.Filename, rng.Start.Line)
+		}
+	}
+
+	for name, override := range cc.overrides {
+		err := utils.SetConfigValue(&config, name, override.value)
+
+		if err != nil {
+			return utils.Config{}, nil, err
+		}
+
+		sources[name] = override.source
+	}
+
+	return config, sources, nil
+}
+
+//get - Outputs the effective value of a configuration value
+func (cc *ConfigCommand) get(name string) error {
+	if _, ok := utils.FindConfigField(name); !ok {
+		return errors.New("Unknown configuration value: " + name)
+	}
+
+	config, _, err := cc.load()
+
+	if err != nil {
+		return err
+	}
+
+	value, err := utils.GetConfigValue(config, name)
+
+	if err != nil {
+		return err
+	}
+
+	//The value is shown as is so that it can be used by scripts
+	cc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  value,
+		Data:     value,
+		Markdown: value,
+	})
+
+	return nil
+}
+
+//list - Shows every effective configuration value and where it came from
+func (cc *ConfigCommand) list() error {
+	config, sources, err := cc.load()
+
+	if err != nil {
+		return err
+	}
+
+	var entries []ConfigEntry
+
+	for _, field := range utils.ConfigFields {
+		value, err := utils.GetConfigValue(config, field.Name)
+
+		if err != nil {
+			return err
+		}
+
+		source, ok := sources[field.Name]
+
+		if !ok {
+			source = "default"
+		}
+
+		entries = append(entries, ConfigEntry{Name: field.Name, Value: value, Source: source})
+	}
+
+	cc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  configText(entries),
+		Data:     entries,
+		Markdown: renderConfig(entries),
+	})
+
+	return nil
+}
+
+//configText - Creates the plain text lines of the configuration values
+func configText(entries []ConfigEntry) string {
+	var lines []string
+
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("%s=%s (%s)", entry.Name, entry.Value, entry.Source))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+//renderConfig - Creates a markdown table of the configuration values
+func renderConfig(entries []ConfigEntry) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Configuration\n")
+	sb.WriteString("| name | value | source |\n")
+	sb.WriteString("| --- | --- | --- |\n")
+
+	for _, entry := range entries {
+		//Values are shown as code so that URLs are rendered as is
+		sb.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", entry.Name, entry.Value, entry.Source))
+	}
+
+	return sb.String()
+}
+
+//currentContent - Gets the content of the configuration file, or of the default configuration file if it does not exist
+func (cc *ConfigCommand) currentContent() (string, error) {
+	if !cc.tools.FileExists(cc.configPath) {
+		return utils.ConfigHCL(utils.DefaultConfig()), nil
+	}
+
+	return cc.tools.ReadFile(cc.configPath)
+}
+
+//write - Writes the content to the configuration file, creating its directory if needed
+func (cc *ConfigCommand) write(content string) error {
+	err := cc.tools.MakeDir(filepath.Dir(cc.configPath))
+
+	if err != nil {
+		return err
+	}
+
+	return cc.tools.WriteFile(cc.configPath, content)
+}
+
+//set - Sets a value in the configuration file, keeping the rest of the file as is
+func (cc *ConfigCommand) set(name string, value string) error {
+	content, err := cc.currentContent()
+
+	if err != nil {
+		return err
+	}
+
+	updated, err := utils.SetConfigHCLValue([]byte(content), cc.configPath, name, value)
+
+	if err != nil {
+		return err
+	}
+
+	err = cc.write(string(updated))
+
+	if err != nil {
+		return err
+	}
+
+	cc.tools.RenderInfoMarkdown(fmt.Sprintf("*Set %s to %s in %s*", name, value, cc.configPath))
+
+	return nil
+}
+
+//edit - Opens the configuration file in an editor and only saves it if it is valid
+func (cc *ConfigCommand) edit() error {
+	content, err := cc.currentContent()
+
+	if err != nil {
+		return err
+	}
+
+	//Edit a copy so that the configuration file is never left invalid
+	tempDir, err := cc.tools.TempDir("packageless-config")
+
+	if err != nil {
+		return err
+	}
+
+	tempPath := tempDir + "config.hcl"
+
+	err = cc.tools.WriteFile(tempPath, content)
+
+	if err != nil {
+		return err
+	}
+
+	err = cc.tools.OpenEditor(tempPath)
+
+	if err != nil {
+		return err
+	}
+
+	edited, err := cc.tools.ReadFile(tempPath)
+
+	if err != nil {
+		return err
+	}
+
+	_, diags := utils.LoadConfigHCL([]byte(edited), cc.configPath)
+
+	if diags.HasErrors() {
+		//Keep the edited copy so that the changes are not lost
+		return errors.New("The edited configuration is invalid so " + cc.configPath + " was not changed. The changes were kept in " + tempPath + ": " + utils.FormatDiagnostics(diags))
+	}
+
+	err = cc.tools.RemoveDir(tempDir)
+
+	if err != nil {
+		return err
+	}
+
+	if edited == content && cc.tools.FileExists(cc.configPath) {
+		cc.tools.RenderInfoMarkdown("*The configuration was not changed*")
+		return nil
+	}
+
+	err = cc.write(edited)
+
+	if err != nil {
+		return err
+	}
+
+	cc.tools.RenderInfoMarkdown(fmt.Sprintf("*Saved the configuration to %s*", cc.configPath))
+
+	return nil
+}
@@ -0,0 +1,307 @@
+package subcommands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Test to make sure the config subcommand has the proper name upon creation
+func TestConfigName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	cc := NewConfigCommand(mu, "config.hcl")
+
+	if cc.Name() != "config" {
+		t.Fatal("The config subcommand's name should be: config | Subcommand Name: " + cc.Name())
+	}
+}
+
+//Test the config subcommand rejects unknown actions and wrong numbers of arguments
+func TestConfigInitErrors(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	cc := NewConfigCommand(mu, "config.hcl")
+
+	tests := map[string][]string{
+		"No action was found. You must include the action you wish to take: get, set, list, edit": {},
+		"Unknown config action remove. Supported actions: get, set, list, edit":                   {"remove"},
+		"Wrong number of arguments for config set. Usage: " + cc.Usage():                          {"set", "alias"},
+	}
+
+	for exErr, args := range tests {
+		err := cc.Init(args)
+
+		if err == nil {
+			t.Fatalf("Expected to have error: %s | Received No Error", exErr)
+		}
+
+		if err.Error() != exErr {
+			t.Fatalf("Expected to have error: %s | Received: %s", exErr, err.Error())
+		}
+	}
+}
+
+//Tests the flow of getting a configuration value
+func TestConfigGetFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.FileContents = map[string]string{"config.hcl": "start_port = 4000\n"}
+
+	cc := NewConfigCommand(mu, "config.hcl")
+
+	err := cc.Init([]string{"get", "start_port"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FileExists", "ReadFile", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if mu.Events[0].Type != utils.EventResult || mu.Events[0].Data != "4000" {
+		t.Fatalf("Expected the value of start_port to be the result | Received: %v", mu.Events[0])
+	}
+
+	//Values that are not set in the configuration file should be the default
+	err = cc.Init([]string{"get", "pims_dir"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mu.Events[1].Data != "pims/" {
+		t.Fatalf("Expected the default value of pims_dir to be the result | Received: %v", mu.Events[1])
+	}
+}
+
+//Tests the flow of listing the configuration values
+func TestConfigListFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.PimConfigShouldExist = false
+
+	cc := NewConfigCommand(mu, "config.hcl")
+
+	err := cc.Init([]string{"list"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FileExists", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	entries := mu.Events[0].Data.([]ConfigEntry)
+
+	if len(entries) != len(utils.ConfigFields) || entries[0].Source != "default" {
+		t.Fatalf("Every configuration value should have been listed with its default source | Received: %+v", entries)
+	}
+}
+
+//Test the config subcommand reports an invalid configuration file
+func TestConfigInvalidFile(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.FileContents = map[string]string{"config.hcl": "start_port = \"abc\"\n"}
+
+	cc := NewConfigCommand(mu, "config.hcl")
+
+	err := cc.Init([]string{"list"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	if err == nil {
+		t.Fatal("Expected an error for the invalid configuration file but did not receive one")
+	}
+
+	if !strings.HasPrefix(err.Error(), "The configuration file is invalid, fix it with packageless config edit: ") {
+		t.Fatalf("Expected the configuration file to be reported as invalid | Received: %s", err.Error())
+	}
+}
+
+//Tests the flow of setting a configuration value
+func TestConfigSetFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.FileContents = map[string]string{"home/config.hcl": "# Use aliases\nalias = false\n"}
+
+	cc := NewConfigCommand(mu, "home/config.hcl")
+
+	err := cc.Init([]string{"set", "alias", "true"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FileExists", "ReadFile", "MakeDir", "WriteFile", "RenderInfoMarkdown"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if mu.WrittenFiles["home/config.hcl"] != "# Use aliases\nalias = true\n" {
+		t.Fatalf("Expected only the alias value to change | Received: %q", mu.WrittenFiles["home/config.hcl"])
+	}
+
+	//Invalid values should not be written
+	mu.Calls = nil
+
+	err = cc.Init([]string{"set", "start_port", "abc"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	exErr := "Invalid value for start_port: abc is not a whole number"
+
+	if err == nil {
+		t.Fatalf("Expected to have error: %s | Received No Error", exErr)
+	}
+
+	if err.Error() != exErr {
+		t.Fatalf("Expected to have error: %s | Received: %s", exErr, err.Error())
+	}
+
+	callStack = []string{"FileExists", "ReadFile"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+}
+
+//Tests the flow of editing the configuration file
+func TestConfigEditFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.FileContents = map[string]string{"config.hcl": "alias = false\n"}
+	mu.EditorContent = "alias = true\n"
+
+	cc := NewConfigCommand(mu, "config.hcl")
+
+	err := cc.Init([]string{"edit"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FileExists", "ReadFile", "TempDir", "WriteFile", "OpenEditor", "ReadFile", "RemoveDir", "MakeDir", "WriteFile", "RenderInfoMarkdown"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if mu.WrittenFiles["config.hcl"] != "alias = true\n" {
+		t.Fatalf("Expected the edited configuration to be saved | Received: %q", mu.WrittenFiles["config.hcl"])
+	}
+}
+
+//Test an invalid edit is not saved
+func TestConfigEditInvalid(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.FileContents = map[string]string{"config.hcl": "alias = false\n"}
+	mu.EditorContent = "alias = maybe\n"
+
+	cc := NewConfigCommand(mu, "config.hcl")
+
+	err := cc.Init([]string{"edit"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	if err == nil {
+		t.Fatal("Expected an error for the invalid edit but did not receive one")
+	}
+
+	if !strings.HasPrefix(err.Error(), "The edited configuration is invalid so config.hcl was not changed. The changes were kept in /tmp/packageless-config/config.hcl: ") {
+		t.Fatalf("Expected the edit to be reported as invalid | Received: %s", err.Error())
+	}
+
+	if _, ok := mu.WrittenFiles["config.hcl"]; ok {
+		t.Fatal("The configuration file should not have been written")
+	}
+}
+
+//Test values overridden by global options take precedence over the configuration file
+func TestConfigOverride(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.FileContents = map[string]string{"config.hcl": "base_dir = \"/opt/packageless/\"\n"}
+
+	cc := NewConfigCommand(mu, "config.hcl")
+	cc.Override("base_dir", "/tmp/sandbox/", "--base-dir")
+
+	err := cc.Init([]string{"get", "base_dir"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mu.Events[0].Data != "/tmp/sandbox/" {
+		t.Fatalf("Expected the overridden value of base_dir to be the result | Received: %v", mu.Events[0])
+	}
+
+	_, sources, err := cc.load()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sources["base_dir"] != "--base-dir" {
+		t.Fatalf("Expected the source of base_dir to be --base-dir | Received: %s", sources["base_dir"])
+	}
+}
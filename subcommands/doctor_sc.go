@@ -0,0 +1,463 @@
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Doctor Sub-Command Object
+type DoctorCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Path to the configuration file that is validated
+	configPath string
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//Results that a doctor check can have
+const (
+	CheckPass = "pass"
+	CheckWarn = "warn"
+	CheckFail = "fail"
+)
+
+//DoctorCheck - The outcome of a single check of the doctor subcommand
+type DoctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Details string `json:"details"`
+
+	//Individual problems found by the check, e.g. the diagnostics of the configuration
+	Problems []string `json:"problems,omitempty"`
+
+	//How to fix the problem found by the check
+	Hint string `json:"hint,omitempty"`
+}
+
+//Instantiation method for a new DoctorCommand
+func NewDoctorCommand(tools utils.Tools, config utils.Config, configPath string) *DoctorCommand {
+	//Create a new DoctorCommand and set the FlagSet
+	dc := &DoctorCommand{
+		fs:         flag.NewFlagSet("doctor", flag.ContinueOnError),
+		configPath: configPath,
+		tools:      tools,
+		config:     config,
+	}
+
+	return dc
+}
+
+//Name - Gets the name of the Sub-Command
+func (dc *DoctorCommand) Name() string {
+	return dc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (dc *DoctorCommand) Description() string {
+	return "Diagnose problems with Docker, the configuration and the installed pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (dc *DoctorCommand) Usage() string {
+	return "packageless doctor"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (dc *DoctorCommand) Flags() *flag.FlagSet {
+	return dc.fs
+}
+
+//Init - Parses and Populates values of the Doctor subcommand
+func (dc *DoctorCommand) Init(args []string) error {
+	return dc.fs.Parse(args)
+}
+
+//Run - Runs the Doctor subcommand
+func (dc *DoctorCommand) Run() error {
+	var checks []DoctorCheck
+
+	dockerCheck, cli := dc.checkDocker()
+	checks = append(checks, dockerCheck)
+
+	configCheck := dc.checkConfig()
+	checks = append(checks, configCheck)
+
+	//The remaining checks rely on the values from the configuration
+	if configCheck.Status == CheckFail {
+		checks = append(checks, DoctorCheck{
+			Name:    "Remaining checks",
+			Status:  CheckWarn,
+			Details: "skipped because the configuration is invalid",
+			Hint:    "Fix the configuration and run *packageless doctor* again",
+		})
+	} else {
+		checks = append(checks, dc.checkDirectories()...)
+		checks = append(checks, dc.checkRepositoryHost())
+		checks = append(checks, dc.checkPims(cli)...)
+	}
+
+	dc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  doctorText(checks),
+		Data:     checks,
+		Markdown: renderDoctorChecks(checks),
+	})
+
+	failed := 0
+
+	for _, check := range checks {
+		if check.Status == CheckFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d failed checks", failed)
+	}
+
+	return nil
+}
+
+//checkDocker - Checks that the Docker daemon can be reached. The client is nil if it could not be created.
+func (dc *DoctorCommand) checkDocker() (DoctorCheck, utils.Client) {
+	check := DoctorCheck{Name: "Docker daemon"}
+
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+
+	if err != nil {
+		check.Status = CheckFail
+		check.Details = "could not create the Docker client: " + err.Error()
+		check.Hint = "Check the DOCKER_HOST, DOCKER_API_VERSION and DOCKER_CERT_PATH environment variables"
+		return check, nil
+	}
+
+	version, err := dc.tools.DockerVersion(cli)
+
+	if err != nil {
+		check.Status = CheckFail
+		check.Details = "not reachable: " + err.Error()
+		check.Hint = "Make sure Docker is installed and running and that the current user is allowed to access it"
+		return check, nil
+	}
+
+	check.Status = CheckPass
+	check.Details = fmt.Sprintf("reachable, Docker %s with API version %s", version.Version, version.APIVersion)
+
+	return check, cli
+}
+
+//checkConfig - Checks that the configuration file exists and is valid
+func (dc *DoctorCommand) checkConfig() DoctorCheck {
+	check := DoctorCheck{Name: "Configuration"}
+
+	if !dc.tools.FileExists(dc.configPath) {
+		check.Status = CheckWarn
+		check.Details = dc.configPath + " does not exist, the default configuration is used"
+		check.Hint = "Create the configuration file with *packageless init*"
+		return check
+	}
+
+	diags := dc.tools.HCLDiagnostics(dc.configPath, utils.Config{})
+
+	if len(diags) == 0 {
+		check.Status = CheckPass
+		check.Details = dc.configPath + " is valid"
+		return check
+	}
+
+	for _, diag := range diags {
+		check.Problems = append(check.Problems, diag.Error())
+	}
+
+	check.Status = CheckWarn
+
+	if diags.HasErrors() {
+		check.Status = CheckFail
+		check.Hint = "Fix the problems at the locations reported in " + dc.configPath
+	}
+
+	check.Details = fmt.Sprintf("%d problems found in %s", len(diags), dc.configPath)
+
+	return check
+}
+
+//checkDirectories - Checks that the packageless directories exist and can be written to
+func (dc *DoctorCommand) checkDirectories() []DoctorCheck {
+	var checks []DoctorCheck
+
+	dirs := []struct {
+		name string
+		path string
+	}{
+		{"base_dir", dc.config.BaseDir},
+		{"pims_dir", dc.config.BaseDir + dc.config.PimsDir},
+		{"pims_config_dir", dc.config.BaseDir + dc.config.PimsConfigDir},
+	}
+
+	for _, dir := range dirs {
+		check := DoctorCheck{Name: "Directory " + dir.name}
+
+		if !dc.tools.FileExists(dir.path) {
+			//The directories are created when the first pim is installed
+			check.Status = CheckWarn
+			check.Details = dir.path + " does not exist"
+			check.Hint = "It is created when a pim is installed, or it can be created with *mkdir -p " + dir.path + "*"
+		} else if err := dc.tools.DirWritable(dir.path); err != nil {
+			check.Status = CheckFail
+			check.Details = dir.path + " is not writable: " + err.Error()
+			check.Hint = "Make sure the current user owns " + dir.path + " and has write permission on it"
+		} else {
+			check.Status = CheckPass
+			check.Details = dir.path + " exists and is writable"
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+//checkRepositoryHost - Checks that the repository host can be reached
+func (dc *DoctorCommand) checkRepositoryHost() DoctorCheck {
+	check := DoctorCheck{Name: "Repository host"}
+
+	err := dc.tools.CheckRepositoryHost(dc.config.RepositoryHost)
+
+	if err != nil {
+		check.Status = CheckFail
+		check.Details = dc.config.RepositoryHost + " is not reachable: " + err.Error()
+		check.Hint = "Check the network connection and the repository_host in the configuration"
+		return check
+	}
+
+	check.Status = CheckPass
+	check.Details = dc.config.RepositoryHost + " is reachable"
+
+	return check
+}
+
+//checkPims - Checks the images, volume directories and aliases of the installed pims
+func (dc *DoctorCommand) checkPims(cli utils.Client) []DoctorCheck {
+	var checks []DoctorCheck
+
+	pimConfigDir := dc.config.BaseDir + dc.config.PimsConfigDir
+	pimDir := dc.config.BaseDir + dc.config.PimsDir
+
+	//A missing pim config directory has already been reported by the directory checks
+	if !dc.tools.FileExists(pimConfigDir) {
+		return checks
+	}
+
+	//Get list of installed pims
+	pimNames, err := dc.tools.GetListOfInstalledPimConfigs(pimConfigDir)
+
+	if err != nil {
+		return append(checks, DoctorCheck{
+			Name:    "Installed pims",
+			Status:  CheckFail,
+			Details: "could not list the installed pim configurations: " + err.Error(),
+			Hint:    "Make sure the current user can read " + pimConfigDir,
+		})
+	}
+
+	//The images can not be checked without Docker
+	if cli == nil {
+		return append(checks, DoctorCheck{
+			Name:    "Installed pims",
+			Status:  CheckWarn,
+			Details: "skipped because the Docker daemon is not reachable",
+			Hint:    "Fix the Docker daemon and run *packageless doctor* again",
+		})
+	}
+
+	var aliasChecks []DoctorCheck
+
+	for _, pimName := range pimNames {
+		check := DoctorCheck{Name: "Pim " + pimName}
+
+		pims, err := dc.parsePimConfig(pimConfigDir + pimName + ".hcl")
+
+		if err != nil {
+			check.Status = CheckFail
+			check.Details = "invalid pim configuration: " + err.Error()
+			check.Hint = "Reinstall the pim with *packageless uninstall " + pimName + "* and *packageless install " + pimName + "*"
+			checks = append(checks, check)
+			continue
+		}
+
+		var installed []string
+		var missingDirs []string
+
+		for _, pim := range pims.Pims {
+			for _, ver := range pim.Versions {
+				imgExist, err := dc.tools.ImageExists(ver.Image, cli)
+
+				if err != nil {
+					check.Status = CheckFail
+					check.Details = "could not check the image " + ver.Image + ": " + err.Error()
+					check.Hint = "Make sure Docker is running"
+					break
+				}
+
+				if !imgExist {
+					continue
+				}
+
+				installed = append(installed, pim.Name+":"+ver.Version)
+
+				for _, vol := range ver.Volumes {
+					if vol.Path != "" && !dc.tools.FileExists(pimDir+vol.Path) {
+						missingDirs = append(missingDirs, pimDir+vol.Path)
+					}
+				}
+
+				if dc.config.Alias {
+					aliasChecks = append(aliasChecks, dc.checkAlias(pim.Name, ver.Version))
+				}
+			}
+		}
+
+		switch {
+		case check.Status == CheckFail:
+		case len(installed) == 0:
+			check.Status = CheckWarn
+			check.Details = "the pim configuration exists but none of its images are downloaded"
+			check.Hint = "Install it with *packageless install " + pimName + "* or remove it with *packageless uninstall " + pimName + "*"
+		case len(missingDirs) > 0:
+			check.Status = CheckFail
+			check.Details = "missing volume directories: " + strings.Join(missingDirs, ", ")
+			check.Hint = "Recreate them with *packageless upgrade " + pimName + "*"
+		default:
+			check.Status = CheckPass
+			check.Details = "images and volume directories present for " + strings.Join(installed, ", ")
+		}
+
+		checks = append(checks, check)
+	}
+
+	return append(checks, aliasChecks...)
+}
+
+//parsePimConfig - Parses the pim configuration at the given path
+func (dc *DoctorCommand) parsePimConfig(path string) (utils.PimHCLUtil, error) {
+	pimListBody, err := dc.tools.GetHCLBody(path)
+
+	if err != nil {
+		return utils.PimHCLUtil{}, err
+	}
+
+	//Parse the pim list
+	parseOut, err := dc.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+	if err != nil {
+		return utils.PimHCLUtil{}, err
+	}
+
+	return parseOut.(utils.PimHCLUtil), nil
+}
+
+//checkAlias - Checks that the alias of an installed pim version is set
+func (dc *DoctorCommand) checkAlias(name string, version string) DoctorCheck {
+	aliasName := name
+
+	if version != "latest" {
+		aliasName = name + ":" + version
+	}
+
+	check := DoctorCheck{Name: "Alias " + aliasName}
+
+	//get the executable directory that the alias points to
+	ex, err := os.Executable()
+
+	if err != nil {
+		check.Status = CheckWarn
+		check.Details = "could not get the packageless executable: " + err.Error()
+		return check
+	}
+
+	executableDir := filepath.Dir(ex)
+
+	var exists bool
+
+	if runtime.GOOS == "windows" {
+		exists, err = dc.tools.AliasExistsWin(aliasName, executableDir)
+	} else {
+		exists, err = dc.tools.AliasExistsUnix(aliasName, executableDir)
+	}
+
+	if err != nil {
+		check.Status = CheckWarn
+		check.Details = "could not be checked: " + err.Error()
+		check.Hint = "Aliases are only supported for bash and zsh, or PowerShell on Windows"
+		return check
+	}
+
+	if !exists {
+		check.Status = CheckWarn
+		check.Details = "not set in the shell rc file"
+		check.Hint = "Reinstall the pim with *packageless uninstall " + aliasName + "* and *packageless install " + aliasName + "*, or set alias to false in the configuration"
+		return check
+	}
+
+	check.Status = CheckPass
+	check.Details = "set in the shell rc file"
+
+	return check
+}
+
+//renderDoctorChecks - Creates the markdown checklist of the doctor checks
+func renderDoctorChecks(checks []DoctorCheck) string {
+	var sb strings.Builder
+	counts := make(map[string]int)
+
+	sb.WriteString("# packageless doctor\n")
+
+	for _, check := range checks {
+		counts[check.Status]++
+
+		sb.WriteString(fmt.Sprintf("- **[%s]** %s - %s\n", strings.ToUpper(check.Status), check.Name, check.Details))
+
+		for _, problem := range check.Problems {
+			sb.WriteString(fmt.Sprintf("  - %s\n", problem))
+		}
+
+		if check.Status != CheckPass && check.Hint != "" {
+			sb.WriteString(fmt.Sprintf("  - *Hint*: %s\n", check.Hint))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("\n**%d passed, %d warnings, %d failed**\n", counts[CheckPass], counts[CheckWarn], counts[CheckFail]))
+
+	return sb.String()
+}
+
+//doctorText - Creates the plain text lines of the doctor checks
+func doctorText(checks []DoctorCheck) string {
+	var lines []string
+
+	for _, check := range checks {
+		lines = append(lines, fmt.Sprintf("[%s] %s - %s", strings.ToUpper(check.Status), check.Name, check.Details))
+
+		for _, problem := range check.Problems {
+			lines = append(lines, "  "+problem)
+		}
+
+		if check.Status != CheckPass && check.Hint != "" {
+			lines = append(lines, "  Hint: "+check.Hint)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,178 @@
+package subcommands
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+	"github.com/hashicorp/hcl2/hcl"
+)
+
+//Test to make sure the doctor subcommand has the proper name upon creation
+func TestDoctorName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	dc := NewDoctorCommand(mu, mu.Conf, "config.hcl")
+
+	if dc.Name() != "doctor" {
+		t.Fatal("The doctor subcommand's name should be: doctor | Subcommand Name: " + dc.Name())
+	}
+}
+
+//Tests the flow of the doctor subcommand when all the checks pass
+func TestDoctorFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.InstalledPims = []string{"python"}
+	mu.ImgExist = true
+	mu.AliasExist = true
+
+	dc := NewDoctorCommand(mu, mu.Conf, "config.hcl")
+
+	err := dc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = dc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"DockerVersion",
+		"FileExists",
+		"HCLDiagnostics",
+		"FileExists",
+		"DirWritable",
+		"FileExists",
+		"DirWritable",
+		"FileExists",
+		"DirWritable",
+		"CheckRepositoryHost",
+		"FileExists",
+		"GetListOfInstalledPimConfigs",
+		"GetHCLBody",
+		"ParseBody",
+		"ImageExists",
+		"FileExists",
+		"AliasExists",
+		"Emit",
+	}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	expectedDirs := []string{
+		mu.Conf.BaseDir,
+		mu.Conf.BaseDir + mu.Conf.PimsDir,
+		mu.Conf.BaseDir + mu.Conf.PimsConfigDir,
+	}
+
+	if !reflect.DeepEqual(expectedDirs, mu.WritableDirs) {
+		t.Fatalf("Writable directories do not match. Received: %v | Expected: %v", mu.WritableDirs, expectedDirs)
+	}
+}
+
+//Test the doctor subcommand fails and skips the remaining checks when the config is invalid
+func TestDoctorInvalidConfig(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.Diags = hcl.Diagnostics{
+		{
+			Severity: hcl.DiagError,
+			Summary:  "Unsuitable value type",
+			Detail:   "Unsuitable value: a number is required",
+		},
+	}
+
+	dc := NewDoctorCommand(mu, mu.Conf, "config.hcl")
+
+	err := dc.Run()
+
+	exErr := "doctor found 1 failed checks"
+
+	if err == nil {
+		t.Fatalf("Expected to have error: %s | Received No Error", exErr)
+	}
+
+	if err.Error() != exErr {
+		t.Fatalf("Expected to have error: %s | Received: %s", exErr, err.Error())
+	}
+
+	callStack := []string{"DockerVersion", "FileExists", "HCLDiagnostics", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	//The checks should be the data of the result so that they can be consumed from the JSON output
+	checks := mu.Events[0].Data.([]DoctorCheck)
+
+	if len(checks) != 3 || checks[1].Status != CheckFail || checks[2].Status != CheckWarn {
+		t.Fatalf("The configuration check should have failed and the remaining checks skipped | Received: %+v", checks)
+	}
+}
+
+//Test the doctor subcommand reports every failed check
+func TestDoctorErrorAt(t *testing.T) {
+	for _, errorAt := range []string{"DockerVersion", "DirWritable", "CheckRepositoryHost"} {
+		mu := utils.NewMockUtility()
+
+		mu.ErrorAt = errorAt
+
+		dc := NewDoctorCommand(mu, mu.Conf, "config.hcl")
+
+		err := dc.Run()
+
+		//Every directory fails when it is not writable
+		failed := 1
+
+		if errorAt == "DirWritable" {
+			failed = 3
+		}
+
+		exErr := fmt.Sprintf("doctor found %d failed checks", failed)
+
+		if err == nil {
+			t.Fatalf("ErrorAt %s: Expected to have error: %s | Received No Error", errorAt, exErr)
+		}
+
+		if err.Error() != exErr {
+			t.Fatalf("ErrorAt %s: Expected to have error: %s | Received: %s", errorAt, exErr, err.Error())
+		}
+	}
+}
+
+//Test the checklist shows the status, details and hints of the checks
+func TestRenderDoctorChecks(t *testing.T) {
+	checks := []DoctorCheck{
+		{Name: "Docker daemon", Status: CheckPass, Details: "reachable", Hint: "not shown"},
+		{Name: "Configuration", Status: CheckFail, Details: "1 problems found", Problems: []string{"config.hcl:2,15-18: Unsuitable value type"}, Hint: "Fix it"},
+		{Name: "Directory pims_dir", Status: CheckWarn, Details: "does not exist"},
+	}
+
+	out := renderDoctorChecks(checks)
+
+	expected := []string{
+		"- **[PASS]** Docker daemon - reachable\n",
+		"- **[FAIL]** Configuration - 1 problems found\n  - config.hcl:2,15-18: Unsuitable value type\n  - *Hint*: Fix it\n",
+		"- **[WARN]** Directory pims_dir - does not exist\n",
+		"**1 passed, 1 warnings, 1 failed**",
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(out, line) {
+			t.Fatalf("renderDoctorChecks: Expected the checklist to contain: %q | Received: %q", line, out)
+		}
+	}
+
+	if strings.Contains(out, "not shown") {
+		t.Fatalf("renderDoctorChecks: Hints should not be shown for passed checks | Received: %q", out)
+	}
+}
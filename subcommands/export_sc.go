@@ -0,0 +1,180 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Export Sub-Command Object
+type ExportCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Path of the lockfile to write
+	path string
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//Instantiation method for a new ExportCommand
+func NewExportCommand(tools utils.Tools, config utils.Config) *ExportCommand {
+	//Create a new ExportCommand and set the FlagSet
+	ec := &ExportCommand{
+		fs:     flag.NewFlagSet("export", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	return ec
+}
+
+//Name - Gets the name of the Sub-Command
+func (ec *ExportCommand) Name() string {
+	return ec.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (ec *ExportCommand) Description() string {
+	return "Write a lockfile of the installed pims that sync can recreate them from"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (ec *ExportCommand) Usage() string {
+	return "packageless export [OPTIONS] [lockfile]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (ec *ExportCommand) Flags() *flag.FlagSet {
+	return ec.fs
+}
+
+//Init - Parses and Populates values of the Export subcommand
+func (ec *ExportCommand) Init(args []string) error {
+	err := ec.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	ec.path = utils.LockFile
+
+	if ec.fs.NArg() > 0 {
+		ec.path = ec.fs.Arg(0)
+	}
+
+	return nil
+}
+
+//Run - Runs the Export subcommand
+func (ec *ExportCommand) Run() error {
+	pimConfigDir := ec.config.BaseDir + ec.config.PimsConfigDir
+
+	var pimNames []string
+	var err error
+
+	//Nothing has been installed yet if the directory does not exist, an empty lockfile is exported
+	if ec.tools.FileExists(pimConfigDir) {
+		pimNames, err = ec.tools.GetListOfInstalledPimConfigs(pimConfigDir)
+
+		if err != nil {
+			return errors.New("Encountered an error while trying to fetch list of installed pim configuration files: " + err.Error())
+		}
+	} else {
+		ec.tools.Emit(utils.Event{Type: utils.EventWarning, Message: "No pims are installed"})
+	}
+
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	entries := []utils.LockEntry{}
+
+	for _, pimName := range pimNames {
+		pimPath := pimConfigDir + pimName + ".hcl"
+
+		content, err := ec.tools.ReadFile(pimPath)
+
+		if err != nil {
+			return err
+		}
+
+		checksum := configChecksum(content)
+
+		pimListBody, err := ec.tools.GetHCLBody(pimPath)
+
+		if err != nil {
+			return err
+		}
+
+		//Parse the pim list
+		parseOut, err := ec.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+		if err != nil {
+			return err
+		}
+
+		pims := parseOut.(utils.PimHCLUtil)
+
+		for _, pim := range pims.Pims {
+			for _, ver := range pim.Versions {
+				img, imgExist, err := ec.tools.FindImage(ver.Image, cli)
+
+				if err != nil {
+					return err
+				}
+
+				//Only the installed versions are locked
+				if !imgExist {
+					continue
+				}
+
+				entry := utils.LockEntry{
+					Pim:            pim.Name,
+					Version:        ver.Version,
+					Image:          ver.Image,
+					Digest:         repoDigest(ver.Image, img.RepoDigests),
+					ConfigChecksum: checksum,
+				}
+
+				if entry.Digest == "" {
+					pimRef := pim.Name + ":" + ver.Version
+					ec.tools.Emit(utils.Event{Type: utils.EventWarning, Pim: pimRef, Message: "Could not lock the image of " + pimRef + ", it was not pulled from a registry. sync will install the latest image of it"})
+				}
+
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	err = ec.tools.WriteFile(ec.path, utils.LockfileHCL(entries))
+
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Exported %d pims to %s", len(entries), ec.path)
+
+	ec.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  message,
+		Data:     entries,
+		Markdown: "*" + message + "*",
+	})
+
+	return nil
+}
+
+//configChecksum - Gets the checksum of a pim configuration that is locked.
+//Line endings depend on how the file was checked out in the repository, so they are not part of the checksum
+func configChecksum(content string) string {
+	return utils.Checksum([]byte(strings.ReplaceAll(content, "\r\n", "\n")))
+}
@@ -0,0 +1,136 @@
+package subcommands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/everettraven/packageless/utils"
+)
+
+func TestExportName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	ec := NewExportCommand(mu, mu.Conf)
+
+	if ec.Name() != "export" {
+		t.Fatalf("The export subcommand's name should be: 'export' but was '%s'", ec.Name())
+	}
+}
+
+//Test the installed pims are written to the lockfile with their image digests and pim configuration checksums
+func TestExportFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.InstalledPims = []string{"python"}
+	mu.ImgExist = true
+	mu.ImgSummary = types.ImageSummary{ID: "sha256:imageid", RepoDigests: []string{"packageless/python@sha256:locked"}}
+	mu.FileContents = map[string]string{mu.Conf.BaseDir + mu.Conf.PimsConfigDir + "python.hcl": "pim \"python\" {}\r\n"}
+
+	ec := NewExportCommand(mu, mu.Conf)
+
+	err := ec.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ec.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"FileExists",
+		"GetListOfInstalledPimConfigs",
+		"ReadFile",
+		"GetHCLBody",
+		"ParseBody",
+		"FindImage",
+		"WriteFile",
+		"Emit",
+	}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	expected := []utils.LockEntry{
+		{
+			Pim:            "python",
+			Version:        "latest",
+			Image:          "packageless/python",
+			Digest:         "sha256:locked",
+			ConfigChecksum: utils.Checksum([]byte("pim \"python\" {}\n")),
+		},
+	}
+
+	entries, diags := utils.LoadLockfileHCL([]byte(mu.WrittenFiles[utils.LockFile]), utils.LockFile)
+
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if !reflect.DeepEqual(expected, entries) {
+		t.Fatalf("Export: Expected the lockfile entries: %v | Received: %v", expected, entries)
+	}
+}
+
+//Test the lockfile is written to the path passed as an argument
+func TestExportPath(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	ec := NewExportCommand(mu, mu.Conf)
+
+	err := ec.Init([]string{"ci/packageless.lock.hcl"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ec.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := mu.WrittenFiles["ci/packageless.lock.hcl"]; !ok {
+		t.Fatalf("Export: Expected the lockfile to be written to ci/packageless.lock.hcl | Received: %v", mu.WrittenFiles)
+	}
+}
+
+//Test an empty lockfile is exported when nothing has been installed yet
+func TestExportNoPimConfigDir(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.PimConfigShouldExist = false
+
+	ec := NewExportCommand(mu, mu.Conf)
+
+	err := ec.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ec.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FileExists", "Emit", "WriteFile", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if mu.Events[0].Type != utils.EventWarning || mu.Events[0].Message != "No pims are installed" {
+		t.Fatalf("Export: Expected a warning that no pims are installed | Received: %v", mu.Events[0])
+	}
+
+	if !reflect.DeepEqual([]utils.LockEntry{}, mu.Events[1].Data) {
+		t.Fatalf("Export: Expected no lockfile entries | Received: %v", mu.Events[1].Data)
+	}
+}
@@ -0,0 +1,119 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//GlobalOptions - Options that are passed before the subcommand and apply to every subcommand
+type GlobalOptions struct {
+	//Path to the configuration file to use instead of the default one
+	ConfigPath string
+
+	//Base directory to use instead of the one in the configuration
+	BaseDir string
+
+	//Only output errors
+	Quiet bool
+
+	//Output what packageless is doing
+	Verbose bool
+
+	//Output without colors
+	NoColor bool
+
+	//Format to output in
+	Output string
+}
+
+//globalFlags - Creates the FlagSet of the global options that populates the given options
+func globalFlags(opts *GlobalOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("packageless", flag.ContinueOnError)
+
+	//Flag parsing errors are returned instead of being printed by the flag package
+	fs.SetOutput(ioutil.Discard)
+
+	fs.StringVar(&opts.ConfigPath, "config", "", "use the configuration file at `PATH` instead of ~/.packageless/config.hcl")
+	fs.StringVar(&opts.BaseDir, "base-dir", "", "use `DIR` as the base directory instead of the base_dir configuration value")
+	fs.BoolVar(&opts.Quiet, "quiet", false, "only output errors")
+	fs.BoolVar(&opts.Verbose, "verbose", false, "output what packageless is doing")
+	fs.BoolVar(&opts.NoColor, "no-color", false, "output without colors, also enabled by setting the NO_COLOR environment variable")
+	fs.StringVar(&opts.Output, "output", utils.OutputMarkdown, "output `FORMAT`: "+strings.Join(utils.OutputFormats, ", "))
+
+	return fs
+}
+
+//ParseGlobalOptions - Parses the global options at the start of the arguments.
+//The remaining arguments, starting with the subcommand, are returned along with the options.
+func ParseGlobalOptions(args []string) (GlobalOptions, []string, error) {
+	var opts GlobalOptions
+
+	fs := globalFlags(&opts)
+
+	err := fs.Parse(args)
+
+	//A help flag before the subcommand shows the usage of packageless
+	if errors.Is(err, flag.ErrHelp) {
+		return opts, []string{"help"}, nil
+	}
+
+	if err != nil {
+		return opts, nil, err
+	}
+
+	if opts.Quiet && opts.Verbose {
+		return opts, nil, errors.New("The --quiet and --verbose options can not be used together")
+	}
+
+	return opts, fs.Args(), nil
+}
+
+//Apply - Overrides the values of the configuration with the global options
+func (opts GlobalOptions) Apply(config *utils.Config) {
+	if opts.BaseDir != "" {
+		config.BaseDir = opts.BaseDir
+
+		//Paths are joined by appending to the base directory so it has to end with a separator
+		if !strings.HasSuffix(config.BaseDir, "/") && !strings.HasSuffix(config.BaseDir, "\\") {
+			config.BaseDir += "/"
+		}
+	}
+}
+
+//skipGlobalOptions - Removes the global options from the start of the words of a command line.
+//Returns true as well if the last word is a global option that still expects a value.
+func skipGlobalOptions(words []string) ([]string, bool) {
+	fs := globalFlags(&GlobalOptions{})
+
+	for len(words) > 0 && strings.HasPrefix(words[0], "-") {
+		word := words[0]
+		words = words[1:]
+
+		if strings.Contains(word, "=") {
+			continue
+		}
+
+		f := fs.Lookup(strings.TrimLeft(word, "-"))
+
+		if f == nil {
+			continue
+		}
+
+		//Options that are not booleans take the next word as their value
+		if boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+			continue
+		}
+
+		if len(words) == 0 {
+			return words, true
+		}
+
+		words = words[1:]
+	}
+
+	return words, false
+}
@@ -0,0 +1,78 @@
+package subcommands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+// Test the global options before the subcommand are parsed and the subcommand arguments are left as is
+func TestParseGlobalOptions(t *testing.T) {
+	opts, args, err := ParseGlobalOptions([]string{"--config", "ci/config.hcl", "--base-dir=/tmp/sandbox", "--verbose", "--no-color", "install", "--quiet", "python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := GlobalOptions{ConfigPath: "ci/config.hcl", BaseDir: "/tmp/sandbox", Verbose: true, NoColor: true, Output: utils.OutputMarkdown}
+
+	if !reflect.DeepEqual(expected, opts) {
+		t.Fatalf("ParseGlobalOptions: Expected options: %v | Received: %v", expected, opts)
+	}
+
+	if !reflect.DeepEqual([]string{"install", "--quiet", "python"}, args) {
+		t.Fatalf("ParseGlobalOptions: Expected the subcommand arguments to be left as is | Received: %v", args)
+	}
+}
+
+// Test invalid global options are reported
+func TestParseGlobalOptionsErrors(t *testing.T) {
+	tests := map[string][]string{
+		"flag provided but not defined: -unknown":                    {"--unknown", "install"},
+		"flag needs an argument: -config":                            {"--config"},
+		"The --quiet and --verbose options can not be used together": {"--quiet", "--verbose", "list"},
+	}
+
+	for exErr, args := range tests {
+		_, _, err := ParseGlobalOptions(args)
+
+		if err == nil {
+			t.Fatalf("Expected to have error: %s | Received No Error", exErr)
+		}
+
+		if err.Error() != exErr {
+			t.Fatalf("Expected to have error: %s | Received: %s", exErr, err.Error())
+		}
+	}
+}
+
+// Test a help flag before the subcommand shows the usage of packageless
+func TestParseGlobalOptionsHelp(t *testing.T) {
+	_, args, err := ParseGlobalOptions([]string{"--quiet", "--help"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{"help"}, args) {
+		t.Fatalf("ParseGlobalOptions: Expected the help subcommand | Received: %v", args)
+	}
+}
+
+// Test the base directory override always ends with a separator
+func TestGlobalOptionsApply(t *testing.T) {
+	config := utils.DefaultConfig()
+
+	GlobalOptions{}.Apply(&config)
+
+	if !reflect.DeepEqual(utils.DefaultConfig(), config) {
+		t.Fatalf("Apply: The configuration should not change without global options | Received: %v", config)
+	}
+
+	GlobalOptions{BaseDir: "/tmp/sandbox"}.Apply(&config)
+
+	if config.BaseDir != "/tmp/sandbox/" {
+		t.Fatalf("Apply: Expected the base directory /tmp/sandbox/ | Received: %s", config.BaseDir)
+	}
+}
@@ -0,0 +1,172 @@
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Help Sub-Command Object
+type HelpCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Name of the subcommand to show the help of
+	name string
+
+	//Subcommands that help can be shown for
+	runners []Runner
+
+	tools utils.Tools
+}
+
+//Instantiation method for a new HelpCommand
+func NewHelpCommand(tools utils.Tools) *HelpCommand {
+	//Create a new HelpCommand and set the FlagSet
+	hc := &HelpCommand{
+		fs:    flag.NewFlagSet("help", flag.ContinueOnError),
+		tools: tools,
+	}
+
+	return hc
+}
+
+//SetRunners - Sets the subcommands that help can be shown for
+func (hc *HelpCommand) SetRunners(runners []Runner) {
+	hc.runners = runners
+}
+
+//Name - Gets the name of the Sub-Command
+func (hc *HelpCommand) Name() string {
+	return hc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (hc *HelpCommand) Description() string {
+	return "Show the usage of packageless or of a subcommand"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (hc *HelpCommand) Usage() string {
+	return "packageless help [OPTIONAL: SUBCOMMAND]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (hc *HelpCommand) Flags() *flag.FlagSet {
+	return hc.fs
+}
+
+//Init - Parses and Populates values of the Help subcommand
+func (hc *HelpCommand) Init(args []string) error {
+	err := hc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	hc.name = ""
+
+	if hc.fs.NArg() > 0 {
+		hc.name = hc.fs.Arg(0)
+	}
+
+	return nil
+}
+
+//Run - Runs the Help subcommand
+func (hc *HelpCommand) Run() error {
+	if hc.name == "" {
+		hc.tools.RenderInfoMarkdown(renderUsage(hc.runners))
+		return nil
+	}
+
+	for _, cmd := range append(append([]Runner{}, hc.runners...), findPlugins(hc.runners)...) {
+		if cmd.Name() == hc.name {
+			hc.tools.RenderInfoMarkdown(renderCommandHelp(cmd))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("Unknown subcommand %s", hc.name)
+}
+
+//renderUsage - Creates the markdown for the usage of packageless listing all the subcommands
+func renderUsage(scmds []Runner) string {
+	var sb strings.Builder
+
+	sb.WriteString("# packageless\n")
+	sb.WriteString("**Usage**: *packageless [GLOBAL OPTIONS] [SUBCOMMAND] [OPTIONS] [ARGUMENTS]*\n\n")
+	sb.WriteString("**Subcommands**:\n")
+
+	for _, cmd := range visibleRunners(scmds) {
+		sb.WriteString(fmt.Sprintf("- **%s** - %s\n", cmd.Name(), cmd.Description()))
+	}
+
+	plugins := findPlugins(scmds)
+
+	if len(plugins) > 0 {
+		sb.WriteString("\n**Plugins**:\n")
+
+		for _, plugin := range plugins {
+			sb.WriteString(fmt.Sprintf("- **%s** - %s\n", plugin.Name(), plugin.Description()))
+		}
+	}
+
+	sb.WriteString("\n**Global options**:\n")
+
+	globalFlags(&GlobalOptions{}).VisitAll(func(f *flag.Flag) {
+		sb.WriteString(renderFlag(f))
+	})
+
+	sb.WriteString("\nRun *packageless help [SUBCOMMAND]* or *packageless [SUBCOMMAND] --help* for the usage of a subcommand\n")
+
+	return sb.String()
+}
+
+//renderCommandHelp - Creates the markdown for the usage of a single subcommand including its flags
+func renderCommandHelp(cmd Runner) string {
+	var sb strings.Builder
+	var flags []string
+
+	sb.WriteString(fmt.Sprintf("# %s\n", cmd.Name()))
+	sb.WriteString(fmt.Sprintf("%s\n\n", cmd.Description()))
+	sb.WriteString(fmt.Sprintf("**Usage**: *%s*\n", cmd.Usage()))
+
+	cmd.Flags().VisitAll(func(f *flag.Flag) {
+		flags = append(flags, renderFlag(f))
+	})
+
+	//The fail fast flag is handled by SubCommand for all batch subcommands
+	if batcher, ok := cmd.(Batcher); ok && batcher.Batch() {
+		flags = append(flags, "- **--fail-fast** - stop processing pims as soon as one of them fails\n")
+	}
+
+	if len(flags) > 0 {
+		sb.WriteString("\n**Options**:\n")
+
+		for _, f := range flags {
+			sb.WriteString(f)
+		}
+	}
+
+	return sb.String()
+}
+
+//renderFlag - Creates the markdown list item describing a flag
+func renderFlag(f *flag.Flag) string {
+	name := "--" + f.Name
+	valueName, usage := flag.UnquoteUsage(f)
+
+	if valueName != "" {
+		name += " " + valueName
+	}
+
+	//Only show defaults that differ from the zero value of the flag
+	if f.DefValue != "" && f.DefValue != "false" && f.DefValue != "0" {
+		usage += fmt.Sprintf(" (default: %s)", f.DefValue)
+	}
+
+	return fmt.Sprintf("- **%s** - %s\n", name, usage)
+}
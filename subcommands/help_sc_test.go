@@ -0,0 +1,139 @@
+package subcommands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Test to make sure the help subcommand has the proper name upon creation
+func TestHelpName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	hc := NewHelpCommand(mu)
+
+	if hc.Name() != "help" {
+		t.Fatal("The help subcommand's name should be: help | Subcommand Name: " + hc.Name())
+	}
+}
+
+//Test to make sure the help subcommand initializes correctly
+func TestHelpInit(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	hc := NewHelpCommand(mu)
+
+	err := hc.Init([]string{"install"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hc.name != "install" {
+		t.Fatal("Subcommand name should have been initialized as: install but is: " + hc.name)
+	}
+}
+
+//Tests the flow of the help subcommand with and without a subcommand
+func TestHelpFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	hc := NewHelpCommand(mu)
+	hc.SetRunners([]Runner{NewInstallCommand(mu, &utils.MockCopyTool{}, mu.Conf), hc})
+
+	for _, args := range [][]string{{}, {"install"}, {"help"}} {
+		mu.Calls = nil
+
+		err := hc.Init(args)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = hc.Run()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		callStack := []string{"RenderInfoMarkdown"}
+
+		if !reflect.DeepEqual(callStack, mu.Calls) {
+			t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+		}
+	}
+}
+
+//Test the help subcommand with an unknown subcommand
+func TestHelpUnknownSubcommand(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	hc := NewHelpCommand(mu)
+	hc.SetRunners([]Runner{hc})
+
+	err := hc.Init([]string{"unknown"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = hc.Run()
+
+	exErr := "Unknown subcommand unknown"
+
+	if err == nil {
+		t.Fatalf("Expected to have error: %s | Received No Error", exErr)
+	}
+
+	if err.Error() != exErr {
+		t.Fatalf("Expected to have error: %s | Received: %s", exErr, err.Error())
+	}
+}
+
+//Test the usage lists every subcommand with its description
+func TestRenderUsage(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	scmds := []Runner{
+		NewInstallCommand(mu, &utils.MockCopyTool{}, mu.Conf),
+		NewListCommand(mu, mu.Conf),
+	}
+
+	usage := renderUsage(scmds)
+
+	for _, cmd := range scmds {
+		line := "- **" + cmd.Name() + "** - " + cmd.Description() + "\n"
+
+		if !strings.Contains(usage, line) {
+			t.Fatalf("renderUsage: Expected the usage to contain: %q | Received: %q", line, usage)
+		}
+	}
+}
+
+//Test the help of a subcommand lists its flags
+func TestRenderCommandHelp(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	help := renderCommandHelp(NewListCommand(mu, mu.Conf))
+
+	expected := []string{
+		"# list\n",
+		"**Usage**: *packageless list [OPTIONS]*\n",
+		"- **--all** - include versions whose configuration exists but whose image is not downloaded\n",
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(help, line) {
+			t.Fatalf("renderCommandHelp: Expected the help to contain: %q | Received: %q", line, help)
+		}
+	}
+
+	//Batch subcommands should include the fail fast flag
+	help = renderCommandHelp(NewInstallCommand(mu, &utils.MockCopyTool{}, mu.Conf))
+
+	if !strings.Contains(help, "- **--fail-fast** - ") {
+		t.Fatalf("renderCommandHelp: Expected the help to contain the fail fast flag | Received: %q", help)
+	}
+}
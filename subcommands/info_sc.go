@@ -0,0 +1,331 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Info Sub-Command Object
+type InfoCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//String for the name of the pim to show the information of
+	name string
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//InfoEntry - Details of a pim and the versions that are shown
+type InfoEntry struct {
+	Name              string        `json:"name"`
+	BaseDir           string        `json:"base_dir"`
+	AvailableVersions []string      `json:"available_versions"`
+	Versions          []InfoVersion `json:"versions"`
+}
+
+//InfoVersion - Details of a single version of a pim
+type InfoVersion struct {
+	Version   string       `json:"version"`
+	Image     string       `json:"image"`
+	Installed bool         `json:"installed"`
+	Ports     []utils.Port `json:"ports"`
+	Volumes   []InfoVolume `json:"volumes"`
+	Copies    []InfoCopy   `json:"copies"`
+}
+
+//InfoVolume - A volume of a pim version, an empty source is the current working directory
+type InfoVolume struct {
+	Source string `json:"source"`
+	Mount  string `json:"mount"`
+}
+
+//InfoCopy - A path that is copied from the container of a pim version
+type InfoCopy struct {
+	Source string `json:"source"`
+	Dest   string `json:"dest"`
+}
+
+//Instantiation method for a new InfoCommand
+func NewInfoCommand(tools utils.Tools, config utils.Config) *InfoCommand {
+	//Create a new InfoCommand and set the FlagSet
+	ic := &InfoCommand{
+		fs:     flag.NewFlagSet("info", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	return ic
+}
+
+//Name - Gets the name of the Sub-Command
+func (ic *InfoCommand) Name() string {
+	return ic.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (ic *InfoCommand) Description() string {
+	return "Show the definition and install state of a pim"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (ic *InfoCommand) Usage() string {
+	return "packageless info [pim]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (ic *InfoCommand) Flags() *flag.FlagSet {
+	return ic.fs
+}
+
+//Init - Parses and Populates values of the Info subcommand
+func (ic *InfoCommand) Init(args []string) error {
+	err := ic.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = ic.fs.Args()
+
+	if len(args) <= 0 {
+		return errors.New("No pim name was found. You must include the name of the pim you wish to see the information of.")
+	}
+
+	ic.name = args[0]
+
+	return nil
+}
+
+//Run - Runs the Info subcommand
+func (ic *InfoCommand) Run() error {
+	pimName, pimVersion := splitPimName(ic.name)
+
+	//Only show a single version if one was specified
+	allVersions := !strings.Contains(ic.name, ":")
+
+	pimConfigDir := ic.config.BaseDir + ic.config.PimsConfigDir
+	pimPath := pimConfigDir + pimName + ".hcl"
+	pimDir := ic.config.BaseDir + ic.config.PimsDir
+
+	//Use the installed pim config if it exists, otherwise fetch it to a temporary directory
+	//so that no pim config is left behind for a pim that is not installed
+	if !ic.tools.FileExists(pimPath) {
+		tempDir, err := ic.tools.TempDir("packageless-info")
+
+		if err != nil {
+			return err
+		}
+
+		defer ic.tools.RemoveDir(tempDir)
+
+		err = ic.tools.FetchPimConfig(ic.config.RepositoryHost, pimName, tempDir)
+
+		if err != nil {
+			return err
+		}
+
+		pimPath = tempDir + pimName + ".hcl"
+	}
+
+	pimListBody, err := ic.tools.GetHCLBody(pimPath)
+
+	if err != nil {
+		return err
+	}
+
+	//Parse the pim list
+	parseOut, err := ic.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+	if err != nil {
+		return err
+	}
+
+	pims := parseOut.(utils.PimHCLUtil)
+
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	found := false
+
+	for _, pim := range pims.Pims {
+		if pim.Name != pimName {
+			continue
+		}
+
+		var versions []utils.Version
+
+		for _, ver := range pim.Versions {
+			if allVersions || ver.Version == pimVersion {
+				versions = append(versions, ver)
+			}
+		}
+
+		if len(versions) == 0 {
+			break
+		}
+
+		found = true
+
+		entry := InfoEntry{
+			Name:    pim.Name,
+			BaseDir: pimDir + pim.BaseDir,
+		}
+
+		for _, ver := range pim.Versions {
+			entry.AvailableVersions = append(entry.AvailableVersions, ver.Version)
+		}
+
+		for _, ver := range versions {
+			//Check if the corresponding pim image is installed
+			imgExist, err := ic.tools.ImageExists(ver.Image, cli)
+
+			if err != nil {
+				return err
+			}
+
+			entry.Versions = append(entry.Versions, versionInfo(ver, imgExist, pimDir))
+		}
+
+		ic.tools.Emit(utils.Event{
+			Type:     utils.EventResult,
+			Message:  infoText(entry),
+			Data:     entry,
+			Markdown: renderInfo(entry),
+		})
+	}
+
+	//Make sure we have found the pim in the pim list
+	if !found {
+		return errors.New("Could not find pim " + pimName + " with version '" + pimVersion + "' in the pim configuration")
+	}
+
+	return nil
+}
+
+//versionInfo - Gets the details of a single version of a pim
+func versionInfo(ver utils.Version, installed bool, pimDir string) InfoVersion {
+	info := InfoVersion{
+		Version:   ver.Version,
+		Image:     ver.Image,
+		Installed: installed,
+		Ports:     append([]utils.Port{}, ver.PublishedPorts()...),
+		Volumes:   []InfoVolume{},
+		Copies:    []InfoCopy{},
+	}
+
+	for _, vol := range ver.Volumes {
+		source := ""
+
+		if vol.Path != "" {
+			source = pimDir + vol.Path
+		}
+
+		info.Volumes = append(info.Volumes, InfoVolume{Source: source, Mount: vol.Mount})
+	}
+
+	for _, copy := range ver.Copies {
+		info.Copies = append(info.Copies, InfoCopy{Source: copy.Source, Dest: pimDir + copy.Dest})
+	}
+
+	return info
+}
+
+//infoText - Creates the plain text lines describing a pim
+func infoText(entry InfoEntry) string {
+	lines := []string{entry.Name + " " + strings.Join(entry.AvailableVersions, ",")}
+
+	for _, ver := range entry.Versions {
+		status := "not installed"
+
+		if ver.Installed {
+			status = "installed"
+		}
+
+		lines = append(lines, fmt.Sprintf("%s:%s %s %s", entry.Name, ver.Version, ver.Image, status))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+//renderInfo - Creates the markdown describing a pim and its versions
+func renderInfo(entry InfoEntry) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n", entry.Name))
+	sb.WriteString(fmt.Sprintf("**Base directory**: *%s*\n\n", entry.BaseDir))
+	sb.WriteString(fmt.Sprintf("**Available versions**: *%s*\n", strings.Join(entry.AvailableVersions, ", ")))
+
+	for _, ver := range entry.Versions {
+		sb.WriteString(renderVersionInfo(ver))
+	}
+
+	return sb.String()
+}
+
+//renderVersionInfo - Creates the markdown describing a single version of a pim
+func renderVersionInfo(ver InfoVersion) string {
+	var sb strings.Builder
+
+	status := "not installed"
+
+	if ver.Installed {
+		status = "installed"
+	}
+
+	sb.WriteString(fmt.Sprintf("## Version %s\n", ver.Version))
+	sb.WriteString(fmt.Sprintf("- **Status**: *%s*\n", status))
+	sb.WriteString(fmt.Sprintf("- **Image**: *%s*\n", ver.Image))
+
+	var ports []string
+
+	for _, port := range ver.Ports {
+		description := port.Container + "/" + port.Protocol
+
+		if port.Host != 0 {
+			description += fmt.Sprintf(" on host port %d", port.Host)
+		}
+
+		ports = append(ports, description)
+	}
+
+	if len(ports) > 0 {
+		sb.WriteString(fmt.Sprintf("- **Ports**: *%s*\n", strings.Join(ports, ", ")))
+	} else {
+		sb.WriteString("- **Ports**: *none*\n")
+	}
+
+	if len(ver.Volumes) > 0 {
+		sb.WriteString("- **Volumes**:\n")
+
+		for _, vol := range ver.Volumes {
+			source := vol.Source
+
+			if source == "" {
+				source = "current working directory"
+			}
+
+			sb.WriteString(fmt.Sprintf("  - *%s* mounted at *%s*\n", source, vol.Mount))
+		}
+	}
+
+	if len(ver.Copies) > 0 {
+		sb.WriteString("- **Copies**:\n")
+
+		for _, copy := range ver.Copies {
+			sb.WriteString(fmt.Sprintf("  - *%s* from the container copied to *%s*\n", copy.Source, copy.Dest))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,226 @@
+package subcommands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Test to make sure the info subcommand has the proper name upon creation
+func TestInfoName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	ic := NewInfoCommand(mu, mu.Conf)
+
+	if ic.Name() != "info" {
+		t.Fatal("The info subcommand's name should be: info | Subcommand Name: " + ic.Name())
+	}
+}
+
+//Test to make sure the info subcommand initializes correctly
+func TestInfoInit(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	ic := NewInfoCommand(mu, mu.Conf)
+
+	err := ic.Init([]string{})
+
+	if err == nil {
+		t.Fatal("Expected an error when no pim is passed but did not receive one")
+	}
+
+	err = ic.Init([]string{"python:3.9"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ic.name != "python:3.9" {
+		t.Fatal("pim Name should have been initialized as: python:3.9 but is: " + ic.name)
+	}
+}
+
+//Tests the flow of the info subcommand for an installed pim
+func TestInfoInstalledFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+
+	ic := NewInfoCommand(mu, mu.Conf)
+
+	err := ic.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"FileExists",
+		"GetHCLBody",
+		"ParseBody",
+		"ImageExists",
+		"Emit",
+	}
+
+	//If the call stack doesn't match the test fails
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	hclFiles := []string{mu.Conf.BaseDir + mu.Conf.PimsConfigDir + "python.hcl"}
+
+	if !reflect.DeepEqual(hclFiles, mu.HCLFiles) {
+		t.Fatalf("HCL files read does not match the expected files. HCL Files: %v | Expected HCL Files: %v", mu.HCLFiles, hclFiles)
+	}
+
+	entry, ok := mu.Events[0].Data.(InfoEntry)
+
+	if !ok || entry.Name != "python" || len(entry.Versions) != 1 || !entry.Versions[0].Installed {
+		t.Fatalf("The installed version of the pim should have been emitted as the result | Received: %+v", mu.Events[0].Data)
+	}
+}
+
+//Tests the info subcommand does not leave a pim config behind for a pim that is not installed
+func TestInfoNotInstalledFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.PimConfigShouldExist = false
+
+	ic := NewInfoCommand(mu, mu.Conf)
+
+	err := ic.Init([]string{"python:latest"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"FileExists",
+		"TempDir",
+		"FetchPimConfig",
+		"GetHCLBody",
+		"ParseBody",
+		"ImageExists",
+		"Emit",
+		"RemoveDir",
+	}
+
+	//If the call stack doesn't match the test fails
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	//The pim config should only have been fetched to the temporary directory that is removed afterwards
+	tempDir := "/tmp/packageless-info/"
+
+	if !reflect.DeepEqual([]string{tempDir}, mu.FetchedPaths) {
+		t.Fatalf("Fetched paths does not match the expected paths. Fetched Paths: %v | Expected Fetched Paths: %v", mu.FetchedPaths, []string{tempDir})
+	}
+
+	if !reflect.DeepEqual([]string{tempDir}, mu.RemovedDirs) {
+		t.Fatalf("Removed directories does not match the expected directories. Removed Directories: %v | Expected Removed Directories: %v", mu.RemovedDirs, []string{tempDir})
+	}
+}
+
+//Test the info subcommand when the version does not exist
+func TestInfoNonExistVersion(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	ic := NewInfoCommand(mu, mu.Conf)
+
+	err := ic.Init([]string{"python:nonexistent"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	expectedErr := "Could not find pim python with version 'nonexistent' in the pim configuration"
+
+	if err == nil {
+		t.Fatal("Expected the following error: " + expectedErr + " but did not receive an error")
+	}
+
+	if err.Error() != expectedErr {
+		t.Fatal("Expected the following error: " + expectedErr + "| Received: " + err.Error())
+	}
+}
+
+//Test if an error happens at the FetchPimConfig function
+func TestInfoErrorAtFetchPimConfig(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.PimConfigShouldExist = false
+	mu.ErrorAt = "FetchPimConfig"
+
+	ic := NewInfoCommand(mu, mu.Conf)
+
+	err := ic.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err == nil {
+		t.Fatal("Expected the following error: " + mu.ErrorMsg + " but did not receive an error")
+	}
+
+	if err.Error() != mu.ErrorMsg {
+		t.Fatal("Expected the following error: " + mu.ErrorMsg + "| Received: " + err.Error())
+	}
+
+	//The temporary directory should still be removed
+	callStack := []string{
+		"FileExists",
+		"TempDir",
+		"FetchPimConfig",
+		"RemoveDir",
+	}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+}
+
+//Test the renderVersionInfo function includes every field of the version
+func TestRenderVersionInfo(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	ver := mu.Pim.Pims[0].Versions[0]
+	ver.Volumes = append(ver.Volumes, utils.Volume{Mount: "/run/"})
+
+	info := renderVersionInfo(versionInfo(ver, true, "pims/"))
+
+	expected := []string{
+		"## Version latest",
+		"- **Status**: *installed*",
+		"- **Image**: *packageless/python*",
+		"- **Ports**: *3000/tcp*",
+		"  - *pims/a/path* mounted at */another/one*",
+		"  - *current working directory* mounted at */run/*",
+		"  - */source/path* from the container copied to *pims/destination*",
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(info, line+"\n") {
+			t.Fatalf("renderVersionInfo: Expected the output to contain: %q | Received: %q", line, info)
+		}
+	}
+}
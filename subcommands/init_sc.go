@@ -0,0 +1,160 @@
+package subcommands
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Init Sub-Command Object
+type InitCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Use the default values without prompting for them
+	yes bool
+
+	//Overwrite an existing configuration file
+	force bool
+
+	//Path that the configuration file is written to
+	configPath string
+
+	tools utils.Tools
+
+	//Reader that the answers to the prompts are read from
+	in io.Reader
+
+	//Writer that the prompts are written to
+	out io.Writer
+}
+
+//Instantiation method for a new InitCommand
+func NewInitCommand(tools utils.Tools, configPath string) *InitCommand {
+	//Create a new InitCommand and set the FlagSet
+	ic := &InitCommand{
+		fs:         flag.NewFlagSet("init", flag.ContinueOnError),
+		configPath: configPath,
+		tools:      tools,
+		in:         os.Stdin,
+		out:        os.Stdout,
+	}
+
+	ic.fs.BoolVar(&ic.yes, "yes", false, "use the default values without prompting for them")
+	ic.fs.BoolVar(&ic.force, "force", false, "overwrite the configuration file if it already exists")
+
+	return ic
+}
+
+//Name - Gets the name of the Sub-Command
+func (ic *InitCommand) Name() string {
+	return ic.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (ic *InitCommand) Description() string {
+	return "Create the configuration file"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (ic *InitCommand) Usage() string {
+	return "packageless init [OPTIONS]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (ic *InitCommand) Flags() *flag.FlagSet {
+	return ic.fs
+}
+
+//Init - Parses and Populates values of the Init subcommand
+func (ic *InitCommand) Init(args []string) error {
+	return ic.fs.Parse(args)
+}
+
+//Run - Runs the Init subcommand
+func (ic *InitCommand) Run() error {
+	if ic.tools.FileExists(ic.configPath) && !ic.force {
+		return errors.New("The configuration file " + ic.configPath + " already exists. Use --force to overwrite it")
+	}
+
+	config := utils.DefaultConfig()
+
+	if !ic.yes {
+		err := ic.prompt(&config)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	err := ic.tools.MakeDir(filepath.Dir(ic.configPath))
+
+	if err != nil {
+		return err
+	}
+
+	err = ic.tools.WriteFile(ic.configPath, utils.ConfigHCL(config))
+
+	if err != nil {
+		return err
+	}
+
+	ic.tools.RenderInfoMarkdown(fmt.Sprintf("*Configuration file created at %s*", ic.configPath))
+
+	return nil
+}
+
+//prompt - Asks for every configuration value, keeping the default when the answer is empty.
+//Once there is nothing left to read the defaults are used for the remaining values.
+func (ic *InitCommand) prompt(config *utils.Config) error {
+	reader := bufio.NewReader(ic.in)
+	done := false
+
+	for _, field := range utils.ConfigFields {
+		for !done {
+			value, err := utils.GetConfigValue(*config, field.Name)
+
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(ic.out, "%s - %s [%s]: ", field.Name, field.Description, value)
+
+			answer, err := reader.ReadString('\n')
+
+			if err == io.EOF {
+				done = true
+			} else if err != nil {
+				return err
+			}
+
+			answer = strings.TrimSpace(answer)
+
+			if answer == "" {
+				break
+			}
+
+			//Ask again until a valid value is given
+			err = utils.SetConfigValue(config, field.Name, answer)
+
+			if err == nil {
+				break
+			}
+
+			fmt.Fprintln(ic.out, err.Error())
+		}
+	}
+
+	if done {
+		fmt.Fprintln(ic.out)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package subcommands
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Test to make sure the init subcommand has the proper name upon creation
+func TestInitName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	ic := NewInitCommand(mu, "config.hcl")
+
+	if ic.Name() != "init" {
+		t.Fatal("The init subcommand's name should be: init | Subcommand Name: " + ic.Name())
+	}
+}
+
+//Test to make sure the init subcommand initializes correctly
+func TestInitInit(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	ic := NewInitCommand(mu, "config.hcl")
+
+	err := ic.Init([]string{"--yes", "--force"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ic.yes || !ic.force {
+		t.Fatal("The yes and force flags should have been set")
+	}
+}
+
+//Tests the flow of the init subcommand without prompting
+func TestInitFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.PimConfigShouldExist = false
+
+	ic := NewInitCommand(mu, "home/.packageless/config.hcl")
+
+	err := ic.Init([]string{"--yes"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FileExists", "MakeDir", "WriteFile", "RenderInfoMarkdown"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if !reflect.DeepEqual([]string{"home/.packageless"}, mu.MadeDirs) {
+		t.Fatalf("Expected the configuration directory to be created | Received: %v", mu.MadeDirs)
+	}
+
+	if mu.WrittenFiles["home/.packageless/config.hcl"] != utils.ConfigHCL(utils.DefaultConfig()) {
+		t.Fatalf("Expected the default configuration to be written | Received: %v", mu.WrittenFiles)
+	}
+}
+
+//Test the init subcommand prompts for the values and asks again for invalid ones
+func TestInitPrompt(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.PimConfigShouldExist = false
+
+	out := &bytes.Buffer{}
+
+	ic := NewInitCommand(mu, "config.hcl")
+	ic.in = strings.NewReader("/opt/packageless/\nabc\n4000\n\ntrue")
+	ic.out = out
+
+	err := ic.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := utils.DefaultConfig()
+	expected.BaseDir = "/opt/packageless/"
+	expected.StartPort = 4000
+	expected.Alias = true
+
+	if mu.WrittenFiles["config.hcl"] != utils.ConfigHCL(expected) {
+		t.Fatalf("Expected the prompted configuration to be written | Received: %s", mu.WrittenFiles["config.hcl"])
+	}
+
+	if !strings.Contains(out.String(), "Invalid value for start_port") {
+		t.Fatalf("Expected the invalid start_port to be reported | Received: %s", out.String())
+	}
+}
+
+//Test the init subcommand does not overwrite an existing configuration without the force flag
+func TestInitConfigExists(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	ic := NewInitCommand(mu, "config.hcl")
+
+	err := ic.Init([]string{"--yes"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	exErr := "The configuration file config.hcl already exists. Use --force to overwrite it"
+
+	if err == nil {
+		t.Fatalf("Expected to have error: %s | Received No Error", exErr)
+	}
+
+	if err.Error() != exErr {
+		t.Fatalf("Expected to have error: %s | Received: %s", exErr, err.Error())
+	}
+
+	//The force flag should overwrite the configuration
+	mu.Calls = nil
+
+	err = ic.Init([]string{"--yes", "--force"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FileExists", "MakeDir", "WriteFile", "RenderInfoMarkdown"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+}
@@ -3,11 +3,9 @@ package subcommands
 import (
 	"errors"
 	"flag"
-	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
 
 	"github.com/docker/docker/client"
 	"github.com/everettraven/packageless/utils"
@@ -27,6 +25,9 @@ type InstallCommand struct {
 	cp utils.Copier
 
 	config utils.Config
+
+	//Digest of the image to install instead of the latest image, set by sync
+	digest string
 }
 
 //Instantiation method for a new InstallCommand
@@ -47,43 +48,74 @@ func (ic *InstallCommand) Name() string {
 	return ic.fs.Name()
 }
 
+//Description - Gets the description of the Sub-Command
+func (ic *InstallCommand) Description() string {
+	return "Install pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (ic *InstallCommand) Usage() string {
+	return "packageless install [OPTIONS] [pim...]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (ic *InstallCommand) Flags() *flag.FlagSet {
+	return ic.fs
+}
+
+//Batch - The install subcommand is ran once for every pim passed as an argument
+func (ic *InstallCommand) Batch() bool {
+	return true
+}
+
 //Init - Parses and Populates values of the Install subcommand
 func (ic *InstallCommand) Init(args []string) error {
+	err := ic.fs.Parse(args)
 
-	if len(args) <= 0 {
-		return errors.New("No pim name was found. You must include the name of the pim you wish to install.")
+	if err != nil {
+		return err
+	}
+
+	args = ic.fs.Args()
+
+	//Without a pim name the pims of the project manifest are installed
+	ic.name = ""
+
+	if len(args) > 0 {
+		ic.name = args[0]
 	}
 
-	ic.name = args[0]
 	return nil
 }
 
 //Run - Runs the install subcommand
 func (ic *InstallCommand) Run() error {
+	if ic.name == "" {
+		return ic.installManifest()
+	}
+
 	//Create variables to use later
 	var found bool
 	var pim utils.PackageImage
 	var version utils.Version
 
-	var pimName string
-	var pimVersion string
+	//Versions that are not specified are resolved the same way as when running the pim
+	pimName, pimVersion, _, err := resolvePimName(ic.tools, ic.name)
 
-	if strings.Contains(ic.name, ":") {
-		split := strings.Split(ic.name, ":")
-		pimName = split[0]
-		pimVersion = split[1]
-	} else {
-		pimName = ic.name
-		pimVersion = "latest"
+	if err != nil {
+		return err
 	}
 
+	//Reference to the pim used in the events
+	pimRef := pimName + ":" + pimVersion
+
 	pimConfigDir := ic.config.BaseDir + ic.config.PimsConfigDir
 	pimPath := pimConfigDir + pimName + ".hcl"
 
 	pimDir := ic.config.BaseDir + ic.config.PimsDir
 
 	//Make the pim config and pim directory if they do not already exist
-	err := ic.tools.MakeDir(pimConfigDir)
+	err = ic.tools.MakeDir(pimConfigDir)
 
 	if err != nil {
 		return err
@@ -97,10 +129,27 @@ func (ic *InstallCommand) Run() error {
 
 	//Check if pim config already exists
 	if !ic.tools.FileExists(pimPath) {
+		stepStarted(ic.tools, pimRef, "fetch_config", "")
+
 		err := ic.tools.FetchPimConfig(ic.config.RepositoryHost, pimName, pimConfigDir)
 		if err != nil {
 			return err
 		}
+
+		//The index is fetched with the pim configuration so that it lists the same checksums
+		checksums, err := fetchIndexChecksums(ic.tools, ic.config, pimRef)
+		if err != nil {
+			return err
+		}
+
+		//A pim configuration that does not match the repository index is not kept
+		err = verifyPimConfig(ic.tools, pimName, pimPath, checksums[pimName])
+		if err != nil {
+			ic.tools.RemoveFile(pimPath)
+			return err
+		}
+
+		stepFinished(ic.tools, pimRef, "fetch_config")
 	}
 
 	//Create the Docker client
@@ -156,19 +205,23 @@ func (ic *InstallCommand) Run() error {
 
 	//If the image exists the pim is already installed
 	if imgExist {
-		return errors.New("pim " + pim.Name + " is already installed")
+		return Skip("pim " + pim.Name + " is already installed")
 	}
 
-	ic.tools.RenderInfoMarkdown(fmt.Sprintf("**Installing**: *%s*", pim.Name+":"+version.Version))
+	ic.tools.Emit(utils.Event{Type: utils.EventStarted, Pim: pimRef, Message: "Installing"})
+
 	//Pull the image down from Docker Hub
-	ic.tools.RenderInfoMarkdown(fmt.Sprintf("- *Pulling image %s*", version.Image))
-	err = ic.tools.PullImage(version.Image, cli)
+	stepStarted(ic.tools, pimRef, "pull_image", "Pulling image "+version.Image)
+
+	err = pullImage(ic.tools, version.Image, ic.digest, cli)
 
 	if err != nil {
 		return err
 	}
 
-	ic.tools.RenderInfoMarkdown("- *Creating pim directories*")
+	stepFinished(ic.tools, pimRef, "pull_image")
+
+	stepStarted(ic.tools, pimRef, "create_directories", "Creating pim directories")
 
 	//Create the base directory for the pim
 	err = ic.tools.MakeDir(pimDir + pim.BaseDir)
@@ -189,10 +242,12 @@ func (ic *InstallCommand) Run() error {
 		}
 	}
 
+	stepFinished(ic.tools, pimRef, "create_directories")
+
 	//Check and see if any files need to be copied from the container to one of the volumes on the host.
 	if len(version.Copies) > 0 {
 
-		ic.tools.RenderInfoMarkdown("- *Copying necessary files (create container)*")
+		stepStarted(ic.tools, pimRef, "create_container", "Copying necessary files (create container)")
 
 		//Create the container so that we can copy the files over to the right places
 		containerID, err := ic.tools.CreateContainer(version.Image, cli)
@@ -201,7 +256,9 @@ func (ic *InstallCommand) Run() error {
 			return err
 		}
 
-		ic.tools.RenderInfoMarkdown("- *Copying necessary files (copy files from container)*")
+		stepFinished(ic.tools, pimRef, "create_container")
+
+		stepStarted(ic.tools, pimRef, "copy_files", "Copying necessary files (copy files from container)")
 
 		//Copy the files from the container to the locations
 		for _, copy := range version.Copies {
@@ -212,7 +269,9 @@ func (ic *InstallCommand) Run() error {
 			}
 		}
 
-		ic.tools.RenderInfoMarkdown("- *Copying necessary files (remove container)*")
+		stepFinished(ic.tools, pimRef, "copy_files")
+
+		stepStarted(ic.tools, pimRef, "remove_container", "Copying necessary files (remove container)")
 
 		//Remove the Container
 		err = ic.tools.RemoveContainer(containerID, cli)
@@ -221,6 +280,8 @@ func (ic *InstallCommand) Run() error {
 			return err
 		}
 
+		stepFinished(ic.tools, pimRef, "remove_container")
+
 	}
 
 	//get the executable directory for setting the aliases
@@ -234,7 +295,7 @@ func (ic *InstallCommand) Run() error {
 
 	if ic.config.Alias {
 		//Set the alias for the command
-		ic.tools.RenderInfoMarkdown("- *Setting alias*")
+		stepStarted(ic.tools, pimRef, "set_alias", "Setting alias")
 
 		if runtime.GOOS == "windows" {
 			if version.Version != "latest" {
@@ -253,10 +314,39 @@ func (ic *InstallCommand) Run() error {
 		if err != nil {
 			return err
 		}
+
+		stepFinished(ic.tools, pimRef, "set_alias")
 	}
 
-	ic.tools.RenderInfoMarkdown("***")
-	ic.tools.RenderInfoMarkdown(fmt.Sprintf("*%s* **successfully installed**", pim.Name))
+	ic.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: pimRef, Message: "successfully installed"})
 
 	return nil
 }
+
+//installManifest - Installs every pim listed in the project manifest, as if they were passed as arguments
+func (ic *InstallCommand) installManifest() error {
+	pims, path, err := findManifest(ic.tools)
+
+	if err != nil {
+		return err
+	}
+
+	if path == "" {
+		return utils.WithErrorCode(utils.ErrorCodeInvalidArguments, errors.New("No pim name was found. You must include the name of the pim you wish to install, or run install in a project with a "+utils.ManifestFile+" manifest."))
+	}
+
+	if len(pims) == 0 {
+		ic.tools.Emit(utils.Event{Type: utils.EventMessage, Message: "The project manifest " + path + " does not list any pims"})
+		return nil
+	}
+
+	var refs []string
+
+	for _, mp := range pims {
+		refs = append(refs, mp.Ref())
+	}
+
+	ic.tools.Emit(utils.Event{Type: utils.EventMessage, Message: "Installing the pims of the project manifest " + path})
+
+	return runBatch(NewInstallCommand(ic.tools, ic.cp, ic.config), nil, refs, false, ic.tools)
+}
@@ -95,28 +95,35 @@ func TestInstallFlow(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"MakeDir",
 		"MakeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"AddAlias",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -205,6 +212,33 @@ func TestInstallFlow(t *testing.T) {
 		t.Fatalf("AddAlias Alias Commands does not match the expected Alias Commands. Alias Commands: %v | Expected Alias Commands: %v", mu.CmdToAlias, aliasCmds)
 	}
 
+	//Every step should have been started and finished in order
+	var events []string
+
+	for _, event := range mu.Events {
+		events = append(events, event.Type+" "+event.Step)
+	}
+
+	expectedEvents := []string{
+		"started ",
+		"step_started pull_image",
+		"step_finished pull_image",
+		"step_started create_directories",
+		"step_finished create_directories",
+		"step_started create_container",
+		"step_finished create_container",
+		"step_started copy_files",
+		"step_finished copy_files",
+		"step_started remove_container",
+		"step_finished remove_container",
+		"step_started set_alias",
+		"step_finished set_alias",
+		"finished ",
+	}
+
+	if !reflect.DeepEqual(expectedEvents, events) {
+		t.Fatalf("Events do not match the expected events. Events: %v | Expected Events: %v", events, expectedEvents)
+	}
 }
 
 //Test the install subcommand getting an error after calling the GetHCLBody function
@@ -247,6 +281,8 @@ func TestInstallErrorAtGetHCLBody(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
@@ -299,6 +335,8 @@ func TestInstallErrorAtParseBody(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
@@ -352,6 +390,8 @@ func TestInstallErrorAtImageExists(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
@@ -406,14 +446,16 @@ func TestInstallErrorAtPullImage(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
 	}
 
@@ -463,6 +505,8 @@ func TestInstallErrorAtMakeDir(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 	}
 
@@ -512,19 +556,23 @@ func TestInstallErrorAtCreateContainer(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"MakeDir",
 		"MakeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
 	}
 
@@ -574,21 +622,26 @@ func TestInstallErrorAtCopyFromContainer(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"MakeDir",
 		"MakeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
 	}
 
@@ -638,23 +691,29 @@ func TestInstallErrorAtRemoveContainer(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"MakeDir",
 		"MakeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveContainer",
 	}
 
@@ -704,25 +763,32 @@ func TestInstallErrorAtAddAlias(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"MakeDir",
 		"MakeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"AddAlias",
 	}
 
@@ -774,6 +840,8 @@ func TestInstallImageExists(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
@@ -787,11 +855,11 @@ func TestInstallImageExists(t *testing.T) {
 	}
 }
 
-//Test the install subcommand with no arguments passed
+//Test the install subcommand with no arguments passed outside of a project
 func TestInstallNoPackage(t *testing.T) {
 	mu := utils.NewMockUtility()
 
-	expectedErr := "No pim name was found. You must include the name of the pim you wish to install."
+	expectedErr := "No pim name was found. You must include the name of the pim you wish to install, or run install in a project with a .packageless.hcl manifest."
 
 	mcp := &utils.MockCopyTool{}
 
@@ -811,6 +879,12 @@ func TestInstallNoPackage(t *testing.T) {
 
 	err := ic.Init(args)
 
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
 	if err == nil {
 		t.Fatal("Expected the following error: '" + expectedErr + "' but did not receive an error")
 	}
@@ -860,6 +934,8 @@ func TestInstallNonExistPackage(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
@@ -906,26 +982,32 @@ func TestInstallAliasFalse(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"MakeDir",
 		"MakeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveContainer",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -1095,29 +1177,43 @@ func TestInstallFlowPimConfigNotExist(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
+		"Emit",
 		"FetchPimConfig",
+		"MakeDir",
+		"Emit",
+		"FetchIndex",
+		"Emit",
+		"FileExists",
+		"Emit",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"MakeDir",
 		"MakeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"AddAlias",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -1143,6 +1239,9 @@ func TestInstallFlowPimConfigNotExist(t *testing.T) {
 
 	mkdirs = append(mkdirs, pimConfigDir)
 	mkdirs = append(mkdirs, pimDir)
+	//The repository index is fetched with the pim configuration
+	mkdirs = append(mkdirs, config.BaseDir)
+
 	//Fill lists
 	for _, pim := range mu.Pim.Pims {
 		//Just use the first version
@@ -1249,9 +1348,12 @@ func TestInstallErrorAtFetchPimConfig(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"MakeDir",
 		"MakeDir",
 		"FileExists",
+		"Emit",
 		"FetchPimConfig",
 	}
 
@@ -1260,3 +1362,148 @@ func TestInstallErrorAtFetchPimConfig(t *testing.T) {
 		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
 	}
 }
+
+//Test the install subcommand with no arguments installs the pims of the project manifest
+func TestInstallManifest(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.PimConfigShouldExist = true
+
+	manifest := "project/" + utils.ManifestFile
+
+	mu.FoundFiles = map[string]string{utils.ManifestFile: manifest}
+	mu.FileContents = map[string]string{manifest: "pim \"python\" {\n  version = \"latest\"\n}\n"}
+
+	ic := NewInstallCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := ic.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{"packageless/python"}, mu.PulledImgs) {
+		t.Fatalf("Install: Expected the pim of the project manifest to be installed | Received: %v", mu.PulledImgs)
+	}
+
+	last := mu.Events[len(mu.Events)-1]
+	expected := []BatchResult{{Pim: "python:latest", Status: StatusSucceeded}}
+
+	if !reflect.DeepEqual(expected, last.Data) {
+		t.Fatalf("Install: Expected the batch results: %v | Received: %v", expected, last.Data)
+	}
+}
+
+//Test the version of a pim that is installed without a version is taken from the project manifest
+func TestInstallManifestVersion(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.PimConfigShouldExist = true
+	mu.Pim.Pims[0].Versions = append(mu.Pim.Pims[0].Versions, utils.Version{Version: "3.9", Image: "packageless/python:3.9"})
+
+	manifest := "project/" + utils.ManifestFile
+
+	mu.FoundFiles = map[string]string{utils.ManifestFile: manifest}
+	mu.FileContents = map[string]string{manifest: "pim \"python\" {\n  version = \"3.9\"\n}\n"}
+
+	ic := NewInstallCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := ic.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{"packageless/python:3.9"}, mu.PulledImgs) {
+		t.Fatalf("Install: Expected the version of the project manifest to be installed | Received: %v", mu.PulledImgs)
+	}
+
+	if mu.Events[0].Pim != "python:3.9" {
+		t.Fatalf("Install: Expected python:3.9 to be installed | Received: %s", mu.Events[0].Pim)
+	}
+}
+
+//Test a downloaded pim configuration is only kept when it matches the checksum of the repository index
+func TestInstallVerifiesChecksum(t *testing.T) {
+	content := "pim \"python\" {}\n"
+
+	for _, checksum := range []string{utils.Checksum([]byte(content)), "sha256:tampered"} {
+		mu := utils.NewMockUtility()
+
+		pimPath := mu.Conf.BaseDir + mu.Conf.PimsConfigDir + "python.hcl"
+
+		mu.PimConfigShouldExist = false
+		mu.ExistingFiles = map[string]bool{mu.Conf.BaseDir + utils.IndexFile: true}
+		mu.FileContents = map[string]string{pimPath: content}
+		mu.Index = utils.RepositoryIndex{Pims: []utils.IndexPim{{Name: "python", Checksum: checksum}}}
+
+		ic := NewInstallCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+		err := ic.Init([]string{"python:latest"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = ic.Run()
+
+		if checksum != "sha256:tampered" {
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			continue
+		}
+
+		exErr := "The downloaded pim configuration of python does not match the checksum sha256:tampered listed in the repository index"
+
+		if err == nil || err.Error() != exErr {
+			t.Fatalf("Install: Expected the error: %s | Received: %v", exErr, err)
+		}
+
+		if !reflect.DeepEqual([]string{pimPath}, mu.RemovedFiles) {
+			t.Fatalf("Install: Expected the pim configuration to be removed | Removed Files: %v", mu.RemovedFiles)
+		}
+	}
+}
+
+//Test a pim configuration is not verified against a cached repository index that could not be updated, as it may list older checksums
+func TestInstallStaleIndex(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	pimPath := mu.Conf.BaseDir + mu.Conf.PimsConfigDir + "python.hcl"
+
+	mu.ErrorAt = "FetchIndex"
+	mu.PimConfigShouldExist = false
+	mu.ExistingFiles = map[string]bool{mu.Conf.BaseDir + utils.IndexFile: true}
+	mu.FileContents = map[string]string{pimPath: "pim \"python\" {}\n"}
+	mu.Index = utils.RepositoryIndex{Pims: []utils.IndexPim{{Name: "python", Checksum: "sha256:stale"}}}
+
+	ic := NewInstallCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := ic.Init([]string{"python:latest"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mu.RemovedFiles) != 0 {
+		t.Fatalf("Install: Expected the pim configuration to be kept | Removed Files: %v", mu.RemovedFiles)
+	}
+}
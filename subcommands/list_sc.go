@@ -0,0 +1,302 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//List Sub-Command Object
+type ListCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Include versions whose pim config exists but whose image is not downloaded
+	all bool
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//ListEntry - Details of a single installed pim version
+type ListEntry struct {
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	Image     string `json:"image"`
+	Digest    string `json:"digest"`
+	ImageSize int64  `json:"image_size"`
+	DataSize  int64  `json:"data_size"`
+	Alias     bool   `json:"alias"`
+	Installed bool   `json:"installed"`
+	Pinned    bool   `json:"pinned"`
+}
+
+//Instantiation method for a new ListCommand
+func NewListCommand(tools utils.Tools, config utils.Config) *ListCommand {
+	//Create a new ListCommand and set the FlagSet
+	lc := &ListCommand{
+		fs:     flag.NewFlagSet("list", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	lc.fs.BoolVar(&lc.all, "all", false, "include versions whose configuration exists but whose image is not downloaded")
+
+	return lc
+}
+
+//Name - Gets the name of the Sub-Command
+func (lc *ListCommand) Name() string {
+	return lc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (lc *ListCommand) Description() string {
+	return "List the installed pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (lc *ListCommand) Usage() string {
+	return "packageless list [OPTIONS]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (lc *ListCommand) Flags() *flag.FlagSet {
+	return lc.fs
+}
+
+//Init - Parses and Populates values of the List subcommand
+func (lc *ListCommand) Init(args []string) error {
+	return lc.fs.Parse(args)
+}
+
+//Run - Runs the List subcommand
+func (lc *ListCommand) Run() error {
+	pimConfigDir := lc.config.BaseDir + lc.config.PimsConfigDir
+	pimDir := lc.config.BaseDir + lc.config.PimsDir
+
+	//Nothing has been installed yet if the directory does not exist
+	if !lc.tools.FileExists(pimConfigDir) {
+		lc.emit([]ListEntry{})
+		return nil
+	}
+
+	//Get list of installed pims
+	pimNames, err := lc.tools.GetListOfInstalledPimConfigs(pimConfigDir)
+
+	if err != nil {
+		return errors.New("Encountered an error while trying to fetch list of installed pim configuration files: " + err.Error())
+	}
+
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	//get the executable directory for checking the aliases
+	ex, err := os.Executable()
+
+	if err != nil {
+		return err
+	}
+
+	executableDir := filepath.Dir(ex)
+
+	holds, err := loadHolds(lc.tools, lc.config)
+
+	if err != nil {
+		return err
+	}
+
+	entries := []ListEntry{}
+
+	for _, pimName := range pimNames {
+		pimListBody, err := lc.tools.GetHCLBody(pimConfigDir + pimName + ".hcl")
+
+		if err != nil {
+			return err
+		}
+
+		//Parse the pim list
+		parseOut, err := lc.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+		if err != nil {
+			return err
+		}
+
+		pims := parseOut.(utils.PimHCLUtil)
+
+		for _, pim := range pims.Pims {
+			for _, ver := range pim.Versions {
+				//Check the version against the images Docker has
+				img, imgExist, err := lc.tools.FindImage(ver.Image, cli)
+
+				if err != nil {
+					return err
+				}
+
+				if !imgExist && !lc.all {
+					continue
+				}
+
+				entry := ListEntry{
+					Name:      pim.Name,
+					Version:   ver.Version,
+					Image:     ver.Image,
+					Installed: imgExist,
+					Pinned:    utils.IsHeld(holds, pim.Name, ver.Version),
+				}
+
+				if imgExist {
+					entry.Digest = imageDigest(img.ID, img.RepoDigests)
+					entry.ImageSize = img.Size
+				}
+
+				//Add up the size of the directories created for the volumes
+				for _, vol := range ver.Volumes {
+					if vol.Path != "" {
+						size, err := lc.tools.DirSize(pimDir + vol.Path)
+
+						if err != nil {
+							return err
+						}
+
+						entry.DataSize += size
+					}
+				}
+
+				if lc.config.Alias {
+					aliasName := pim.Name
+
+					if ver.Version != "latest" {
+						aliasName = pim.Name + ":" + ver.Version
+					}
+
+					//An alias that can not be checked, e.g. because of an unsupported shell, is reported as not set
+					if runtime.GOOS == "windows" {
+						entry.Alias, _ = lc.tools.AliasExistsWin(aliasName, executableDir)
+					} else {
+						entry.Alias, _ = lc.tools.AliasExistsUnix(aliasName, executableDir)
+					}
+				}
+
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	lc.emit(entries)
+
+	return nil
+}
+
+//emit - Emits the installed pims as the result of the subcommand
+func (lc *ListCommand) emit(entries []ListEntry) {
+	lc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  listText(entries),
+		Data:     entries,
+		Markdown: renderListTable(entries),
+	})
+}
+
+//imageDigest - Gets the digest of an image from its repo digests, falling back to the image ID for images without one
+func imageDigest(id string, repoDigests []string) string {
+	for _, repoDigest := range repoDigests {
+		if i := strings.Index(repoDigest, "@"); i >= 0 {
+			return repoDigest[i+1:]
+		}
+	}
+
+	return id
+}
+
+//listText - Creates the plain text lines of the installed pims
+func listText(entries []ListEntry) string {
+	if len(entries) == 0 {
+		return "No pims are installed"
+	}
+
+	var lines []string
+
+	for _, entry := range entries {
+		line := fmt.Sprintf("%s:%s %s", entry.Name, entry.Version, entry.Image)
+
+		if !entry.Installed {
+			line += " not downloaded"
+		}
+
+		if entry.Pinned {
+			line += " (pinned)"
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+//formatBytes - Formats a number of bytes as a human readable size
+func formatBytes(bytes int64) string {
+	const unit = 1024
+
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+//renderListTable - Creates a markdown table of the installed pims
+func renderListTable(entries []ListEntry) string {
+	if len(entries) == 0 {
+		return "*No pims are installed*"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("# Installed pims\n")
+	sb.WriteString("| pim | version | image | digest | image size | data size | alias | pinned |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+
+	for _, entry := range entries {
+		digest := entry.Digest
+		imageSize := formatBytes(entry.ImageSize)
+
+		if !entry.Installed {
+			digest = "not downloaded"
+			imageSize = "-"
+		}
+
+		alias := "no"
+
+		if entry.Alias {
+			alias = "yes"
+		}
+
+		pinned := "no"
+
+		if entry.Pinned {
+			pinned = "yes"
+		}
+
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s | %s |\n", entry.Name, entry.Version, entry.Image, digest, imageSize, formatBytes(entry.DataSize), alias, pinned))
+	}
+
+	return sb.String()
+}
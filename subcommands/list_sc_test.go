@@ -0,0 +1,266 @@
+package subcommands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Test to make sure the list subcommand has the proper name upon creation
+func TestListName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	lc := NewListCommand(mu, mu.Conf)
+
+	if lc.Name() != "list" {
+		t.Fatal("The list subcommand's name should be: list | Subcommand Name: " + lc.Name())
+	}
+}
+
+//Test to make sure the list subcommand parses its flags
+func TestListInit(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	lc := NewListCommand(mu, mu.Conf)
+
+	err := lc.Init([]string{"--all"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !lc.all {
+		t.Fatal("The all flag should have been set")
+	}
+}
+
+//Tests the flow of a correctly ran list subcommand
+func TestListFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.InstalledPims = []string{"python"}
+	mu.ImgExist = true
+	mu.AliasExist = true
+	mu.DirSizeRet = 2048
+	mu.FileContents = map[string]string{mu.Conf.BaseDir + utils.HoldsFile: utils.HoldsHCL([]utils.Hold{{Pim: "python"}})}
+	mu.ImgSummary = types.ImageSummary{
+		ID:          "sha256:imageid",
+		RepoDigests: []string{"packageless/python@sha256:digest"},
+		Size:        1024,
+	}
+
+	lc := NewListCommand(mu, mu.Conf)
+
+	err := lc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Set a variable with the proper call stack and see if the call stack matches
+	callStack := []string{
+		"FileExists",
+		"GetListOfInstalledPimConfigs",
+		"ReadFile",
+		"GetHCLBody",
+		"ParseBody",
+		"FindImage",
+		"DirSize",
+		"AliasExists",
+		"Emit",
+	}
+
+	//If the call stack doesn't match the test fails
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if mu.Events[0].Type != utils.EventResult {
+		t.Fatalf("The list should have been emitted as a result | Received: %+v", mu.Events[0])
+	}
+
+	expected := []ListEntry{
+		{
+			Name:      "python",
+			Version:   "latest",
+			Image:     "packageless/python",
+			Digest:    "sha256:digest",
+			ImageSize: 1024,
+			DataSize:  2048,
+			Alias:     true,
+			Installed: true,
+			Pinned:    true,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, mu.Events[0].Data) {
+		t.Fatalf("List entries do not match the expected entries. Entries: %v | Expected Entries: %v", mu.Events[0].Data, expected)
+	}
+
+	//Make sure the data size is calculated from the volume directories
+	sizedDirs := []string{mu.Conf.BaseDir + mu.Conf.PimsDir + "a/path"}
+
+	if !reflect.DeepEqual(sizedDirs, mu.SizedDirs) {
+		t.Fatalf("Sized directories do not match the expected directories. Sized Directories: %v | Expected Sized Directories: %v", mu.SizedDirs, sizedDirs)
+	}
+}
+
+//Test that versions without a downloaded image are only listed with --all
+func TestListImageNotExists(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.InstalledPims = []string{"python"}
+
+	lc := NewListCommand(mu, mu.Conf)
+
+	err := lc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entries := mu.Events[0].Data.([]ListEntry); len(entries) != 0 {
+		t.Fatalf("No pims should have been listed | Received: %v", entries)
+	}
+
+	lc = NewListCommand(mu, mu.Conf)
+	mu.Events = nil
+
+	err = lc.Init([]string{"--all"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := mu.Events[0].Data.([]ListEntry)
+
+	if len(entries) != 1 || entries[0].Installed {
+		t.Fatalf("The version that is not downloaded should have been listed | Received: %v", entries)
+	}
+}
+
+//Test the list subcommand reports no pims when nothing has been installed yet
+func TestListNoPimConfigDir(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.PimConfigShouldExist = false
+
+	lc := NewListCommand(mu, mu.Conf)
+
+	err := lc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FileExists", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if entries := mu.Events[0].Data.([]ListEntry); len(entries) != 0 {
+		t.Fatalf("No pims should have been listed | Received: %v", entries)
+	}
+
+	if mu.Events[0].Markdown != "*No pims are installed*" {
+		t.Fatalf("The list should have reported that no pims are installed | Received: %s", mu.Events[0].Markdown)
+	}
+}
+
+//Test if an error happens at the GetListOfInstalledPimConfigs function
+func TestListErrorAtGetListOfInstalledPimConfigs(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ErrorAt = "GetListOfInstalledPimConfigs"
+
+	lc := NewListCommand(mu, mu.Conf)
+
+	err := lc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lc.Run()
+
+	expectedErr := "Encountered an error while trying to fetch list of installed pim configuration files: " + mu.ErrorMsg
+
+	if err == nil {
+		t.Fatal("Expected the following error: " + expectedErr + " but did not receive an error")
+	}
+
+	if err.Error() != expectedErr {
+		t.Fatal("Expected the following error: " + expectedErr + "| Received: " + err.Error())
+	}
+}
+
+//Test if an error happens at the FindImage function
+func TestListErrorAtFindImage(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.InstalledPims = []string{"python"}
+	mu.ErrorAt = "FindImage"
+
+	lc := NewListCommand(mu, mu.Conf)
+
+	err := lc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lc.Run()
+
+	if err == nil {
+		t.Fatal("Expected the following error: " + mu.ErrorMsg + " but did not receive an error")
+	}
+
+	if err.Error() != mu.ErrorMsg {
+		t.Fatal("Expected the following error: " + mu.ErrorMsg + "| Received: " + err.Error())
+	}
+}
+
+//Test the formatBytes function
+func TestFormatBytes(t *testing.T) {
+	cases := map[int64]string{
+		0:                      "0 B",
+		1023:                   "1023 B",
+		1024:                   "1.0 KiB",
+		1536:                   "1.5 KiB",
+		5 * 1024 * 1024:        "5.0 MiB",
+		3 * 1024 * 1024 * 1024: "3.0 GiB",
+	}
+
+	for bytes, expected := range cases {
+		if formatBytes(bytes) != expected {
+			t.Fatalf("formatBytes(%d): Expected: %s | Received: %s", bytes, expected, formatBytes(bytes))
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Outdated Sub-Command Object
+type OutdatedCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//OutdatedEntry - Details of an installed pim version that has a newer image or a newer pim configuration
+type OutdatedEntry struct {
+	Name         string `json:"name"`
+	Version      string `json:"version"`
+	Image        string `json:"image"`
+	LocalDigest  string `json:"local_digest"`
+	RemoteDigest string `json:"remote_digest"`
+	NewerImage   bool   `json:"newer_image"`
+	NewerConfig  bool   `json:"newer_config"`
+	Pinned       bool   `json:"pinned"`
+}
+
+//Instantiation method for a new OutdatedCommand
+func NewOutdatedCommand(tools utils.Tools, config utils.Config) *OutdatedCommand {
+	//Create a new OutdatedCommand and set the FlagSet
+	oc := &OutdatedCommand{
+		fs:     flag.NewFlagSet("outdated", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	return oc
+}
+
+//Name - Gets the name of the Sub-Command
+func (oc *OutdatedCommand) Name() string {
+	return oc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (oc *OutdatedCommand) Description() string {
+	return "List the installed pims that have a newer image or a newer pim configuration"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (oc *OutdatedCommand) Usage() string {
+	return "packageless outdated"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (oc *OutdatedCommand) Flags() *flag.FlagSet {
+	return oc.fs
+}
+
+//Init - Parses and Populates values of the Outdated subcommand
+func (oc *OutdatedCommand) Init(args []string) error {
+	return oc.fs.Parse(args)
+}
+
+//Run - Runs the Outdated subcommand
+func (oc *OutdatedCommand) Run() error {
+	pimConfigDir := oc.config.BaseDir + oc.config.PimsConfigDir
+
+	//Nothing has been installed yet if the directory does not exist
+	if !oc.tools.FileExists(pimConfigDir) {
+		oc.tools.Emit(utils.Event{
+			Type:     utils.EventResult,
+			Message:  "No pims are installed",
+			Data:     []OutdatedEntry{},
+			Markdown: "*No pims are installed*",
+		})
+
+		return nil
+	}
+
+	//Get list of installed pims
+	pimNames, err := oc.tools.GetListOfInstalledPimConfigs(pimConfigDir)
+
+	if err != nil {
+		return errors.New("Encountered an error while trying to fetch list of installed pim configuration files: " + err.Error())
+	}
+
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	holds, err := loadHolds(oc.tools, oc.config)
+
+	if err != nil {
+		return err
+	}
+
+	//The pim configurations of the repository are fetched to a temporary directory to compare them with the installed ones
+	tempDir, err := oc.tools.TempDir("packageless-outdated")
+
+	if err != nil {
+		return err
+	}
+
+	defer oc.tools.RemoveDir(tempDir)
+
+	entries := []OutdatedEntry{}
+
+	for _, pimName := range pimNames {
+		pimPath := pimConfigDir + pimName + ".hcl"
+
+		pimListBody, err := oc.tools.GetHCLBody(pimPath)
+
+		if err != nil {
+			return err
+		}
+
+		//Parse the pim list
+		parseOut, err := oc.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+		if err != nil {
+			return err
+		}
+
+		pims := parseOut.(utils.PimHCLUtil)
+
+		//The pim configuration is only compared once some version of the pim turns out to be installed
+		configChecked := false
+		newerConfig := false
+
+		for _, pim := range pims.Pims {
+			for _, ver := range pim.Versions {
+				img, imgExist, err := oc.tools.FindImage(ver.Image, cli)
+
+				if err != nil {
+					return err
+				}
+
+				//Versions that are not installed can not be outdated
+				if !imgExist {
+					continue
+				}
+
+				if !configChecked {
+					configChecked = true
+					newerConfig = oc.newerConfig(pimName, pimPath, tempDir)
+				}
+
+				entry := OutdatedEntry{
+					Name:        pim.Name,
+					Version:     ver.Version,
+					Image:       ver.Image,
+					LocalDigest: repoDigest(ver.Image, img.RepoDigests),
+					NewerConfig: newerConfig,
+					Pinned:      utils.IsHeld(holds, pim.Name, ver.Version),
+				}
+
+				pimRef := pim.Name + ":" + ver.Version
+
+				//Images that were not pulled from a registry have nothing to be compared with
+				if entry.LocalDigest == "" {
+					oc.tools.Emit(utils.Event{Type: utils.EventWarning, Pim: pimRef, Message: "Could not check the image of " + pimRef + ", it was not pulled from a registry"})
+				} else {
+					entry.RemoteDigest, err = oc.tools.RemoteDigest(ver.Image, cli)
+
+					//A registry that can not be reached should not prevent checking the other pims
+					if err != nil {
+						oc.tools.Emit(utils.Event{Type: utils.EventWarning, Pim: pimRef, Message: "Could not check the image of " + pimRef + " in the registry: " + err.Error()})
+					} else {
+						entry.NewerImage = entry.RemoteDigest != entry.LocalDigest
+					}
+				}
+
+				if entry.NewerImage || entry.NewerConfig {
+					entries = append(entries, entry)
+				}
+			}
+		}
+	}
+
+	oc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  outdatedText(entries),
+		Data:     entries,
+		Markdown: renderOutdated(entries),
+	})
+
+	return nil
+}
+
+//newerConfig - Checks if the repository has a pim configuration that differs from the installed one.
+//Problems fetching it are reported as warnings and the pim configuration is considered up to date.
+func (oc *OutdatedCommand) newerConfig(pimName string, pimPath string, tempDir string) bool {
+	err := oc.tools.FetchPimConfig(oc.config.RepositoryHost, pimName, tempDir)
+
+	if err != nil {
+		oc.tools.Emit(utils.Event{Type: utils.EventWarning, Pim: pimName, Message: "Could not check the pim configuration of " + pimName + ": " + err.Error()})
+		return false
+	}
+
+	installed, err := oc.tools.ReadFile(pimPath)
+
+	if err != nil {
+		oc.tools.Emit(utils.Event{Type: utils.EventWarning, Pim: pimName, Message: "Could not check the pim configuration of " + pimName + ": " + err.Error()})
+		return false
+	}
+
+	latest, err := oc.tools.ReadFile(tempDir + pimName + ".hcl")
+
+	if err != nil {
+		oc.tools.Emit(utils.Event{Type: utils.EventWarning, Pim: pimName, Message: "Could not check the pim configuration of " + pimName + ": " + err.Error()})
+		return false
+	}
+
+	//Line endings depend on how the file was checked out in the repository
+	return strings.ReplaceAll(installed, "\r\n", "\n") != strings.ReplaceAll(latest, "\r\n", "\n")
+}
+
+//repoDigest - Gets the digest of the manifest the image was pulled with, empty if the image was not pulled from a registry
+func repoDigest(image string, repoDigests []string) string {
+	repository := imageRepository(image)
+
+	for _, rd := range repoDigests {
+		if i := strings.Index(rd, "@"); i >= 0 && rd[:i] == repository {
+			return rd[i+1:]
+		}
+	}
+
+	return ""
+}
+
+//imageRepository - Gets the repository of an image reference, without its tag or digest
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+
+	//A colon before the last slash belongs to the port of the registry host
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+
+	return image
+}
+
+//outdatedChanges - Describes what is newer for an outdated pim version
+func outdatedChanges(entry OutdatedEntry) string {
+	var changes []string
+
+	if entry.NewerImage {
+		changes = append(changes, "newer image")
+	}
+
+	if entry.NewerConfig {
+		changes = append(changes, "newer pim configuration")
+	}
+
+	return strings.Join(changes, ", ")
+}
+
+//renderOutdated - Creates the markdown table listing the outdated pims
+func renderOutdated(entries []OutdatedEntry) string {
+	if len(entries) == 0 {
+		return "*Every installed pim is up to date*"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("# Outdated pims\n")
+	sb.WriteString("| pim | version | newer | local digest | remote digest | pinned |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+
+	for _, entry := range entries {
+		pinned := "no"
+
+		if entry.Pinned {
+			pinned = "yes"
+		}
+
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s |\n", entry.Name, entry.Version, outdatedChanges(entry), entry.LocalDigest, entry.RemoteDigest, pinned))
+	}
+
+	return sb.String()
+}
+
+//outdatedText - Creates the plain text listing the outdated pims, one line per pim version
+func outdatedText(entries []OutdatedEntry) string {
+	if len(entries) == 0 {
+		return "Every installed pim is up to date"
+	}
+
+	var lines []string
+
+	for _, entry := range entries {
+		line := fmt.Sprintf("%s:%s %s", entry.Name, entry.Version, outdatedChanges(entry))
+
+		if entry.Pinned {
+			line += " (pinned)"
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
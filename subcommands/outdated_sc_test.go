@@ -0,0 +1,198 @@
+package subcommands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Create a mock utility with python installed from a registry, with the same pim configuration as the repository
+func outdatedTestUtility() *utils.MockUtility {
+	mu := utils.NewMockUtility()
+
+	mu.InstalledPims = []string{"python"}
+	mu.ImgExist = true
+	mu.ImgSummary = types.ImageSummary{
+		ID:          "sha256:imageid",
+		RepoDigests: []string{"packageless/python@sha256:local"},
+	}
+
+	mu.RemoteDigests = map[string]string{"packageless/python": "sha256:local"}
+
+	mu.FileContents = map[string]string{
+		mu.Conf.BaseDir + mu.Conf.PimsConfigDir + "python.hcl": "pim \"python\" {}\n",
+		"/tmp/packageless-outdated/python.hcl":                 "pim \"python\" {}\r\n",
+	}
+
+	return mu
+}
+
+func TestOutdatedName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	oc := NewOutdatedCommand(mu, mu.Conf)
+
+	if oc.Name() != "outdated" {
+		t.Fatalf("The outdated subcommand's name should be: 'outdated' but was '%s'", oc.Name())
+	}
+}
+
+//Test nothing is reported when the image and pim configuration match the registry and repository
+func TestOutdatedUpToDate(t *testing.T) {
+	mu := outdatedTestUtility()
+
+	oc := NewOutdatedCommand(mu, mu.Conf)
+
+	err := oc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = oc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"FileExists",
+		"GetListOfInstalledPimConfigs",
+		"ReadFile",
+		"TempDir",
+		"GetHCLBody",
+		"ParseBody",
+		"FindImage",
+		"FetchPimConfig",
+		"ReadFile",
+		"ReadFile",
+		"RemoteDigest",
+		"Emit",
+		"RemoveDir",
+	}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if !reflect.DeepEqual([]OutdatedEntry{}, mu.Events[0].Data) {
+		t.Fatalf("Outdated: Expected no outdated pims | Received: %v", mu.Events[0].Data)
+	}
+}
+
+//Test a newer image and a newer pim configuration are reported
+func TestOutdatedNewerImageAndConfig(t *testing.T) {
+	mu := outdatedTestUtility()
+
+	mu.RemoteDigests["packageless/python"] = "sha256:remote"
+	mu.FileContents["/tmp/packageless-outdated/python.hcl"] = "pim \"python\" {\n  base_dir = \"python\"\n}\n"
+
+	oc := NewOutdatedCommand(mu, mu.Conf)
+
+	err := oc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = oc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []OutdatedEntry{
+		{
+			Name:         "python",
+			Version:      "latest",
+			Image:        "packageless/python",
+			LocalDigest:  "sha256:local",
+			RemoteDigest: "sha256:remote",
+			NewerImage:   true,
+			NewerConfig:  true,
+		},
+	}
+
+	if !reflect.DeepEqual(expected, mu.Events[0].Data) {
+		t.Fatalf("Outdated: Expected outdated pims: %v | Received: %v", expected, mu.Events[0].Data)
+	}
+}
+
+//Test a registry that can not be reached is reported as a warning without failing
+func TestOutdatedErrorAtRemoteDigest(t *testing.T) {
+	mu := outdatedTestUtility()
+	mu.ErrorAt = "RemoteDigest"
+
+	oc := NewOutdatedCommand(mu, mu.Conf)
+
+	err := oc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = oc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mu.Events[0].Type != utils.EventWarning {
+		t.Fatalf("Outdated: Expected a warning | Received: %v", mu.Events[0])
+	}
+
+	if !reflect.DeepEqual([]OutdatedEntry{}, mu.Events[1].Data) {
+		t.Fatalf("Outdated: Expected no outdated pims | Received: %v", mu.Events[1].Data)
+	}
+}
+
+//Test the outdated subcommand reports no pims when nothing has been installed yet
+func TestOutdatedNoPimConfigDir(t *testing.T) {
+	mu := outdatedTestUtility()
+	mu.PimConfigShouldExist = false
+
+	oc := NewOutdatedCommand(mu, mu.Conf)
+
+	err := oc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = oc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FileExists", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if !reflect.DeepEqual([]OutdatedEntry{}, mu.Events[0].Data) {
+		t.Fatalf("Outdated: Expected no outdated pims | Received: %v", mu.Events[0].Data)
+	}
+}
+
+//Test the digest of the image is taken from the repository the image belongs to
+func TestRepoDigest(t *testing.T) {
+	repoDigests := []string{"mirror.local:5000/python@sha256:mirror", "packageless/python@sha256:hub"}
+
+	cases := map[string]string{
+		"packageless/python":                "sha256:hub",
+		"packageless/python:3.9":            "sha256:hub",
+		"mirror.local:5000/python:latest":   "sha256:mirror",
+		"mirror.local:5000/python@sha256:x": "sha256:mirror",
+		"packageless/node:latest":           "",
+	}
+
+	for image, expected := range cases {
+		if digest := repoDigest(image, repoDigests); digest != expected {
+			t.Fatalf("repoDigest: Expected the digest of %s to be: %s | Received: %s", image, expected, digest)
+		}
+	}
+}
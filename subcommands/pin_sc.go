@@ -0,0 +1,249 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Pin Sub-Command Object
+type PinCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//String for the name of the pim to pin
+	name string
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//Instantiation method for a new PinCommand
+func NewPinCommand(tools utils.Tools, config utils.Config) *PinCommand {
+	//Create a new PinCommand and set the FlagSet
+	pc := &PinCommand{
+		fs:     flag.NewFlagSet("pin", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	return pc
+}
+
+//Name - Gets the name of the Sub-Command
+func (pc *PinCommand) Name() string {
+	return pc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (pc *PinCommand) Description() string {
+	return "Pin pims so that upgrading or updating all pims skips them, or list the pinned pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (pc *PinCommand) Usage() string {
+	return "packageless pin [OPTIONAL: pim[:version]...]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (pc *PinCommand) Flags() *flag.FlagSet {
+	return pc.fs
+}
+
+//Batch - The pin subcommand is ran once for every pim passed as an argument
+func (pc *PinCommand) Batch() bool {
+	return true
+}
+
+//Init - Parses and Populates values of the Pin subcommand
+func (pc *PinCommand) Init(args []string) error {
+	err := pc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = pc.fs.Args()
+
+	pc.name = ""
+
+	if len(args) > 0 {
+		pc.name = args[0]
+	}
+
+	return nil
+}
+
+//Run - Runs the Pin subcommand
+func (pc *PinCommand) Run() error {
+	holds, err := loadHolds(pc.tools, pc.config)
+
+	if err != nil {
+		return err
+	}
+
+	//Without a pim the pinned pims are listed
+	if pc.name == "" {
+		if holds == nil {
+			holds = []utils.Hold{}
+		}
+
+		pc.tools.Emit(utils.Event{
+			Type:     utils.EventResult,
+			Message:  holdsText(holds),
+			Data:     holds,
+			Markdown: renderHolds(holds),
+		})
+
+		return nil
+	}
+
+	hold := parseHold(pc.name)
+
+	pimConfigDir := pc.config.BaseDir + pc.config.PimsConfigDir
+	pimPath := pimConfigDir + hold.Pim + ".hcl"
+
+	//Check if pim config exists
+	if !pc.tools.FileExists(pimPath) {
+		return errors.New("Could not find pim configuration for: " + hold.Pim + " has it been installed?")
+	}
+
+	if hold.Version != "" {
+		pimListBody, err := pc.tools.GetHCLBody(pimPath)
+
+		if err != nil {
+			return err
+		}
+
+		//Parse the pim list
+		parseOut, err := pc.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+		if err != nil {
+			return err
+		}
+
+		if _, _, found := findPimVersion(parseOut.(utils.PimHCLUtil), hold.Pim, hold.Version); !found {
+			return errors.New("Could not find pim " + hold.Pim + " with version '" + hold.Version + "' in the pim list")
+		}
+	}
+
+	var kept []utils.Hold
+
+	for _, existing := range holds {
+		//The pim is already pinned by this hold or by a hold of every version
+		if existing.Pim == hold.Pim && (existing.Version == "" || existing.Version == hold.Version) {
+			return Skip(existing.String() + " is already pinned")
+		}
+
+		//Pinning every version of the pim replaces the holds of single versions
+		if existing.Pim == hold.Pim && hold.Version == "" {
+			continue
+		}
+
+		kept = append(kept, existing)
+	}
+
+	err = saveHolds(pc.tools, pc.config, append(kept, hold))
+
+	if err != nil {
+		return err
+	}
+
+	pc.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: hold.String(), Message: "pinned"})
+
+	return nil
+}
+
+//parseHold - Creates a hold from a pim argument in the format pim[:version].
+//Unlike other subcommands the version does not default to latest, a pim without a version holds every version
+func parseHold(name string) utils.Hold {
+	if strings.Contains(name, ":") {
+		split := strings.Split(name, ":")
+		return utils.Hold{Pim: split[0], Version: split[1]}
+	}
+
+	return utils.Hold{Pim: name}
+}
+
+//holdsPath - Gets the path of the holds file
+func holdsPath(config utils.Config) string {
+	return config.BaseDir + utils.HoldsFile
+}
+
+//loadHolds - Loads the pinned pims, no pims are pinned if the holds file does not exist
+func loadHolds(tools utils.Tools, config utils.Config) ([]utils.Hold, error) {
+	path := holdsPath(config)
+
+	content, err := tools.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	holds, diags := utils.LoadHoldsHCL([]byte(content), path)
+
+	if diags.HasErrors() {
+		return nil, utils.WithErrorCode(utils.ErrorCodeInvalidConfig, errors.New("The holds file is invalid: "+utils.FormatDiagnostics(diags)))
+	}
+
+	return holds, nil
+}
+
+//saveHolds - Writes the pinned pims to the holds file, sorted so that the file does not change with the order pims are pinned in
+func saveHolds(tools utils.Tools, config utils.Config, holds []utils.Hold) error {
+	sort.SliceStable(holds, func(i, j int) bool {
+		return holds[i].String() < holds[j].String()
+	})
+
+	return tools.WriteFile(holdsPath(config), utils.HoldsHCL(holds))
+}
+
+//renderHolds - Creates the markdown table listing the pinned pims
+func renderHolds(holds []utils.Hold) string {
+	if len(holds) == 0 {
+		return "*No pims are pinned*"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("# Pinned pims\n")
+	sb.WriteString("| pim | version |\n")
+	sb.WriteString("| --- | --- |\n")
+
+	for _, hold := range holds {
+		version := hold.Version
+
+		if version == "" {
+			version = "all versions"
+		}
+
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", hold.Pim, version))
+	}
+
+	return sb.String()
+}
+
+//holdsText - Creates the plain text listing the pinned pims, one line per hold
+func holdsText(holds []utils.Hold) string {
+	if len(holds) == 0 {
+		return "No pims are pinned"
+	}
+
+	var lines []string
+
+	for _, hold := range holds {
+		lines = append(lines, hold.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
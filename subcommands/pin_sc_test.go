@@ -0,0 +1,216 @@
+package subcommands
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+func TestPinName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	pc := NewPinCommand(mu, mu.Conf)
+
+	if pc.Name() != "pin" {
+		t.Fatalf("The pin subcommand's name should be: 'pin' but was '%s'", pc.Name())
+	}
+}
+
+//Test pinning a version of a pim writes it to the holds file
+func TestPinFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	pc := NewPinCommand(mu, mu.Conf)
+
+	err := pc.Init([]string{"python:latest"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"ReadFile", "FileExists", "GetHCLBody", "ParseBody", "WriteFile", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	expected := utils.HoldsHCL([]utils.Hold{{Pim: "python", Version: "latest"}})
+
+	if mu.WrittenFiles[mu.Conf.BaseDir+utils.HoldsFile] != expected {
+		t.Fatalf("Pin: Expected holds file: %s | Received: %s", expected, mu.WrittenFiles[mu.Conf.BaseDir+utils.HoldsFile])
+	}
+}
+
+//Test pinning every version of a pim replaces the pins of its single versions and that pinning it again is skipped
+func TestPinEveryVersion(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.FileContents = map[string]string{
+		mu.Conf.BaseDir + utils.HoldsFile: utils.HoldsHCL([]utils.Hold{{Pim: "python", Version: "latest"}, {Pim: "node", Version: "16"}}),
+	}
+
+	pc := NewPinCommand(mu, mu.Conf)
+
+	err := pc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := utils.HoldsHCL([]utils.Hold{{Pim: "node", Version: "16"}, {Pim: "python"}})
+
+	if mu.WrittenFiles[mu.Conf.BaseDir+utils.HoldsFile] != expected {
+		t.Fatalf("Pin: Expected holds file: %s | Received: %s", expected, mu.WrittenFiles[mu.Conf.BaseDir+utils.HoldsFile])
+	}
+
+	err = pc.Run()
+
+	var skip *SkipError
+
+	if !errors.As(err, &skip) {
+		t.Fatalf("Pin: Expected pinning an already pinned pim to be skipped | Received: %v", err)
+	}
+}
+
+//Test pinning a version that is not in the pim configuration
+func TestPinNonExistVersion(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	pc := NewPinCommand(mu, mu.Conf)
+
+	err := pc.Init([]string{"python:idontexist"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	expected := "Could not find pim python with version 'idontexist' in the pim list"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Pin: Expected error: %s | Received: %v", expected, err)
+	}
+
+	if len(mu.WrittenFiles) > 0 {
+		t.Fatal("Pin: Expected the holds file not to be written")
+	}
+}
+
+//Test the pinned pims are listed when no pim is passed
+func TestPinList(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	holds := []utils.Hold{{Pim: "node", Version: "16"}, {Pim: "python"}}
+
+	mu.FileContents = map[string]string{mu.Conf.BaseDir + utils.HoldsFile: utils.HoldsHCL(holds)}
+
+	pc := NewPinCommand(mu, mu.Conf)
+
+	err := pc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(holds, mu.Events[0].Data) {
+		t.Fatalf("Pin: Expected the pinned pims as the result: %v | Received: %v", holds, mu.Events[0].Data)
+	}
+}
+
+//Test unpinning a pim removes the pins of all its versions and that unpinning a pim that is not pinned is skipped
+func TestUnpinFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.FileContents = map[string]string{
+		mu.Conf.BaseDir + utils.HoldsFile: utils.HoldsHCL([]utils.Hold{{Pim: "node", Version: "16"}, {Pim: "python", Version: "3.9"}, {Pim: "python", Version: "latest"}}),
+	}
+
+	uc := NewUnpinCommand(mu, mu.Conf)
+
+	err := uc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = uc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := utils.HoldsHCL([]utils.Hold{{Pim: "node", Version: "16"}})
+
+	if mu.WrittenFiles[mu.Conf.BaseDir+utils.HoldsFile] != expected {
+		t.Fatalf("Unpin: Expected holds file: %s | Received: %s", expected, mu.WrittenFiles[mu.Conf.BaseDir+utils.HoldsFile])
+	}
+
+	err = uc.Run()
+
+	var skip *SkipError
+
+	if !errors.As(err, &skip) {
+		t.Fatalf("Unpin: Expected unpinning a pim that is not pinned to be skipped | Received: %v", err)
+	}
+}
+
+//Test a single version can not be unpinned while every version of the pim is pinned
+func TestUnpinVersionOfPinnedPim(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.FileContents = map[string]string{mu.Conf.BaseDir + utils.HoldsFile: utils.HoldsHCL([]utils.Hold{{Pim: "python"}})}
+
+	uc := NewUnpinCommand(mu, mu.Conf)
+
+	err := uc.Init([]string{"python:latest"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = uc.Run()
+
+	expected := "python:latest is pinned by the pin of every version of python, unpin python instead"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Unpin: Expected error: %s | Received: %v", expected, err)
+	}
+}
+
+//Test an invalid holds file is reported as an invalid configuration
+func TestPinInvalidHoldsFile(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.FileContents = map[string]string{mu.Conf.BaseDir + utils.HoldsFile: "hold {"}
+
+	pc := NewPinCommand(mu, mu.Conf)
+
+	err := pc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if utils.ErrorCode(err) != utils.ErrorCodeInvalidConfig {
+		t.Fatalf("Pin: Expected an invalid configuration error | Received: %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Plugins Sub-Command Object
+type PluginsCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	tools utils.Tools
+
+	config utils.Config
+
+	//Path to the configuration file, passed on to the plugins
+	configPath string
+
+	//Global options, passed on to the plugins
+	opts GlobalOptions
+}
+
+//Instantiation method for a new PluginsCommand
+func NewPluginsCommand(tools utils.Tools, config utils.Config, configPath string, opts GlobalOptions) *PluginsCommand {
+	//Create a new PluginsCommand and set the FlagSet
+	pc := &PluginsCommand{
+		fs:         flag.NewFlagSet("plugins", flag.ContinueOnError),
+		tools:      tools,
+		config:     config,
+		configPath: configPath,
+		opts:       opts,
+	}
+
+	return pc
+}
+
+//Name - Gets the name of the Sub-Command
+func (pc *PluginsCommand) Name() string {
+	return pc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (pc *PluginsCommand) Description() string {
+	return "List the plugins that provide additional subcommands"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (pc *PluginsCommand) Usage() string {
+	return "packageless plugins"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (pc *PluginsCommand) Flags() *flag.FlagSet {
+	return pc.fs
+}
+
+//Init - Parses and Populates values of the Plugins subcommand
+func (pc *PluginsCommand) Init(args []string) error {
+	return pc.fs.Parse(args)
+}
+
+//Run - Runs the Plugins subcommand
+func (pc *PluginsCommand) Run() error {
+	plugins, err := pc.tools.FindPlugins(pc.dirs())
+
+	if err != nil {
+		return err
+	}
+
+	pc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  pluginsText(plugins),
+		Data:     plugins,
+		Markdown: pc.renderPlugins(plugins),
+	})
+
+	return nil
+}
+
+//renderPlugins - Creates the markdown table listing the plugins
+func (pc *PluginsCommand) renderPlugins(plugins []utils.Plugin) string {
+	if len(plugins) == 0 {
+		return fmt.Sprintf("*No plugins found. Plugins are executables named %s<name> on the PATH or in %s*", utils.PluginPrefix, pc.pluginDir())
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("# Plugins\n")
+	sb.WriteString("| name | path |\n")
+	sb.WriteString("| --- | --- |\n")
+
+	for _, plugin := range plugins {
+		//Paths are shown as code so that they are rendered as is
+		sb.WriteString(fmt.Sprintf("| %s | `%s` |\n", plugin.Name, plugin.Path))
+	}
+
+	return sb.String()
+}
+
+//pluginsText - Creates the plain text listing the plugins, one line per plugin
+func pluginsText(plugins []utils.Plugin) string {
+	var lines []string
+
+	for _, plugin := range plugins {
+		lines = append(lines, plugin.Name+": "+plugin.Path)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+//pluginDir - Gets the directory that packageless looks for plugins in besides the PATH
+func (pc *PluginsCommand) pluginDir() string {
+	return pc.config.BaseDir + "plugins/"
+}
+
+//dirs - Gets the directories to look for plugins in, the plugins directory of packageless comes before the PATH
+func (pc *PluginsCommand) dirs() []string {
+	return append([]string{pc.pluginDir()}, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+//FindPlugin - Finds the plugin that provides the subcommand with the name
+func (pc *PluginsCommand) FindPlugin(name string) (Runner, bool, error) {
+	plugins, err := pc.tools.FindPlugins(pc.dirs())
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, plugin := range plugins {
+		if plugin.Name == name {
+			return pc.newPluginCommand(plugin), true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+//FindPlugins - Finds every plugin
+func (pc *PluginsCommand) FindPlugins() ([]Runner, error) {
+	plugins, err := pc.tools.FindPlugins(pc.dirs())
+
+	if err != nil {
+		return nil, err
+	}
+
+	var runners []Runner
+
+	for _, plugin := range plugins {
+		runners = append(runners, pc.newPluginCommand(plugin))
+	}
+
+	return runners, nil
+}
+
+//newPluginCommand - Creates the subcommand that runs the plugin
+func (pc *PluginsCommand) newPluginCommand(plugin utils.Plugin) *PluginCommand {
+	return &PluginCommand{
+		fs:     flag.NewFlagSet(plugin.Name, flag.ContinueOnError),
+		plugin: plugin,
+		env:    pc.env(),
+		tools:  pc.tools,
+	}
+}
+
+//env - Gets the environment variables that pass the resolved configuration and the global options to plugins
+func (pc *PluginsCommand) env() []string {
+	return []string{
+		"PACKAGELESS_CONFIG=" + pc.configPath,
+		"PACKAGELESS_BASE_DIR=" + pc.config.BaseDir,
+		"PACKAGELESS_PIMS_DIR=" + pc.config.BaseDir + pc.config.PimsDir,
+		"PACKAGELESS_PIMS_CONFIG_DIR=" + pc.config.BaseDir + pc.config.PimsConfigDir,
+		"PACKAGELESS_REPOSITORY_HOST=" + pc.config.RepositoryHost,
+		"PACKAGELESS_START_PORT=" + strconv.Itoa(pc.config.StartPort),
+		"PACKAGELESS_PORT_INCREMENT=" + strconv.Itoa(pc.config.PortInc),
+		"PACKAGELESS_ALIAS=" + strconv.FormatBool(pc.config.Alias),
+		"PACKAGELESS_ROLLBACK_GENERATIONS=" + strconv.Itoa(pc.config.RollbackGens),
+		"PACKAGELESS_OUTPUT=" + pc.opts.Output,
+		"PACKAGELESS_QUIET=" + strconv.FormatBool(pc.opts.Quiet),
+		"PACKAGELESS_VERBOSE=" + strconv.FormatBool(pc.opts.Verbose),
+		"PACKAGELESS_NO_COLOR=" + strconv.FormatBool(pc.opts.NoColor),
+		"PACKAGELESS_VERSION=" + version,
+	}
+}
+
+//Plugin Sub-Command Object, runs an external plugin executable
+type PluginCommand struct {
+	//FlagSet so that the plugin has a name, the flags are parsed by the plugin itself
+	fs *flag.FlagSet
+
+	plugin utils.Plugin
+
+	//Environment variables passed to the plugin
+	env []string
+
+	//Arguments passed to the plugin
+	args []string
+
+	tools utils.Tools
+}
+
+//Name - Gets the name of the Sub-Command
+func (pc *PluginCommand) Name() string {
+	return pc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (pc *PluginCommand) Description() string {
+	return "Plugin at " + pc.plugin.Path
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (pc *PluginCommand) Usage() string {
+	return "packageless " + pc.plugin.Name + " [ARGUMENTS]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (pc *PluginCommand) Flags() *flag.FlagSet {
+	return pc.fs
+}
+
+//Init - Keeps the arguments as they are, including help flags, to pass them to the plugin
+func (pc *PluginCommand) Init(args []string) error {
+	pc.args = args
+	return nil
+}
+
+//Run - Runs the plugin, exiting with its exit code if it fails
+func (pc *PluginCommand) Run() error {
+	exitCode, err := pc.tools.RunPlugin(pc.plugin.Path, pc.args, pc.env)
+
+	if err != nil {
+		return fmt.Errorf("Could not run the plugin %s: %s", pc.plugin.Path, err)
+	}
+
+	if exitCode != 0 {
+		return &ExitError{Code: exitCode}
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package subcommands
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+func TestPluginsName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	pc := NewPluginsCommand(mu, mu.Conf, "config.hcl", GlobalOptions{})
+
+	if pc.Name() != "plugins" {
+		t.Fatalf("The plugins subcommand's name should be: 'plugins' but was '%s'", pc.Name())
+	}
+}
+
+//Test the plugins are listed and looked for in the plugins directory before the PATH
+func TestPluginsRun(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.Plugins = []utils.Plugin{{Name: "team-sync", Path: "/usr/local/bin/packageless-team-sync"}}
+
+	pc := NewPluginsCommand(mu, mu.Conf, "config.hcl", GlobalOptions{})
+
+	err := pc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"FindPlugins", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	dirs := append([]string{mu.Conf.BaseDir + "plugins/"}, filepath.SplitList(os.Getenv("PATH"))...)
+
+	if !reflect.DeepEqual(dirs, mu.PluginDirs) {
+		t.Fatalf("Plugins: Expected the plugins to be looked for in: %v | Received: %v", dirs, mu.PluginDirs)
+	}
+
+	if !reflect.DeepEqual(mu.Plugins, mu.Events[0].Data) {
+		t.Fatalf("Plugins: Expected the plugins as the result: %v | Received: %v", mu.Plugins, mu.Events[0].Data)
+	}
+}
+
+//Test SubCommand runs a plugin for a subcommand that is not registered, passing the arguments and configuration
+func TestSubCommandRunsPlugin(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.Plugins = []utils.Plugin{{Name: "team-sync", Path: "/plugins/packageless-team-sync"}}
+
+	opts := GlobalOptions{Output: utils.OutputJSON}
+
+	scmds := []Runner{NewPluginsCommand(mu, mu.Conf, "config.hcl", opts)}
+
+	err := SubCommand([]string{"team-sync", "--help", "python"}, scmds, mu)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mu.RunPluginPath != "/plugins/packageless-team-sync" {
+		t.Fatalf("SubCommand: Expected the plugin to be ran | Received: %s", mu.RunPluginPath)
+	}
+
+	//Every argument, including help flags, is passed to the plugin
+	args := []string{"--help", "python"}
+
+	if !reflect.DeepEqual(args, mu.RunPluginArgs) {
+		t.Fatalf("SubCommand: Expected plugin arguments: %v | Received: %v", args, mu.RunPluginArgs)
+	}
+
+	env := strings.Join(mu.RunPluginEnv, "\n")
+
+	for _, expected := range []string{
+		"PACKAGELESS_CONFIG=config.hcl",
+		"PACKAGELESS_BASE_DIR=" + mu.Conf.BaseDir,
+		"PACKAGELESS_PIMS_DIR=" + mu.Conf.BaseDir + mu.Conf.PimsDir,
+		"PACKAGELESS_PIMS_CONFIG_DIR=" + mu.Conf.BaseDir + mu.Conf.PimsConfigDir,
+		"PACKAGELESS_OUTPUT=json",
+	} {
+		if !strings.Contains(env, expected) {
+			t.Fatalf("SubCommand: Expected the plugin environment to contain %s | Received: %v", expected, mu.RunPluginEnv)
+		}
+	}
+}
+
+//Test the exit code of a plugin is returned
+func TestSubCommandPluginExitCode(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.Plugins = []utils.Plugin{{Name: "team-sync", Path: "/plugins/packageless-team-sync"}}
+	mu.PluginExitCode = 3
+
+	scmds := []Runner{NewPluginsCommand(mu, mu.Conf, "config.hcl", GlobalOptions{})}
+
+	err := SubCommand([]string{"team-sync"}, scmds, mu)
+
+	var exitErr *ExitError
+
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("SubCommand: Expected the exit code 3 of the plugin | Received: %v", err)
+	}
+}
+
+//Test registered subcommands take precedence over plugins and unknown subcommands are still reported
+func TestSubCommandPluginPrecedence(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.Plugins = []utils.Plugin{{Name: "version", Path: "/plugins/packageless-version"}}
+
+	scmds := []Runner{NewVersionCommand(mu), NewPluginsCommand(mu, mu.Conf, "config.hcl", GlobalOptions{})}
+
+	err := SubCommand([]string{"version"}, scmds, mu)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mu.RunPluginPath != "" {
+		t.Fatalf("SubCommand: Expected the registered subcommand to be ran instead of the plugin %s", mu.RunPluginPath)
+	}
+
+	err = SubCommand([]string{"team-sync"}, scmds, mu)
+
+	if utils.ErrorCode(err) != utils.ErrorCodeUnknownCommand {
+		t.Fatalf("SubCommand: Expected an unknown subcommand error | Received: %v", err)
+	}
+}
+
+//Test the usage lists the plugins
+func TestUsageListsPlugins(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.Plugins = []utils.Plugin{{Name: "team-sync", Path: "/plugins/packageless-team-sync"}}
+
+	usage := renderUsage([]Runner{NewPluginsCommand(mu, mu.Conf, "config.hcl", GlobalOptions{})})
+
+	if !strings.Contains(usage, "**Plugins**:\n- **team-sync** - Plugin at /plugins/packageless-team-sync") {
+		t.Fatalf("Usage: Expected the plugins to be listed | Received: %s", usage)
+	}
+}
@@ -0,0 +1,418 @@
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Prune Sub-Command Object
+type PruneCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Only list what would be removed
+	dryRun bool
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//Kinds of items that the prune subcommand removes
+const (
+	PruneContainer = "container"
+	PruneImage     = "image"
+	PruneDirectory = "directory"
+	PruneConfig    = "config"
+)
+
+//PruneItem - Something packageless owns that is not referenced by any installed pim
+type PruneItem struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	//Identifier used to remove the item, the ID for containers and images and the path otherwise
+	ID string `json:"id"`
+
+	Size int64 `json:"size"`
+}
+
+//PruneResult - The outcome of the prune subcommand
+type PruneResult struct {
+	DryRun    bool        `json:"dry_run"`
+	Items     []PruneItem `json:"items"`
+	Reclaimed int64       `json:"reclaimed"`
+}
+
+//Instantiation method for a new PruneCommand
+func NewPruneCommand(tools utils.Tools, config utils.Config) *PruneCommand {
+	//Create a new PruneCommand and set the FlagSet
+	pc := &PruneCommand{
+		fs:     flag.NewFlagSet("prune", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	pc.fs.BoolVar(&pc.dryRun, "dry-run", false, "only list what would be removed and how much space would be reclaimed")
+
+	return pc
+}
+
+//Name - Gets the name of the Sub-Command
+func (pc *PruneCommand) Name() string {
+	return pc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (pc *PruneCommand) Description() string {
+	return "Remove the images, directories, configurations and containers that no installed pim uses"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (pc *PruneCommand) Usage() string {
+	return "packageless prune [OPTIONS]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (pc *PruneCommand) Flags() *flag.FlagSet {
+	return pc.fs
+}
+
+//Init - Parses and Populates values of the Prune subcommand
+func (pc *PruneCommand) Init(args []string) error {
+	return pc.fs.Parse(args)
+}
+
+//Run - Runs the Prune subcommand
+func (pc *PruneCommand) Run() error {
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	items, err := pc.unreferenced(cli)
+
+	if err != nil {
+		return err
+	}
+
+	result := PruneResult{DryRun: pc.dryRun, Items: items}
+
+	for _, item := range items {
+		if !pc.dryRun {
+			stepStarted(pc.tools, "", "remove_"+item.Kind, fmt.Sprintf("Removing %s %s", item.Kind, item.Name))
+
+			err = pc.remove(item, cli)
+
+			if err != nil {
+				return fmt.Errorf("Could not remove %s %s: %s", item.Kind, item.Name, err)
+			}
+
+			stepFinished(pc.tools, "", "remove_"+item.Kind)
+		}
+
+		result.Reclaimed += item.Size
+	}
+
+	pc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  pruneText(result),
+		Data:     result,
+		Markdown: renderPrune(result),
+	})
+
+	return nil
+}
+
+//unreferenced - Gets everything that packageless owns but that no installed pim references.
+//Containers come first as they use images, and configurations come last so that prune can be ran again if it fails.
+func (pc *PruneCommand) unreferenced(cli utils.Client) ([]PruneItem, error) {
+	pimConfigDir := pc.config.BaseDir + pc.config.PimsConfigDir
+	pimDir := pc.config.BaseDir + pc.config.PimsDir
+
+	items := []PruneItem{}
+
+	//Containers created to copy files out of an image are only needed while installing
+	containers, err := pc.tools.ListTemporaryContainers(cli)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ctr := range containers {
+		items = append(items, PruneItem{Kind: PruneContainer, Name: containerName(ctr), ID: ctr.ID, Size: ctr.SizeRw})
+	}
+
+	images, err := pc.tools.ListImages(cli)
+
+	if err != nil {
+		return nil, err
+	}
+
+	downloaded := make(map[string]bool)
+
+	for _, img := range images {
+		for _, tag := range img.RepoTags {
+			downloaded[tag] = true
+		}
+	}
+
+	var pimNames []string
+
+	//Nothing has been installed yet if the directory does not exist
+	if pc.tools.FileExists(pimConfigDir) {
+		pimNames, err = pc.tools.GetListOfInstalledPimConfigs(pimConfigDir)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//Images of the versions in the pim configurations, they are downloaded only if the version is installed
+	usedImages := make(map[string]bool)
+
+	//Paths used by installed pims, everything else in the pims directory is removed
+	usedPaths := make(map[string]bool)
+
+	//Base directories of installed pims, they are kept but what they contain is only kept if it is used
+	baseDirs := make(map[string]bool)
+
+	//Rollbacks of installed versions, by the prefix of their tags and by their directory
+	usedRollbacks := make(map[string]bool)
+	usedRollbackPaths := make(map[string]bool)
+
+	var configItems []PruneItem
+
+	for _, pimName := range pimNames {
+		pimListBody, err := pc.tools.GetHCLBody(pimConfigDir + pimName + ".hcl")
+
+		if err != nil {
+			return nil, err
+		}
+
+		parseOut, err := pc.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		installed := false
+
+		for _, pim := range parseOut.(utils.PimHCLUtil).Pims {
+			for _, ver := range pim.Versions {
+				usedImages[ver.Image] = true
+
+				if !downloaded[ver.Image] {
+					continue
+				}
+
+				installed = true
+				baseDirs[filepath.Clean(pimDir+pim.BaseDir)] = true
+
+				usedRollbacks[utils.RollbackTag(pim.Name, ver.Version, "")] = true
+				usedRollbackPaths[filepath.Clean(rollbackBaseDir(pc.config)+pim.Name+"/"+ver.Version)] = true
+
+				for _, vol := range ver.Volumes {
+					if vol.Path != "" {
+						usedPaths[filepath.Clean(pimDir+vol.Path)] = true
+					}
+				}
+
+				for _, copy := range ver.Copies {
+					usedPaths[filepath.Clean(pimDir+copy.Dest)] = true
+				}
+			}
+		}
+
+		//Uninstalling a pim keeps its configuration
+		if !installed {
+			configPath := pimConfigDir + pimName + ".hcl"
+
+			size, err := pc.tools.DirSize(configPath)
+
+			if err != nil {
+				return nil, err
+			}
+
+			configItems = append(configItems, PruneItem{Kind: PruneConfig, Name: pimName, ID: configPath, Size: size})
+		}
+	}
+
+	for _, img := range images {
+		if ownedImage(img.RepoTags, usedImages, usedRollbacks) {
+			items = append(items, PruneItem{Kind: PruneImage, Name: img.RepoTags[0], ID: img.ID, Size: img.Size})
+		}
+	}
+
+	dirItems, err := pc.unreferencedPaths(filepath.Clean(pimDir), usedPaths, baseDirs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items = append(items, dirItems...)
+
+	//The rollbacks of versions that are not installed, e.g. because they were uninstalled before rollbacks were removed with them
+	rollbackRoot := filepath.Clean(rollbackBaseDir(pc.config))
+
+	if pc.tools.FileExists(rollbackRoot) {
+		rollbackItems, err := pc.unreferencedPaths(rollbackRoot, usedRollbackPaths, map[string]bool{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, rollbackItems...)
+	}
+
+	return append(items, configItems...), nil
+}
+
+//ownedImage - Checks if the image only has tags of pim images or rollbacks and none of them is used by an installed pim.
+//Images that were also tagged by the user are never removed.
+func ownedImage(tags []string, usedImages map[string]bool, usedRollbacks map[string]bool) bool {
+	if len(tags) == 0 {
+		return false
+	}
+
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, utils.RollbackPrefix) {
+			//The generation follows the last dash of the tag
+			if usedRollbacks[tag[:strings.LastIndex(tag, "-")+1]] {
+				return false
+			}
+
+			continue
+		}
+
+		if !strings.HasPrefix(tag, utils.ImagePrefix) || usedImages[tag] {
+			return false
+		}
+	}
+
+	return true
+}
+
+//unreferencedPaths - Gets the files and directories in the directory that are neither used, a base directory nor contain a used path
+func (pc *PruneCommand) unreferencedPaths(dir string, usedPaths map[string]bool, baseDirs map[string]bool) ([]PruneItem, error) {
+	names, err := pc.tools.ListDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var items []PruneItem
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+
+		if usedPaths[path] {
+			continue
+		}
+
+		//Look inside base directories and directories that contain a used path
+		if baseDirs[path] || containsUsedPath(path, usedPaths) {
+			children, err := pc.unreferencedPaths(path, usedPaths, baseDirs)
+
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, children...)
+			continue
+		}
+
+		size, err := pc.tools.DirSize(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, PruneItem{Kind: PruneDirectory, Name: path, ID: path, Size: size})
+	}
+
+	return items, nil
+}
+
+//containsUsedPath - Checks if any of the used paths is inside the directory
+func containsUsedPath(dir string, usedPaths map[string]bool) bool {
+	for path := range usedPaths {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//remove - Removes an item
+func (pc *PruneCommand) remove(item PruneItem, cli utils.Client) error {
+	switch item.Kind {
+	case PruneContainer:
+		return pc.tools.RemoveContainer(item.ID, cli)
+	case PruneImage:
+		return pc.tools.RemoveImage(item.ID, cli)
+	case PruneConfig:
+		return pc.tools.RemoveFile(item.ID)
+	}
+
+	return pc.tools.RemoveDir(item.ID)
+}
+
+//renderPrune - Creates the markdown table of what was, or would be, removed
+func renderPrune(result PruneResult) string {
+	if len(result.Items) == 0 {
+		return "*Nothing to prune*"
+	}
+
+	var sb strings.Builder
+
+	if result.DryRun {
+		sb.WriteString("# Would remove\n")
+	} else {
+		sb.WriteString("# Removed\n")
+	}
+
+	sb.WriteString("| kind | name | size |\n")
+	sb.WriteString("| --- | --- | --- |\n")
+
+	for _, item := range result.Items {
+		//Names are shown as code so that paths are rendered as is
+		sb.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", item.Kind, item.Name, formatBytes(item.Size)))
+	}
+
+	if result.DryRun {
+		sb.WriteString(fmt.Sprintf("\n**Would reclaim**: *%s*\n", formatBytes(result.Reclaimed)))
+	} else {
+		sb.WriteString(fmt.Sprintf("\n**Reclaimed**: *%s*\n", formatBytes(result.Reclaimed)))
+	}
+
+	return sb.String()
+}
+
+//pruneText - Creates the plain text of what was, or would be, removed, one line per item
+func pruneText(result PruneResult) string {
+	if len(result.Items) == 0 {
+		return "Nothing to prune"
+	}
+
+	var lines []string
+
+	for _, item := range result.Items {
+		lines = append(lines, fmt.Sprintf("%s %s (%s)", item.Kind, item.Name, formatBytes(item.Size)))
+	}
+
+	if result.DryRun {
+		lines = append(lines, "Would reclaim "+formatBytes(result.Reclaimed))
+	} else {
+		lines = append(lines, "Reclaimed "+formatBytes(result.Reclaimed))
+	}
+
+	return strings.Join(lines, "\n")
+}
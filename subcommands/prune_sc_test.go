@@ -0,0 +1,217 @@
+package subcommands
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Create a mock utility with python installed and debris left behind by other pims
+func pruneTestUtility() (*utils.MockUtility, utils.Config) {
+	mu := utils.NewMockUtility()
+
+	config := mu.Conf
+	config.BaseDir = "/home/user/.packageless/"
+
+	pimDir := filepath.Clean(config.BaseDir + config.PimsDir)
+
+	mu.Pim.Pims[0].Versions[0].Image = "packageless/python:latest"
+	mu.Pim.Pims[0].BaseDir = "python"
+	mu.Pim.Pims[0].Versions[0].Volumes[0].Path = "python/data"
+	mu.Pim.Pims[0].Versions[0].Copies[0].Dest = "python/lib"
+
+	mu.InstalledPims = []string{"python"}
+
+	mu.Images = []types.ImageSummary{
+		{ID: "sha256:python", RepoTags: []string{"packageless/python:latest"}, Size: 100},
+		{ID: "sha256:old", RepoTags: []string{"packageless/python:2.7"}, Size: 50},
+		{ID: "sha256:mine", RepoTags: []string{"packageless/node:latest", "mine/node:latest"}, Size: 70},
+		{ID: "sha256:other", RepoTags: []string{"ubuntu:latest"}, Size: 30},
+	}
+
+	mu.TempContainers = []types.Container{{ID: "leftover", Names: []string{"/quirky_name"}}}
+
+	mu.DirEntries = map[string][]string{
+		pimDir:                          {"python", "node"},
+		filepath.Join(pimDir, "python"): {"data", "lib", "cache"},
+	}
+
+	mu.DirSizeRet = 10
+
+	return mu, config
+}
+
+func TestPruneName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	pc := NewPruneCommand(mu, mu.Conf)
+
+	if pc.Name() != "prune" {
+		t.Fatalf("The prune subcommand's name should be: 'prune' but was '%s'", pc.Name())
+	}
+}
+
+//Test the dry run lists everything that is not referenced by an installed pim without removing it
+func TestPruneDryRun(t *testing.T) {
+	mu, config := pruneTestUtility()
+
+	pimDir := filepath.Clean(config.BaseDir + config.PimsDir)
+
+	pc := NewPruneCommand(mu, config)
+
+	err := pc.Init([]string{"--dry-run"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := PruneResult{
+		DryRun: true,
+		Items: []PruneItem{
+			{Kind: PruneContainer, Name: "quirky_name", ID: "leftover"},
+			{Kind: PruneImage, Name: "packageless/python:2.7", ID: "sha256:old", Size: 50},
+			{Kind: PruneDirectory, Name: filepath.Join(pimDir, "python", "cache"), ID: filepath.Join(pimDir, "python", "cache"), Size: 10},
+			{Kind: PruneDirectory, Name: filepath.Join(pimDir, "node"), ID: filepath.Join(pimDir, "node"), Size: 10},
+		},
+		Reclaimed: 70,
+	}
+
+	result := mu.Events[len(mu.Events)-1].Data
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Prune: Expected result: %+v | Received: %+v", expected, result)
+	}
+
+	if len(mu.RemovedDirs) > 0 || len(mu.RemovedImgs) > 0 || len(mu.RemovedFiles) > 0 || len(mu.RemovedContainers) > 0 {
+		t.Fatal("Prune: Expected nothing to be removed in a dry run")
+	}
+}
+
+//Test everything that is not referenced is removed, including the configuration of pims that are not installed
+func TestPruneFlow(t *testing.T) {
+	mu, config := pruneTestUtility()
+
+	//Python is not installed anymore
+	mu.Images = mu.Images[1:]
+
+	pimDir := filepath.Clean(config.BaseDir + config.PimsDir)
+
+	pc := NewPruneCommand(mu, config)
+
+	err := pc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{"leftover"}, mu.RemovedContainers) {
+		t.Fatalf("Prune: Expected removed containers: [leftover] | Received: %v", mu.RemovedContainers)
+	}
+
+	if !reflect.DeepEqual([]string{"sha256:old"}, mu.RemovedImgs) {
+		t.Fatalf("Prune: Expected removed images: [sha256:old] | Received: %v", mu.RemovedImgs)
+	}
+
+	dirs := []string{filepath.Join(pimDir, "python"), filepath.Join(pimDir, "node")}
+
+	if !reflect.DeepEqual(dirs, mu.RemovedDirs) {
+		t.Fatalf("Prune: Expected removed directories: %v | Received: %v", dirs, mu.RemovedDirs)
+	}
+
+	files := []string{config.BaseDir + config.PimsConfigDir + "python.hcl"}
+
+	if !reflect.DeepEqual(files, mu.RemovedFiles) {
+		t.Fatalf("Prune: Expected removed files: %v | Received: %v", files, mu.RemovedFiles)
+	}
+}
+
+//Test the rollbacks of versions that are not installed are removed and the ones of installed versions are kept
+func TestPruneRollbacks(t *testing.T) {
+	mu, config := pruneTestUtility()
+
+	rollbackRoot := filepath.Clean(rollbackBaseDir(config))
+
+	mu.Images = append(mu.Images,
+		types.ImageSummary{ID: "sha256:kept", RepoTags: []string{utils.RollbackTag("python", "latest", "20260101T000000Z")}, Size: 20},
+		types.ImageSummary{ID: "sha256:uninstalled", RepoTags: []string{utils.RollbackTag("python", "latest-beta", "20260101T000000Z")}, Size: 20},
+		types.ImageSummary{ID: "sha256:node", RepoTags: []string{utils.RollbackTag("node", "latest", "20260101T000000Z")}, Size: 20},
+	)
+
+	mu.DirEntries[rollbackRoot] = []string{"python", "node"}
+	mu.DirEntries[filepath.Join(rollbackRoot, "python")] = []string{"latest", "latest-beta"}
+
+	pc := NewPruneCommand(mu, config)
+
+	err := pc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	images := []string{"sha256:old", "sha256:uninstalled", "sha256:node"}
+
+	if !reflect.DeepEqual(images, mu.RemovedImgs) {
+		t.Fatalf("Prune: Expected removed images: %v | Received: %v", images, mu.RemovedImgs)
+	}
+
+	pimDir := filepath.Clean(config.BaseDir + config.PimsDir)
+
+	dirs := []string{
+		filepath.Join(pimDir, "python", "cache"),
+		filepath.Join(pimDir, "node"),
+		filepath.Join(rollbackRoot, "python", "latest-beta"),
+		filepath.Join(rollbackRoot, "node"),
+	}
+
+	if !reflect.DeepEqual(dirs, mu.RemovedDirs) {
+		t.Fatalf("Prune: Expected removed directories: %v | Received: %v", dirs, mu.RemovedDirs)
+	}
+}
+
+//Test the prune subcommand getting an error when removing something
+func TestPruneErrorAtRemoveImage(t *testing.T) {
+	mu, config := pruneTestUtility()
+	mu.ErrorAt = "RemoveImage"
+
+	pc := NewPruneCommand(mu, config)
+
+	err := pc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	expected := "Could not remove image packageless/python:2.7: " + mu.ErrorMsg
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Prune: Expected error: %s | Received: %v", expected, err)
+	}
+
+	//The configurations are removed last so nothing else should have been removed
+	if len(mu.RemovedDirs) > 0 || len(mu.RemovedFiles) > 0 {
+		t.Fatal("Prune: Expected nothing to be removed after the error")
+	}
+}
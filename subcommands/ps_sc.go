@@ -0,0 +1,208 @@
+package subcommands
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Ps Sub-Command Object
+type PsCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	tools utils.Tools
+
+	//Returns the current time, so that the uptime can be tested
+	now func() time.Time
+}
+
+//PsEntry - Details of a single running pim container
+type PsEntry struct {
+	Container string   `json:"container"`
+	ID        string   `json:"id"`
+	Pim       string   `json:"pim"`
+	Version   string   `json:"version"`
+	Started   string   `json:"started"`
+	Uptime    string   `json:"uptime"`
+	Ports     []string `json:"ports"`
+	Workdir   string   `json:"workdir,omitempty"`
+}
+
+//Instantiation method for a new PsCommand
+func NewPsCommand(tools utils.Tools) *PsCommand {
+	//Create a new PsCommand and set the FlagSet
+	pc := &PsCommand{
+		fs:    flag.NewFlagSet("ps", flag.ContinueOnError),
+		tools: tools,
+		now:   time.Now,
+	}
+
+	return pc
+}
+
+//Name - Gets the name of the Sub-Command
+func (pc *PsCommand) Name() string {
+	return pc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (pc *PsCommand) Description() string {
+	return "List the running pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (pc *PsCommand) Usage() string {
+	return "packageless ps"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (pc *PsCommand) Flags() *flag.FlagSet {
+	return pc.fs
+}
+
+//Init - Parses and Populates values of the Ps subcommand
+func (pc *PsCommand) Init(args []string) error {
+	return pc.fs.Parse(args)
+}
+
+//Run - Runs the Ps subcommand
+func (pc *PsCommand) Run() error {
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	containers, err := pc.tools.ListPimContainers(cli)
+
+	if err != nil {
+		return err
+	}
+
+	entries := []PsEntry{}
+
+	for _, ctr := range containers {
+		entries = append(entries, pc.entry(ctr))
+	}
+
+	//Show the containers that have been running the longest first
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Started < entries[j].Started
+	})
+
+	pc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  psText(entries),
+		Data:     entries,
+		Markdown: renderPs(entries),
+	})
+
+	return nil
+}
+
+//entry - Creates the details of a running pim container from the container and its labels
+func (pc *PsCommand) entry(ctr types.Container) PsEntry {
+	started := time.Unix(ctr.Created, 0).UTC()
+
+	//The label is set when the container is started while Created is set before the image is ready
+	if label, err := time.Parse(time.RFC3339, ctr.Labels[utils.LabelStarted]); err == nil {
+		started = label
+	}
+
+	entry := PsEntry{
+		Container: containerName(ctr),
+		ID:        shortID(ctr.ID),
+		Pim:       ctr.Labels[utils.LabelPim],
+		Version:   ctr.Labels[utils.LabelVersion],
+		Started:   started.UTC().Format(time.RFC3339),
+		Uptime:    pc.now().Sub(started).Round(time.Second).String(),
+		Ports:     []string{},
+		Workdir:   ctr.Labels[utils.LabelWorkdir],
+	}
+
+	for _, port := range ctr.Ports {
+		if port.PublicPort != 0 {
+			entry.Ports = append(entry.Ports, fmt.Sprintf("%d->%d/%s", port.PublicPort, port.PrivatePort, port.Type))
+		} else {
+			entry.Ports = append(entry.Ports, fmt.Sprintf("%d/%s", port.PrivatePort, port.Type))
+		}
+	}
+
+	return entry
+}
+
+//containerName - Gets the name of a container without the leading slash Docker adds
+func containerName(ctr types.Container) string {
+	if len(ctr.Names) == 0 {
+		return shortID(ctr.ID)
+	}
+
+	return strings.TrimPrefix(ctr.Names[0], "/")
+}
+
+//shortID - Gets the short form of a container ID that Docker shows
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+
+	return id
+}
+
+//renderPs - Creates the markdown table listing the running pims
+func renderPs(entries []PsEntry) string {
+	if len(entries) == 0 {
+		return "*No pims are running*"
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("# Running pims\n")
+	sb.WriteString("| container | pim | uptime | ports | working directory |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, entry := range entries {
+		workdir := ""
+
+		//Paths are shown as code so that they are rendered as is
+		if entry.Workdir != "" {
+			workdir = "`" + entry.Workdir + "`"
+		}
+
+		sb.WriteString(fmt.Sprintf("| %s | %s:%s | %s | %s | %s |\n", entry.Container, entry.Pim, entry.Version, entry.Uptime, strings.Join(entry.Ports, ", "), workdir))
+	}
+
+	return sb.String()
+}
+
+//psText - Creates the plain text listing the running pims, one line per container
+func psText(entries []PsEntry) string {
+	if len(entries) == 0 {
+		return "No pims are running"
+	}
+
+	var lines []string
+
+	for _, entry := range entries {
+		line := fmt.Sprintf("%s %s:%s up %s", entry.Container, entry.Pim, entry.Version, entry.Uptime)
+
+		if len(entry.Ports) > 0 {
+			line += " ports " + strings.Join(entry.Ports, ", ")
+		}
+
+		if entry.Workdir != "" {
+			line += " workdir " + entry.Workdir
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
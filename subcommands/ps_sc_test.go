@@ -0,0 +1,117 @@
+package subcommands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/everettraven/packageless/utils"
+)
+
+func TestPsName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	pc := NewPsCommand(mu)
+
+	if pc.Name() != "ps" {
+		t.Fatalf("The ps subcommand's name should be: 'ps' but was '%s'", pc.Name())
+	}
+}
+
+//Tests the running pims are listed with their uptime, ports and working directory
+func TestPsFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.Containers = []types.Container{
+		{
+			ID:      "0123456789abcdef",
+			Names:   []string{"/python"},
+			Created: time.Date(2021, 9, 1, 11, 59, 0, 0, time.UTC).Unix(),
+			Labels: map[string]string{
+				utils.LabelPim:     "python",
+				utils.LabelVersion: "latest",
+				utils.LabelStarted: "2021-09-01T12:00:00Z",
+				utils.LabelWorkdir: "/home/user/project",
+			},
+			Ports: []types.Port{{PrivatePort: 3000, PublicPort: 3001, Type: "tcp"}},
+		},
+		{
+			ID:      "fedcba9876543210",
+			Names:   []string{"/node"},
+			Created: time.Date(2021, 9, 1, 11, 0, 0, 0, time.UTC).Unix(),
+			Labels: map[string]string{
+				utils.LabelPim:     "node",
+				utils.LabelVersion: "16",
+			},
+		},
+	}
+
+	pc := NewPsCommand(mu)
+	pc.now = func() time.Time { return time.Date(2021, 9, 1, 13, 30, 0, 0, time.UTC) }
+
+	err := pc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"ListPimContainers", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	//The container without a start label uses its creation time and is listed first as it has been running the longest
+	expected := []PsEntry{
+		{
+			Container: "node",
+			ID:        "fedcba987654",
+			Pim:       "node",
+			Version:   "16",
+			Started:   "2021-09-01T11:00:00Z",
+			Uptime:    "2h30m0s",
+			Ports:     []string{},
+		},
+		{
+			Container: "python",
+			ID:        "0123456789ab",
+			Pim:       "python",
+			Version:   "latest",
+			Started:   "2021-09-01T12:00:00Z",
+			Uptime:    "1h30m0s",
+			Ports:     []string{"3001->3000/tcp"},
+			Workdir:   "/home/user/project",
+		},
+	}
+
+	if !reflect.DeepEqual(expected, mu.Events[0].Data) {
+		t.Fatalf("Ps: Expected entries: %+v | Received: %+v", expected, mu.Events[0].Data)
+	}
+}
+
+//Test the ps subcommand getting an error when listing the containers
+func TestPsErrorAtListPimContainers(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.ErrorAt = "ListPimContainers"
+
+	pc := NewPsCommand(mu)
+
+	err := pc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pc.Run()
+
+	if err == nil || err.Error() != mu.ErrorMsg {
+		t.Fatalf("Ps: Expected error: %s | Received: %v", mu.ErrorMsg, err)
+	}
+}
@@ -0,0 +1,369 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//rollbackTimeFormat - Format of the generations of rollbacks, the time of the upgrade that created them.
+//It sorts in chronological order and only has characters that can be used in an image tag
+const rollbackTimeFormat = "20060102T150405Z"
+
+//Rollback Sub-Command Object
+type RollbackCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//String for the name of the pim to roll back
+	name string
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//Instantiation method for a new RollbackCommand
+func NewRollbackCommand(tools utils.Tools, config utils.Config) *RollbackCommand {
+	//Create a new RollbackCommand and set the FlagSet
+	rc := &RollbackCommand{
+		fs:     flag.NewFlagSet("rollback", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	return rc
+}
+
+//Name - Gets the name of the Sub-Command
+func (rc *RollbackCommand) Name() string {
+	return rc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (rc *RollbackCommand) Description() string {
+	return "Restore the image and data directories pims had before they were last upgraded"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (rc *RollbackCommand) Usage() string {
+	return "packageless rollback [OPTIONS] [pim[:version]...]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (rc *RollbackCommand) Flags() *flag.FlagSet {
+	return rc.fs
+}
+
+//Batch - The rollback subcommand is ran once for every pim passed as an argument
+func (rc *RollbackCommand) Batch() bool {
+	return true
+}
+
+//Init - Parses and Populates values of the Rollback subcommand
+func (rc *RollbackCommand) Init(args []string) error {
+	err := rc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = rc.fs.Args()
+
+	if len(args) <= 0 {
+		return errors.New("No pim name was found. You must include the name of the pim you wish to roll back.")
+	}
+
+	rc.name = args[0]
+
+	return nil
+}
+
+//Run - Runs the Rollback subcommand
+func (rc *RollbackCommand) Run() error {
+	pimName, pimVersion := splitPimName(rc.name)
+
+	pimConfigDir := rc.config.BaseDir + rc.config.PimsConfigDir
+	pimPath := pimConfigDir + pimName + ".hcl"
+	pimDir := rc.config.BaseDir + rc.config.PimsDir
+
+	//Check if pim config exists
+	if !rc.tools.FileExists(pimPath) {
+		return errors.New("Could not find pim configuration for: " + pimName + " has it been installed?")
+	}
+
+	pimListBody, err := rc.tools.GetHCLBody(pimPath)
+
+	if err != nil {
+		return err
+	}
+
+	//Parse the pim list
+	parseOut, err := rc.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+	if err != nil {
+		return err
+	}
+
+	pim, version, found := findPimVersion(parseOut.(utils.PimHCLUtil), pimName, pimVersion)
+
+	if !found {
+		return errors.New("Could not find pim " + pimName + " with version '" + pimVersion + "' in the pim list")
+	}
+
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	generations, err := rollbackGenerations(rc.tools, pim.Name, version.Version, cli)
+
+	if err != nil {
+		return err
+	}
+
+	//Reference to the pim used in the events
+	pimRef := pim.Name + ":" + version.Version
+
+	if len(generations) == 0 {
+		return errors.New("There is nothing to roll back for " + pimRef + ", it has not been upgraded since rollbacks were enabled")
+	}
+
+	//Roll back to the generation of the last upgrade
+	generation := generations[len(generations)-1]
+	rollbackTag := utils.RollbackTag(pim.Name, version.Version, generation)
+	snapshotDir := rollbackDir(rc.config, pim.Name, version.Version, generation)
+
+	rc.tools.Emit(utils.Event{Type: utils.EventStarted, Pim: pimRef, Message: "Rolling back"})
+
+	stepStarted(rc.tools, pimRef, "restore_image", "Restoring image "+version.Image)
+
+	err = rc.tools.TagImage(rollbackTag, version.Image, cli)
+
+	if err != nil {
+		return err
+	}
+
+	stepFinished(rc.tools, pimRef, "restore_image")
+
+	stepStarted(rc.tools, pimRef, "restore_directories", "Restoring pim directories")
+
+	for _, vol := range version.Volumes {
+		//Directories that did not exist before the upgrade were not saved
+		if vol.Path != "" && rc.tools.FileExists(snapshotDir+vol.Path) {
+			err = rc.tools.CopyDir(snapshotDir+vol.Path, pimDir+vol.Path)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	stepFinished(rc.tools, pimRef, "restore_directories")
+
+	//The generation has been restored so the next rollback goes back further
+	stepStarted(rc.tools, pimRef, "remove_rollback", "Removing the restored rollback")
+
+	err = removeRollback(rc.tools, rc.config, pim.Name, version.Version, generation, cli)
+
+	if err != nil {
+		return err
+	}
+
+	stepFinished(rc.tools, pimRef, "remove_rollback")
+
+	rc.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: pimRef, Message: "successfully rolled back to the image from before the upgrade of " + generationTime(generation)})
+
+	return nil
+}
+
+//rollbackBaseDir - Gets the directory that the data directories of every generation are saved in
+func rollbackBaseDir(config utils.Config) string {
+	return config.BaseDir + "rollback/"
+}
+
+//rollbackDir - Gets the directory that the data directories of a generation are saved in
+func rollbackDir(config utils.Config, pim string, version string, generation string) string {
+	return rollbackBaseDir(config) + pim + "/" + version + "/" + generation + "/"
+}
+
+//generationTime - Gets the time of the upgrade that created a generation in a readable format
+func generationTime(generation string) string {
+	t, err := time.Parse(rollbackTimeFormat, generation)
+
+	if err != nil {
+		return generation
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+//rollbackGenerations - Gets the generations of rollbacks that exist for a version of a pim, from the oldest to the newest
+func rollbackGenerations(tools utils.Tools, pim string, version string, cli utils.Client) ([]string, error) {
+	generations, _, err := rollbackImages(tools, pim, version, cli)
+
+	return generations, err
+}
+
+//rollbackImages - Gets the generations of rollbacks that exist for a version of a pim, from the oldest to the newest,
+//and the IDs of the images they keep by generation
+func rollbackImages(tools utils.Tools, pim string, version string, cli utils.Client) ([]string, map[string]string, error) {
+	images, err := tools.ListImages(cli)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prefix := utils.RollbackTag(pim, version, "")
+
+	var generations []string
+	ids := make(map[string]string)
+
+	for _, img := range images {
+		for _, tag := range img.RepoTags {
+			if !strings.HasPrefix(tag, prefix) {
+				continue
+			}
+
+			generation := strings.TrimPrefix(tag, prefix)
+
+			//The tags of versions that start with this version, e.g. 3-beta for 3, are not generations of it
+			if _, err := time.Parse(rollbackTimeFormat, generation); err == nil {
+				generations = append(generations, generation)
+				ids[generation] = img.ID
+			}
+		}
+	}
+
+	sort.Strings(generations)
+
+	return generations, ids, nil
+}
+
+//saveRollback - Keeps the image and data directories a version of a pim has before it is upgraded.
+//The generation that was created is returned with the ID of the image it keeps, it is empty if nothing was saved
+func saveRollback(tools utils.Tools, config utils.Config, pim string, version utils.Version, now time.Time, cli utils.Client) (string, string, error) {
+	if config.RollbackGens <= 0 {
+		return "", "", nil
+	}
+
+	img, imgExist, err := tools.FindImage(version.Image, cli)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	//There is nothing to roll back to if the image has not been downloaded
+	if !imgExist {
+		return "", "", nil
+	}
+
+	generations, ids, err := rollbackImages(tools, pim, version.Version, cli)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	//The image has not changed since the last upgrade, which the newest generation can already roll back
+	if len(generations) > 0 && ids[generations[len(generations)-1]] == img.ID {
+		return "", "", nil
+	}
+
+	generation := now.UTC().Format(rollbackTimeFormat)
+
+	//The image is tagged by its ID so that pulling the new image does not move the rollback tag
+	err = tools.TagImage(img.ID, utils.RollbackTag(pim, version.Version, generation), cli)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	pimDir := config.BaseDir + config.PimsDir
+	snapshotDir := rollbackDir(config, pim, version.Version, generation)
+
+	for _, vol := range version.Volumes {
+		if vol.Path != "" && tools.FileExists(pimDir+vol.Path) {
+			err = tools.CopyDir(pimDir+vol.Path, snapshotDir+vol.Path)
+
+			if err != nil {
+				return "", "", err
+			}
+		}
+	}
+
+	return generation, img.ID, nil
+}
+
+//keepRollback - Keeps the generation saved before the image of a version of a pim was pulled if the pull changed the image,
+//removing the oldest generations so that only the configured number of generations is kept
+func keepRollback(tools utils.Tools, config utils.Config, pim string, version utils.Version, generation string, imageID string, cli utils.Client) error {
+	if generation == "" {
+		return nil
+	}
+
+	img, imgExist, err := tools.FindImage(version.Image, cli)
+
+	if err != nil {
+		return err
+	}
+
+	//An upgrade that did not change the image has nothing to roll back, keeping it would expire an older generation that does
+	if imgExist && img.ID == imageID {
+		return removeRollback(tools, config, pim, version.Version, generation, cli)
+	}
+
+	generations, err := rollbackGenerations(tools, pim, version.Version, cli)
+
+	if err != nil {
+		return err
+	}
+
+	//Expire the oldest generations
+	for len(generations) > config.RollbackGens {
+		err = removeRollback(tools, config, pim, version.Version, generations[0], cli)
+
+		if err != nil {
+			return err
+		}
+
+		generations = generations[1:]
+	}
+
+	return nil
+}
+
+//removeRollbacks - Removes every generation of a version of a pim, e.g. when it is uninstalled
+func removeRollbacks(tools utils.Tools, config utils.Config, pim string, version string, cli utils.Client) error {
+	generations, err := rollbackGenerations(tools, pim, version, cli)
+
+	if err != nil {
+		return err
+	}
+
+	for _, generation := range generations {
+		err = removeRollback(tools, config, pim, version, generation, cli)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return tools.RemoveDir(rollbackBaseDir(config) + pim + "/" + version + "/")
+}
+
+//removeRollback - Removes the image tag and data directories of a generation
+func removeRollback(tools utils.Tools, config utils.Config, pim string, version string, generation string, cli utils.Client) error {
+	err := tools.RemoveImage(utils.RollbackTag(pim, version, generation), cli)
+
+	if err != nil {
+		return err
+	}
+
+	return tools.RemoveDir(rollbackDir(config, pim, version, generation))
+}
@@ -0,0 +1,263 @@
+package subcommands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Create a mock utility with python installed and upgraded twice before
+func rollbackTestUtility() *utils.MockUtility {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+	mu.ImgSummary = types.ImageSummary{ID: "sha256:current", RepoTags: []string{"packageless/python"}}
+
+	mu.Images = []types.ImageSummary{
+		{ID: "sha256:older", RepoTags: []string{utils.RollbackTag("python", "latest", "20260101T000000Z")}},
+		{ID: "sha256:old", RepoTags: []string{utils.RollbackTag("python", "latest", "20260201T000000Z")}},
+		{ID: "sha256:beta", RepoTags: []string{utils.RollbackTag("python", "latest-beta", "20260301T000000Z")}},
+		mu.ImgSummary,
+	}
+
+	return mu
+}
+
+func TestRollbackName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	rc := NewRollbackCommand(mu, mu.Conf)
+
+	if rc.Name() != "rollback" {
+		t.Fatalf("The rollback subcommand's name should be: 'rollback' but was '%s'", rc.Name())
+	}
+}
+
+//Test upgrading keeps the current image and data directories and expires the oldest generation
+func TestUpgradeSavesRollback(t *testing.T) {
+	mu := rollbackTestUtility()
+	mu.PulledImgSummary = types.ImageSummary{ID: "sha256:new", RepoTags: []string{"packageless/python"}}
+
+	config := mu.Conf
+	config.RollbackGens = 1
+
+	ic := NewUpgradeCommand(mu, &utils.MockCopyTool{}, config)
+	ic.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	err := ic.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generation := "20260301T120000Z"
+
+	if mu.TaggedImages[utils.RollbackTag("python", "latest", generation)] != "sha256:current" {
+		t.Fatalf("Upgrade: Expected the current image to be tagged for rollback | Received: %v", mu.TaggedImages)
+	}
+
+	pimDir := config.BaseDir + config.PimsDir
+	snapshot := rollbackDir(config, "python", "latest", generation) + "a/path"
+
+	if mu.CopiedDirs[snapshot] != pimDir+"a/path" {
+		t.Fatalf("Upgrade: Expected the data directory to be saved to %s | Received: %v", snapshot, mu.CopiedDirs)
+	}
+
+	//The mock does not list the new tag, so only the newest of the existing generations is kept
+	expired := []string{utils.RollbackTag("python", "latest", "20260101T000000Z")}
+
+	if !reflect.DeepEqual(expired, mu.RemovedImgs) {
+		t.Fatalf("Upgrade: Expected the oldest generation to be expired: %v | Received: %v", expired, mu.RemovedImgs)
+	}
+
+	expiredDirs := []string{rollbackDir(config, "python", "latest", "20260101T000000Z")}
+
+	if !reflect.DeepEqual(expiredDirs, mu.RemovedDirs) {
+		t.Fatalf("Upgrade: Expected the directories of the oldest generation to be expired: %v | Received: %v", expiredDirs, mu.RemovedDirs)
+	}
+}
+
+//Test an upgrade that does not change the image keeps no new generation and expires none of the existing ones
+func TestUpgradeUnchangedImageKeepsRollbacks(t *testing.T) {
+	mu := rollbackTestUtility()
+
+	config := mu.Conf
+	config.RollbackGens = 2
+
+	ic := NewUpgradeCommand(mu, &utils.MockCopyTool{}, config)
+	ic.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	err := ic.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generation := "20260301T120000Z"
+
+	removed := []string{utils.RollbackTag("python", "latest", generation)}
+
+	if !reflect.DeepEqual(removed, mu.RemovedImgs) {
+		t.Fatalf("Upgrade: Expected only the new generation to be removed: %v | Received: %v", removed, mu.RemovedImgs)
+	}
+
+	removedDirs := []string{rollbackDir(config, "python", "latest", generation)}
+
+	if !reflect.DeepEqual(removedDirs, mu.RemovedDirs) {
+		t.Fatalf("Upgrade: Expected only the directories of the new generation to be removed: %v | Received: %v", removedDirs, mu.RemovedDirs)
+	}
+}
+
+//Test no generation is saved when the image is the one the newest generation already keeps
+func TestUpgradeSkipsRollbackOfSameImage(t *testing.T) {
+	mu := rollbackTestUtility()
+	mu.ImgSummary = types.ImageSummary{ID: "sha256:old", RepoTags: []string{"packageless/python"}}
+
+	config := mu.Conf
+	config.RollbackGens = 1
+
+	ic := NewUpgradeCommand(mu, &utils.MockCopyTool{}, config)
+
+	err := ic.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mu.TaggedImages) != 0 || len(mu.RemovedImgs) != 0 {
+		t.Fatalf("Upgrade: Expected no generation to be saved or expired | Tagged: %v | Removed: %v", mu.TaggedImages, mu.RemovedImgs)
+	}
+}
+
+//Test uninstalling a pim removes every generation of its rollbacks
+func TestUninstallRemovesRollbacks(t *testing.T) {
+	mu := rollbackTestUtility()
+
+	uc := NewUninstallCommand(mu, mu.Conf)
+
+	err := uc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = uc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed := []string{
+		mu.Pim.Pims[0].Versions[0].Image,
+		utils.RollbackTag("python", "latest", "20260101T000000Z"),
+		utils.RollbackTag("python", "latest", "20260201T000000Z"),
+	}
+
+	if !reflect.DeepEqual(removed, mu.RemovedImgs) {
+		t.Fatalf("Uninstall: Expected the image and its rollbacks to be removed: %v | Received: %v", removed, mu.RemovedImgs)
+	}
+}
+
+//Test rolling back restores the image and data directories of the last upgrade
+func TestRollbackFlow(t *testing.T) {
+	mu := rollbackTestUtility()
+
+	rc := NewRollbackCommand(mu, mu.Conf)
+
+	err := rc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"FileExists",
+		"GetHCLBody",
+		"ParseBody",
+		"ListImages",
+		"Emit",
+		"Emit",
+		"TagImage",
+		"Emit",
+		"Emit",
+		"FileExists",
+		"CopyDir",
+		"Emit",
+		"Emit",
+		"RemoveImage",
+		"RemoveDir",
+		"Emit",
+		"Emit",
+	}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	generation := "20260201T000000Z"
+	rollbackTag := utils.RollbackTag("python", "latest", generation)
+
+	if mu.TaggedImages["packageless/python"] != rollbackTag {
+		t.Fatalf("Rollback: Expected the image to be restored from %s | Received: %v", rollbackTag, mu.TaggedImages)
+	}
+
+	pimDir := mu.Conf.BaseDir + mu.Conf.PimsDir
+	snapshot := rollbackDir(mu.Conf, "python", "latest", generation) + "a/path"
+
+	if mu.CopiedDirs[pimDir+"a/path"] != snapshot {
+		t.Fatalf("Rollback: Expected the data directory to be restored from %s | Received: %v", snapshot, mu.CopiedDirs)
+	}
+
+	if !reflect.DeepEqual([]string{rollbackTag}, mu.RemovedImgs) {
+		t.Fatalf("Rollback: Expected the restored generation to be removed | Received: %v", mu.RemovedImgs)
+	}
+}
+
+//Test rolling back a pim that has not been upgraded
+func TestRollbackNothingToRollBack(t *testing.T) {
+	mu := rollbackTestUtility()
+	mu.Images = []types.ImageSummary{mu.ImgSummary}
+
+	rc := NewRollbackCommand(mu, mu.Conf)
+
+	err := rc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rc.Run()
+
+	expected := "There is nothing to roll back for python:latest, it has not been upgraded since rollbacks were enabled"
+
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Rollback: Expected error: %s | Received: %v", expected, err)
+	}
+}
@@ -3,8 +3,10 @@ package subcommands
 import (
 	"errors"
 	"flag"
+	"fmt"
+	"os"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/docker/docker/client"
 	"github.com/everettraven/packageless/utils"
@@ -20,6 +22,13 @@ type RunCommand struct {
 
 	args []string
 
+	//Show how the version of the pim is chosen instead of running it
+	explain bool
+
+	//Always or never give the container a terminal, instead of giving it one only when packageless runs in a terminal
+	tty   bool
+	noTTY bool
+
 	tools utils.Tools
 
 	config utils.Config
@@ -34,16 +43,53 @@ func NewRunCommand(tools utils.Tools, config utils.Config) *RunCommand {
 		config: config,
 	}
 
+	rc.fs.BoolVar(&rc.explain, "explain", false, "show how the version of the pim is chosen instead of running it")
+	rc.fs.BoolVar(&rc.tty, "tty", false, "give the pim a terminal even if packageless does not run in one")
+	rc.fs.BoolVar(&rc.noTTY, "no-tty", false, "do not give the pim a terminal even if packageless runs in one")
+
 	return rc
 }
 
+//RunResolution - How the version of the pim to run was chosen
+type RunResolution struct {
+	Pim         string `json:"pim"`
+	Version     string `json:"version"`
+	Explanation string `json:"explanation"`
+}
+
 //Name - Gets the name of the Sub-Command
 func (rc *RunCommand) Name() string {
 	return rc.fs.Name()
 }
 
+//Description - Gets the description of the Sub-Command
+func (rc *RunCommand) Description() string {
+	return "Run an installed pim"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (rc *RunCommand) Usage() string {
+	return "packageless run [OPTIONS] [pim] [ARGUMENTS]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (rc *RunCommand) Flags() *flag.FlagSet {
+	return rc.fs
+}
+
 //Init - Parses and Populates values of the Run subcommand
 func (rc *RunCommand) Init(args []string) error {
+	err := rc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	if rc.tty && rc.noTTY {
+		return errors.New("The --tty and --no-tty options can not be used together")
+	}
+
+	args = rc.fs.Args()
 
 	if len(args) <= 0 {
 		return errors.New("No pim name was found. You must include the name of the pim you wish to run.")
@@ -58,50 +104,138 @@ func (rc *RunCommand) Init(args []string) error {
 
 //Run - Runs the Run subcommand
 func (rc *RunCommand) Run() error {
-	//Create variables to use later
-	var found bool
-	var pim utils.PackageImage
-	var version utils.Version
+	//The version comes from the project manifest when it is not specified
+	pimName, pimVersion, explanation, err := resolvePimName(rc.tools, rc.name)
 
-	var pimName string
-	var pimVersion string
+	if err != nil {
+		return err
+	}
 
-	if strings.Contains(rc.name, ":") {
-		split := strings.Split(rc.name, ":")
-		pimName = split[0]
-		pimVersion = split[1]
-	} else {
-		pimName = rc.name
-		pimVersion = "latest"
+	if rc.explain {
+		rc.tools.Emit(utils.Event{
+			Type:     utils.EventResult,
+			Message:  pimName + ":" + pimVersion + ", " + explanation,
+			Data:     RunResolution{Pim: pimName, Version: pimVersion, Explanation: explanation},
+			Markdown: "*Using " + pimName + ":" + pimVersion + ", " + explanation + "*",
+		})
+
+		return nil
 	}
 
+	//The pim writes to stdout, so what packageless outputs goes to stderr to keep the two apart
+	rc.tools.RedirectOutput(os.Stderr)
+
 	//Create the Docker client
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return err
 	}
 
-	pimConfigDir := rc.config.BaseDir + rc.config.PimsConfigDir
+	plan, err := resolveRun(rc.tools, rc.config, pimName, pimVersion)
+
+	if err != nil {
+		return err
+	}
+
+	pim := plan.pim
+	version := plan.version
+
+	//Check if the corresponding pim image is already installed
+	imgExist, err := rc.tools.ImageExists(version.Image, cli)
+
+	//Check for errors
+	if err != nil {
+		return err
+	}
+
+	//If the image exists the pim is already installed
+	if !imgExist {
+		return errors.New("pim " + pim.Name + " with version '" + version.Version + "' is not installed. You must install the pim before running it.")
+	}
+
+	//Reference to the pim used in the events
+	pimRef := pim.Name + ":" + version.Version
+
+	for _, port := range plan.published {
+		message := fmt.Sprintf("Port %s/%s of %s is published on host port %d", port.Container, port.Protocol, pimRef, port.Host)
+
+		if port.Host == 0 {
+			message = fmt.Sprintf("Port %s/%s of %s is published on a host port picked by Docker, which is shown by packageless ps", port.Container, port.Protocol, pimRef)
+		}
+
+		rc.tools.Emit(utils.Event{Type: utils.EventMessage, Pim: pimRef, Message: message})
+	}
+
+	//The step has no message as the output of the container is all that should be shown
+	stepStarted(rc.tools, pimRef, "run_container", "")
+
+	//Run the container
+	opts := plan.options
+	opts.Args = rc.args
+
+	if rc.tty || rc.noTTY {
+		opts.TTY = &rc.tty
+	}
+
+	exitCode, err := rc.tools.RunContainer(opts, cli)
+
+	if err != nil {
+		return err
+	}
+
+	//The pim reports its own errors, packageless exits with the same exit code so it can be used in scripts
+	if exitCode != 0 {
+		return &ExitError{Code: exitCode}
+	}
+
+	stepFinished(rc.tools, pimRef, "run_container")
+
+	return nil
+}
+
+//runPlan - How a pim is run, resolved the same way by the run and which subcommands
+type runPlan struct {
+	//Path of the pim configuration the pim is defined in
+	configPath string
+
+	pim     utils.PackageImage
+	version utils.Version
+
+	//How the container is run, without the arguments passed to the pim
+	options utils.RunOptions
+
+	//Ports of the container with the host ports they are published on, 0 when Docker picks the host port
+	published []utils.Port
+}
+
+//resolveRun - Finds the version of the pim in its pim configuration and computes how its container is run
+func resolveRun(tools utils.Tools, config utils.Config, pimName string, pimVersion string) (runPlan, error) {
+	//Create variables to use later
+	var found bool
+	var pim utils.PackageImage
+	var version utils.Version
+
+	pimConfigDir := config.BaseDir + config.PimsConfigDir
 
 	//Default location of the pim list
 	pimList := pimConfigDir + pimName + ".hcl"
 
-	if !rc.tools.FileExists(pimList) {
-		return errors.New("Could not find a configuration file for '" + pimName + "' has it been installed?")
+	if !tools.FileExists(pimList) {
+		return runPlan{}, errors.New("Could not find a configuration file for '" + pimName + "' has it been installed?")
 	}
 
-	pimListBody, err := rc.tools.GetHCLBody(pimList)
+	pimListBody, err := tools.GetHCLBody(pimList)
 
 	if err != nil {
-		return err
+		return runPlan{}, err
 	}
 
 	//Parse the pim list
-	parseOut, err := rc.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+	parseOut, err := tools.ParseBody(pimListBody, utils.PimHCLUtil{})
 
 	//Check for errors
 	if err != nil {
-		return err
+		return runPlan{}, err
 	}
 
 	pims := parseOut.(utils.PimHCLUtil)
@@ -124,50 +258,93 @@ func (rc *RunCommand) Run() error {
 
 	//Make sure we have found the pim in the pim list
 	if !found {
-		return errors.New("Could not find pim " + pimName + " with version '" + pimVersion + "' in the pim configuration")
-	}
-
-	//Check if the corresponding pim image is already installed
-	imgExist, err := rc.tools.ImageExists(version.Image, cli)
-
-	//Check for errors
-	if err != nil {
-		return err
-	}
-
-	//If the image exists the pim is already installed
-	if !imgExist {
-		return errors.New("pim " + pim.Name + " with version '" + version.Version + "' is not installed. You must install the pim before running it.")
+		return runPlan{}, errors.New("Could not find pim " + pimName + " with version '" + pimVersion + "' in the pim configuration")
 	}
 
 	//Create the variables to use when running the container
 	var ports []string
 	var volumes []string
 
-	ports = append(ports, strconv.Itoa(rc.config.StartPort)+":"+version.Port)
+	pimRef := pim.Name + ":" + version.Version
+
+	published := version.PublishedPorts()
 
-	pimDir := rc.config.BaseDir + rc.config.PimsDir
+	//Host ports given to the ports of this run, so that two ports are not published on the same host port
+	taken := map[int]bool{}
+
+	for i, port := range published {
+		containerPort := port.Container + "/" + port.Protocol
+
+		//Another run of the pim, or any other program, may already use the host port
+		if port.Host != 0 && (taken[port.Host] || !tools.PortAvailable(port.Protocol, port.Host)) {
+			tools.Emit(utils.Event{Type: utils.EventWarning, Pim: pimRef, Message: fmt.Sprintf("Port %d is already in use, port %s of %s is published on another host port instead", port.Host, containerPort, pimRef)})
+			port.Host = 0
+		}
+
+		if port.Host == 0 {
+			port.Host = allocatePort(tools, config, port.Protocol, taken)
+		}
+
+		published[i] = port
+
+		//Docker picks a free host port when none was found
+		if port.Host == 0 {
+			tools.Emit(utils.Event{Type: utils.EventWarning, Pim: pimRef, Message: fmt.Sprintf("No free host port was found from port %d, port %s of %s is published on a host port picked by Docker instead", config.StartPort, containerPort, pimRef)})
+			ports = append(ports, containerPort)
+			continue
+		}
+
+		taken[port.Host] = true
+		ports = append(ports, strconv.Itoa(port.Host)+":"+containerPort)
+	}
+
+	pimDir := config.BaseDir + config.PimsDir
+
+	//Label the container so that it can be found by ps and stop
+	labels := map[string]string{
+		utils.LabelPim:     pim.Name,
+		utils.LabelVersion: version.Version,
+		utils.LabelStarted: time.Now().UTC().Format(time.RFC3339),
+	}
 
 	for _, vol := range version.Volumes {
 		if vol.Path != "" {
 			volumes = append(volumes, pimDir+vol.Path+":"+vol.Mount)
 		} else {
-			sourcePath, err := rc.tools.Getwd()
+			sourcePath, err := tools.Getwd()
 
 			if err != nil {
-				return err
+				return runPlan{}, err
 			}
 
 			volumes = append(volumes, sourcePath+":"+vol.Mount)
+			labels[utils.LabelWorkdir] = sourcePath
 		}
 	}
 
-	//Run the container
-	_, err = rc.tools.RunContainer(version.Image, ports, volumes, pim.Name, rc.args)
+	options := utils.RunOptions{
+		Image:   version.Image,
+		Name:    utils.ContainerName(pim.Name, version.Version),
+		Ports:   ports,
+		Volumes: volumes,
+		Labels:  labels,
+	}
 
-	if err != nil {
-		return err
+	return runPlan{configPath: pimList, pim: pim, version: version, options: options, published: published}, nil
+}
+
+//allocatePort - Finds a free host port from the start_port, stepping by the port_increment. The port is 0 if there is none
+func allocatePort(tools utils.Tools, config utils.Config, protocol string, taken map[int]bool) int {
+	for port := config.StartPort; port > 0 && port <= 65535; port += config.PortInc {
+		if !taken[port] && tools.PortAvailable(protocol, port) {
+			return port
+		}
+
+		//Without an increment only the start port can be used
+		if config.PortInc <= 0 {
+			break
+		}
 	}
 
-	return nil
+	return 0
 }
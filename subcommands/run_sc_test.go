@@ -1,9 +1,13 @@
 package subcommands
 
 import (
+	"errors"
+	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/everettraven/packageless/utils"
 )
@@ -58,6 +62,54 @@ func TestRunInit(t *testing.T) {
 	}
 }
 
+//Test the Run subcommand does not give the pim a terminal with --no-tty
+func TestRunNoTTY(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+
+	config := utils.Config{
+		BaseDir:        "~/.packageless/",
+		StartPort:      3000,
+		PortInc:        1,
+		Alias:          true,
+		RepositoryHost: "https://raw.githubusercontent.com/everettraven/packageless-pims/main/pims/",
+		PimsConfigDir:  "pims_config/",
+		PimsDir:        "pims/",
+	}
+
+	rc := NewRunCommand(mu, config)
+
+	err := rc.Init([]string{"--no-tty", "python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if mu.RunTTY == nil || *mu.RunTTY {
+		t.Fatalf("RunContainer: Expected the pim not to be given a terminal. Received TTY: %v", mu.RunTTY)
+	}
+}
+
+//Test the Run subcommand returns an error when both --tty and --no-tty are used
+func TestRunTTYConflict(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	rc := NewRunCommand(mu, utils.Config{})
+
+	err := rc.Init([]string{"--tty", "--no-tty", "python"})
+
+	if err == nil {
+		t.Fatal("Expected an error when both --tty and --no-tty are used but did not receive one")
+	}
+}
+
 //Test the Run subcommand with no pim specified
 func TestRunNoPackage(t *testing.T) {
 	mu := utils.NewMockUtility()
@@ -127,6 +179,9 @@ func TestRunNonExistPackage(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -177,9 +232,13 @@ func TestRunImageNotExist(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
+		"PortAvailable",
 		"ImageExists",
 	}
 
@@ -222,11 +281,18 @@ func TestRunFlowNoRunArgs(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
+		"PortAvailable",
 		"ImageExists",
+		"Emit",
+		"Emit",
 		"RunContainer",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -241,7 +307,7 @@ func TestRunFlowNoRunArgs(t *testing.T) {
 
 	pimDir := config.BaseDir + config.PimsDir
 
-	port := []string{strconv.Itoa(mu.Conf.StartPort) + ":" + mu.Pim.Pims[0].Versions[0].Port}
+	port := []string{strconv.Itoa(mu.Conf.StartPort) + ":" + mu.Pim.Pims[0].Versions[0].Port + "/tcp"}
 	volume := []string{pimDir + mu.Pim.Pims[0].Versions[0].Volumes[0].Path + ":" + mu.Pim.Pims[0].Versions[0].Volumes[0].Mount}
 
 	//Make sure the ports passed in matches
@@ -258,6 +324,20 @@ func TestRunFlowNoRunArgs(t *testing.T) {
 	if len(mu.RunArgs) > 0 {
 		t.Fatalf("RunContainer: RunArgs were received but no RunArgs were expected. Received RunArgs: %v", mu.RunArgs)
 	}
+
+	//Make sure the container is labelled with the pim
+	if mu.RunLabels[utils.LabelPim] != "python" || mu.RunLabels[utils.LabelVersion] != "latest" {
+		t.Fatalf("RunContainer: Labels do not match the pim. Received Labels: %v", mu.RunLabels)
+	}
+
+	if _, err := time.Parse(time.RFC3339, mu.RunLabels[utils.LabelStarted]); err != nil {
+		t.Fatalf("RunContainer: Expected the start time label to be in RFC 3339 format. Received Labels: %v", mu.RunLabels)
+	}
+
+	//No working directory is mounted
+	if _, ok := mu.RunLabels[utils.LabelWorkdir]; ok {
+		t.Fatalf("RunContainer: Expected no working directory label. Received Labels: %v", mu.RunLabels)
+	}
 }
 
 //Tests the flow of a correctly ran Run subcommand with run args
@@ -298,11 +378,18 @@ func TestRunFlowRunArgs(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
+		"PortAvailable",
 		"ImageExists",
+		"Emit",
+		"Emit",
 		"RunContainer",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -317,7 +404,7 @@ func TestRunFlowRunArgs(t *testing.T) {
 
 	pimDir := config.BaseDir + config.PimsDir
 
-	port := []string{strconv.Itoa(mu.Conf.StartPort) + ":" + mu.Pim.Pims[0].Versions[0].Port}
+	port := []string{strconv.Itoa(mu.Conf.StartPort) + ":" + mu.Pim.Pims[0].Versions[0].Port + "/tcp"}
 	volume := []string{pimDir + mu.Pim.Pims[0].Versions[0].Volumes[0].Path + ":" + mu.Pim.Pims[0].Versions[0].Volumes[0].Mount}
 
 	//Make sure the ports passed in matches
@@ -376,6 +463,9 @@ func TestRunErrorAtGetHCLBody(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 	}
@@ -426,6 +516,9 @@ func TestRunErrorAtParseBody(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -476,9 +569,13 @@ func TestRunErrorAtImageExists(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
+		"PortAvailable",
 		"ImageExists",
 	}
 
@@ -527,10 +624,16 @@ func TestRunErrorAtRunContainer(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
+		"PortAvailable",
 		"ImageExists",
+		"Emit",
+		"Emit",
 		"RunContainer",
 	}
 
@@ -539,6 +642,41 @@ func TestRunErrorAtRunContainer(t *testing.T) {
 	}
 }
 
+//Test the Run subcommand exits with the exit code of the container
+func TestRunContainerExitCode(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+
+	mu.RunExitCode = 2
+
+	config := utils.Config{
+		BaseDir:        "~/.packageless/",
+		StartPort:      3000,
+		PortInc:        1,
+		Alias:          true,
+		RepositoryHost: "https://raw.githubusercontent.com/everettraven/packageless-pims/main/pims/",
+		PimsConfigDir:  "pims_config/",
+		PimsDir:        "pims/",
+	}
+
+	rc := NewRunCommand(mu, config)
+
+	err := rc.Init([]string{"python", "-c", "exit(2)"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rc.Run()
+
+	var exitErr *ExitError
+
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("Expected the run subcommand to exit with exit code 2 | Received: %v", err)
+	}
+}
+
 //Test the Run subcommand with a pim with a nonexistent version specified
 func TestRunNonExistVersion(t *testing.T) {
 	mu := utils.NewMockUtility()
@@ -577,6 +715,7 @@ func TestRunNonExistVersion(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -626,6 +765,7 @@ func TestRunFileNotExists(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"RedirectOutput",
 		"FileExists",
 	}
 
@@ -669,17 +809,214 @@ func TestRunEmptyVolumePath(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"RedirectOutput",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
-		"ImageExists",
+		"PortAvailable",
 		"Getwd",
+		"ImageExists",
+		"Emit",
+		"Emit",
 		"RunContainer",
+		"Emit",
 	}
 
 	if !reflect.DeepEqual(callStack, mu.Calls) {
 		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
 	}
+
+	wd, err := os.Getwd()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//The working directory is mounted so it should be in the labels
+	if mu.RunLabels[utils.LabelWorkdir] != wd {
+		t.Fatalf("RunContainer: Expected the working directory label: %s | Received Labels: %v", wd, mu.RunLabels)
+	}
+}
+
+//Test the Run subcommand publishes the port on the next host port when the start port is in use
+func TestRunPortInUse(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+
+	mu.UsedPorts = map[int]bool{3000: true}
+
+	config := utils.Config{
+		BaseDir:        "~/.packageless/",
+		StartPort:      3000,
+		PortInc:        1,
+		Alias:          true,
+		RepositoryHost: "https://raw.githubusercontent.com/everettraven/packageless-pims/main/pims/",
+		PimsConfigDir:  "pims_config/",
+		PimsDir:        "pims/",
+	}
+
+	rc := NewRunCommand(mu, config)
+
+	err := rc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := []string{"3001:" + mu.Pim.Pims[0].Versions[0].Port + "/tcp"}
+
+	if !reflect.DeepEqual(mu.RunPorts, port) {
+		t.Fatalf("RunContainer: Ports do not match the expected Ports. Received Ports: %v | Expected Ports: %v", mu.RunPorts, port)
+	}
+
+	//The chosen host port is shown before the pim runs
+	if len(mu.Events) == 0 || mu.Events[0].Type != utils.EventMessage || !strings.Contains(mu.Events[0].Message, "host port 3001") {
+		t.Fatalf("Expected a message with the host port. Received Events: %v", mu.Events)
+	}
+}
+
+//Test the Run subcommand lets Docker pick the host port when no host port is free
+func TestRunNoFreePort(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+
+	mu.UsedPorts = map[int]bool{3000: true}
+
+	//Without an increment only the start port can be used
+	rc := NewRunCommand(mu, utils.Config{BaseDir: "~/.packageless/", StartPort: 3000, PortInc: 0, PimsConfigDir: "pims_config/", PimsDir: "pims/"})
+
+	err := rc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := []string{mu.Pim.Pims[0].Versions[0].Port + "/tcp"}
+
+	if !reflect.DeepEqual(mu.RunPorts, port) {
+		t.Fatalf("RunContainer: Ports do not match the expected Ports. Received Ports: %v | Expected Ports: %v", mu.RunPorts, port)
+	}
+
+	if len(mu.Events) == 0 || mu.Events[0].Type != utils.EventWarning {
+		t.Fatalf("Expected a warning that no host port is free. Received Events: %v", mu.Events)
+	}
+}
+
+//Test the Run subcommand publishes every port block of the version
+func TestRunPortBlocks(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+
+	mu.Pim.Pims[0].Versions[0].Port = ""
+	mu.Pim.Pims[0].Versions[0].Ports = []utils.Port{
+		{Container: "8080"},
+		{Container: "53", Protocol: "udp", Host: 5353},
+		{Container: "9090"},
+	}
+
+	//The fixed host port is in use so it falls back to the allocated ports
+	mu.UsedPorts = map[int]bool{3000: true, 5353: true}
+
+	rc := NewRunCommand(mu, utils.Config{BaseDir: "~/.packageless/", StartPort: 3000, PortInc: 10, PimsConfigDir: "pims_config/", PimsDir: "pims/"})
+
+	err := rc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ports := []string{"3010:8080/tcp", "3020:53/udp", "3030:9090/tcp"}
+
+	if !reflect.DeepEqual(mu.RunPorts, ports) {
+		t.Fatalf("RunContainer: Ports do not match the expected Ports. Received Ports: %v | Expected Ports: %v", mu.RunPorts, ports)
+	}
+}
+
+//Test the Run subcommand does not publish any port when the version has none
+func TestRunNoPorts(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+
+	mu.Pim.Pims[0].Versions[0].Port = ""
+
+	rc := NewRunCommand(mu, utils.Config{BaseDir: "~/.packageless/", StartPort: 3000, PortInc: 1, PimsConfigDir: "pims_config/", PimsDir: "pims/"})
+
+	err := rc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mu.RunPorts) != 0 {
+		t.Fatalf("RunContainer: Expected no ports to be published. Received Ports: %v", mu.RunPorts)
+	}
+
+	for _, call := range mu.Calls {
+		if call == "PortAvailable" {
+			t.Fatal("Expected no host port to be looked for")
+		}
+	}
+}
+
+//Test every run of a pim gets its own container name
+func TestRunUniqueContainerName(t *testing.T) {
+	var names []string
+
+	for i := 0; i < 2; i++ {
+		mu := utils.NewMockUtility()
+
+		mu.ImgExist = true
+
+		rc := NewRunCommand(mu, utils.Config{BaseDir: "~/.packageless/", StartPort: 3000, PimsConfigDir: "pims_config/", PimsDir: "pims/"})
+
+		err := rc.Init([]string{"python"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = rc.Run()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		names = append(names, mu.RunContainerName)
+	}
+
+	if !strings.HasPrefix(names[0], "packageless-python-latest-") || names[0] == names[1] {
+		t.Fatalf("RunContainer: Expected unique container names for python:latest. Received Names: %v", names)
+	}
 }
 
 func TestRunErrorAtGetwd(t *testing.T) {
@@ -723,10 +1060,6 @@ func TestRunErrorAtGetwd(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
-		"FileExists",
-		"GetHCLBody",
-		"ParseBody",
-		"ImageExists",
 		"Getwd",
 	}
 
@@ -734,3 +1067,43 @@ func TestRunErrorAtGetwd(t *testing.T) {
 		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
 	}
 }
+
+//Test the version of the pim to run is taken from the project manifest unless it is specified, a lockfile is not used
+func TestRunExplainManifest(t *testing.T) {
+	manifest := "project/" + utils.ManifestFile
+	lockfile := "project/" + utils.LockFile
+
+	cases := map[string]RunResolution{
+		"python":        {Pim: "python", Version: "3.9", Explanation: "the version is set by the project manifest " + manifest},
+		"python:latest": {Pim: "python", Version: "latest", Explanation: "the version was specified in the arguments"},
+		"node":          {Pim: "node", Version: "latest", Explanation: "the project manifest " + manifest + " does not list node, latest is the default"},
+	}
+
+	for name, expected := range cases {
+		mu := utils.NewMockUtility()
+
+		mu.FoundFiles = map[string]string{utils.ManifestFile: manifest, utils.LockFile: lockfile}
+		mu.FileContents = map[string]string{
+			manifest: "pim \"python\" {\n  version = \"3.9\"\n}\n",
+			lockfile: utils.LockfileHCL([]utils.LockEntry{{Pim: "python", Version: "latest", Image: "packageless/python"}, {Pim: "node", Version: "14", Image: "packageless/node:14"}, {Pim: "node", Version: "latest", Image: "packageless/node"}}),
+		}
+
+		rc := NewRunCommand(mu, mu.Conf)
+
+		err := rc.Init([]string{"--explain", name})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = rc.Run()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(expected, mu.Events[0].Data) {
+			t.Fatalf("Run: Expected the resolution of %s: %v | Received: %v", name, expected, mu.Events[0].Data)
+		}
+	}
+}
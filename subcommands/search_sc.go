@@ -0,0 +1,239 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Search Sub-Command Object
+type SearchCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//The term to search the repository index for
+	term string
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//SearchEntry - Details of a pim that matched the search term
+type SearchEntry struct {
+	Name        string   `json:"name"`
+	Versions    []string `json:"versions"`
+	Description string   `json:"description"`
+}
+
+//searchResult - A pim from the repository index that matched the search term
+type searchResult struct {
+	pim   utils.IndexPim
+	score int
+}
+
+//Instantiation method for a new SearchCommand
+func NewSearchCommand(tools utils.Tools, config utils.Config) *SearchCommand {
+	//Create a new SearchCommand and set the FlagSet
+	sc := &SearchCommand{
+		fs:     flag.NewFlagSet("search", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	return sc
+}
+
+//Name - Gets the name of the Sub-Command
+func (sc *SearchCommand) Name() string {
+	return sc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (sc *SearchCommand) Description() string {
+	return "Search the repository index for pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (sc *SearchCommand) Usage() string {
+	return "packageless search [TERM]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (sc *SearchCommand) Flags() *flag.FlagSet {
+	return sc.fs
+}
+
+//Init - Parses and Populates values of the Search subcommand
+func (sc *SearchCommand) Init(args []string) error {
+	err := sc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = sc.fs.Args()
+
+	if len(args) <= 0 {
+		return errors.New("No search term was found. You must include the term you wish to search for.")
+	}
+
+	sc.term = strings.Join(args, " ")
+
+	return nil
+}
+
+//Run - Runs the Search subcommand
+func (sc *SearchCommand) Run() error {
+	indexPath := sc.config.BaseDir + utils.IndexFile
+
+	//Fetch the repository index if it has not been cached by the update subcommand yet
+	if !sc.tools.FileExists(indexPath) {
+		err := sc.tools.MakeDir(sc.config.BaseDir)
+
+		if err != nil {
+			return err
+		}
+
+		err = sc.tools.FetchIndex(sc.config.RepositoryHost, sc.config.BaseDir)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	indexBody, err := sc.tools.GetHCLBody(indexPath)
+
+	if err != nil {
+		return err
+	}
+
+	//Parse the repository index
+	parseOut, err := sc.tools.ParseBody(indexBody, utils.RepositoryIndex{})
+
+	if err != nil {
+		return err
+	}
+
+	index := parseOut.(utils.RepositoryIndex)
+
+	entries := []SearchEntry{}
+
+	for _, result := range searchIndex(sc.term, index) {
+		entries = append(entries, SearchEntry{
+			Name:        result.pim.Name,
+			Versions:    result.pim.Versions,
+			Description: result.pim.Description,
+		})
+	}
+
+	sc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  searchText(sc.term, entries),
+		Data:     entries,
+		Markdown: renderSearch(sc.term, entries),
+	})
+
+	return nil
+}
+
+//searchText - Creates the plain text lines of the pims matching the search term
+func searchText(term string, entries []SearchEntry) string {
+	if len(entries) == 0 {
+		return fmt.Sprintf("No pims found matching '%s'", term)
+	}
+
+	var lines []string
+
+	for _, entry := range entries {
+		lines = append(lines, fmt.Sprintf("%s %s %s", entry.Name, strings.Join(entry.Versions, ","), entry.Description))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+//renderSearch - Creates a markdown table of the pims matching the search term
+func renderSearch(term string, entries []SearchEntry) string {
+	if len(entries) == 0 {
+		return fmt.Sprintf("*No pims found matching '%s'*", term)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# Pims matching '%s'\n", term))
+	sb.WriteString("| pim | versions | description |\n")
+	sb.WriteString("| --- | --- | --- |\n")
+
+	for _, entry := range entries {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", entry.Name, strings.Join(entry.Versions, ", "), entry.Description))
+	}
+
+	return sb.String()
+}
+
+//searchIndex - Finds the pims in the repository index matching the term, ordered from the best match to the worst
+func searchIndex(term string, index utils.RepositoryIndex) []searchResult {
+	var results []searchResult
+
+	for _, pim := range index.Pims {
+		score := matchScore(term, pim.Name, pim.Description)
+
+		if score > 0 {
+			results = append(results, searchResult{pim: pim, score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+
+		return results[i].pim.Name < results[j].pim.Name
+	})
+
+	return results
+}
+
+//matchScore - Scores how well a pim matches the search term. A score of 0 means the pim does not match.
+//Matches on the name score higher than matches on the description, and exact and substring
+//matches score higher than fuzzy matches where the characters of the term only appear in order.
+func matchScore(term string, name string, description string) int {
+	term = strings.ToLower(strings.TrimSpace(term))
+	name = strings.ToLower(name)
+	description = strings.ToLower(description)
+
+	switch {
+	case term == "":
+		return 0
+	case name == term:
+		return 100
+	case strings.HasPrefix(name, term):
+		return 80
+	case strings.Contains(name, term):
+		return 60
+	case strings.Contains(description, term):
+		return 40
+	case isSubsequence(term, name):
+		return 20
+	}
+
+	return 0
+}
+
+//isSubsequence - Checks if all the characters of the term appear in the value in the same order
+func isSubsequence(term string, value string) bool {
+	termRunes := []rune(term)
+	i := 0
+
+	for _, r := range value {
+		if i < len(termRunes) && r == termRunes[i] {
+			i++
+		}
+	}
+
+	return i == len(termRunes)
+}
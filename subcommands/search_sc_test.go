@@ -0,0 +1,202 @@
+package subcommands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Create a repository index to search in the tests
+func testIndex() utils.RepositoryIndex {
+	return utils.RepositoryIndex{
+		Pims: []utils.IndexPim{
+			{
+				Name:        "node",
+				Description: "JavaScript runtime built on Chrome's V8 engine",
+				Versions:    []string{"latest", "16"},
+			},
+			{
+				Name:        "python",
+				Description: "An interpreted programming language",
+				Versions:    []string{"latest", "3.9"},
+			},
+			{
+				Name:        "pypy",
+				Description: "A fast python implementation",
+				Versions:    []string{"latest"},
+			},
+		},
+	}
+}
+
+//Test to make sure the search subcommand has the proper name upon creation
+func TestSearchName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	sc := NewSearchCommand(mu, mu.Conf)
+
+	if sc.Name() != "search" {
+		t.Fatal("The search subcommand's name should be: search | Subcommand Name: " + sc.Name())
+	}
+}
+
+//Test to make sure the search subcommand initializes correctly
+func TestSearchInit(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	sc := NewSearchCommand(mu, mu.Conf)
+
+	err := sc.Init([]string{})
+
+	if err == nil {
+		t.Fatal("Expected an error when no search term is passed but did not receive one")
+	}
+
+	err = sc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if sc.term != "python" {
+		t.Fatal("Search term should have been initialized as: python but is: " + sc.term)
+	}
+}
+
+//Tests the flow of the search subcommand with a cached repository index
+func TestSearchFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.Index = testIndex()
+
+	sc := NewSearchCommand(mu, mu.Conf)
+
+	err := sc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"FileExists",
+		"GetHCLBody",
+		"ParseBody",
+		"Emit",
+	}
+
+	//If the call stack doesn't match the test fails
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	expected := []SearchEntry{
+		{Name: "python", Versions: []string{"latest", "3.9"}, Description: "An interpreted programming language"},
+		{Name: "pypy", Versions: []string{"latest"}, Description: "A fast python implementation"},
+	}
+
+	if !reflect.DeepEqual(expected, mu.Events[0].Data) {
+		t.Fatalf("Search: Expected entries: %+v | Received: %+v", expected, mu.Events[0].Data)
+	}
+
+	hclFiles := []string{mu.Conf.BaseDir + utils.IndexFile}
+
+	if !reflect.DeepEqual(hclFiles, mu.HCLFiles) {
+		t.Fatalf("HCL files read does not match the expected files. HCL Files: %v | Expected HCL Files: %v", mu.HCLFiles, hclFiles)
+	}
+}
+
+//Tests the search subcommand fetches the repository index when it is not cached
+func TestSearchFetchesIndex(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.Index = testIndex()
+	mu.PimConfigShouldExist = false
+
+	sc := NewSearchCommand(mu, mu.Conf)
+
+	err := sc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"FileExists",
+		"MakeDir",
+		"FetchIndex",
+		"GetHCLBody",
+		"ParseBody",
+		"Emit",
+	}
+
+	//If the call stack doesn't match the test fails
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+}
+
+//Test if an error happens at the FetchIndex function
+func TestSearchErrorAtFetchIndex(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.PimConfigShouldExist = false
+	mu.ErrorAt = "FetchIndex"
+
+	sc := NewSearchCommand(mu, mu.Conf)
+
+	err := sc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if err == nil {
+		t.Fatal("Expected the following error: " + mu.ErrorMsg + " but did not receive an error")
+	}
+
+	if err.Error() != mu.ErrorMsg {
+		t.Fatal("Expected the following error: " + mu.ErrorMsg + "| Received: " + err.Error())
+	}
+}
+
+//Test the searchIndex function orders the results from the best match to the worst
+func TestSearchIndex(t *testing.T) {
+	cases := []struct {
+		term     string
+		expected []string
+	}{
+		{"python", []string{"python", "pypy"}},
+		{"py", []string{"pypy", "python"}},
+		{"runtime", []string{"node"}},
+		{"pthn", []string{"python"}},
+		{"NODE", []string{"node"}},
+		{"ruby", nil},
+	}
+
+	for _, c := range cases {
+		var names []string
+
+		for _, result := range searchIndex(c.term, testIndex()) {
+			names = append(names, result.pim.Name)
+		}
+
+		if !reflect.DeepEqual(c.expected, names) {
+			t.Fatalf("searchIndex(%s): Expected: %v | Received: %v", c.term, c.expected, names)
+		}
+	}
+}
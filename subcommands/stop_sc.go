@@ -0,0 +1,150 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Stop Sub-Command Object
+type StopCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Name of the pim or container to stop
+	name string
+
+	//Seconds to wait for the container to stop before killing it
+	timeout int
+
+	tools utils.Tools
+}
+
+//Instantiation method for a new StopCommand
+func NewStopCommand(tools utils.Tools) *StopCommand {
+	//Create a new StopCommand and set the FlagSet
+	sc := &StopCommand{
+		fs:    flag.NewFlagSet("stop", flag.ContinueOnError),
+		tools: tools,
+	}
+
+	sc.fs.IntVar(&sc.timeout, "timeout", 10, "`SECONDS` to wait for the pim to stop before killing it")
+
+	return sc
+}
+
+//Name - Gets the name of the Sub-Command
+func (sc *StopCommand) Name() string {
+	return sc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (sc *StopCommand) Description() string {
+	return "Stop running pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (sc *StopCommand) Usage() string {
+	return "packageless stop [OPTIONS] [pim[:version]|container...]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (sc *StopCommand) Flags() *flag.FlagSet {
+	return sc.fs
+}
+
+//Batch - The stop subcommand is ran once for every pim passed as an argument
+func (sc *StopCommand) Batch() bool {
+	return true
+}
+
+//Init - Parses and Populates values of the Stop subcommand
+func (sc *StopCommand) Init(args []string) error {
+	err := sc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = sc.fs.Args()
+
+	if len(args) <= 0 {
+		return errors.New("No pim or container name was found. You must include the name of the pim or container you wish to stop.")
+	}
+
+	if sc.timeout < 0 {
+		return errors.New("The timeout can not be negative")
+	}
+
+	sc.name = args[0]
+
+	return nil
+}
+
+//Run - Runs the Stop subcommand
+func (sc *StopCommand) Run() error {
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	containers, err := sc.tools.ListPimContainers(cli)
+
+	if err != nil {
+		return err
+	}
+
+	var matches []types.Container
+
+	for _, ctr := range containers {
+		if matchesContainer(ctr, sc.name) {
+			matches = append(matches, ctr)
+		}
+	}
+
+	if len(matches) == 0 {
+		return errors.New("No running pim or container matches " + sc.name + ". Run packageless ps to list the running pims")
+	}
+
+	sc.tools.Emit(utils.Event{Type: utils.EventStarted, Pim: sc.name, Message: "Stopping"})
+
+	for _, ctr := range matches {
+		name := containerName(ctr)
+
+		stepStarted(sc.tools, sc.name, "stop_container", "Stopping container "+name)
+
+		err = sc.tools.StopContainer(ctr.ID, time.Duration(sc.timeout)*time.Second, cli)
+
+		if err != nil {
+			return errors.New("Could not stop container " + name + ": " + err.Error())
+		}
+
+		stepFinished(sc.tools, sc.name, "stop_container")
+	}
+
+	sc.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: sc.name, Message: "successfully stopped"})
+
+	return nil
+}
+
+//matchesContainer - Checks if the container is the one with the name or ID, or runs the pim with the pim[:version] name.
+//Without a version every running version of the pim matches.
+func matchesContainer(ctr types.Container, name string) bool {
+	if containerName(ctr) == name || (len(name) >= 4 && strings.HasPrefix(ctr.ID, name)) {
+		return true
+	}
+
+	pimName := ctr.Labels[utils.LabelPim]
+
+	if !strings.Contains(name, ":") {
+		return pimName == name
+	}
+
+	return pimName+":"+ctr.Labels[utils.LabelVersion] == name
+}
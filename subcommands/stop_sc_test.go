@@ -0,0 +1,156 @@
+package subcommands
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Create the containers of two running versions of python and one of node
+func stopTestContainers() []types.Container {
+	return []types.Container{
+		{ID: "aaaa1111", Names: []string{"/python"}, Labels: map[string]string{utils.LabelPim: "python", utils.LabelVersion: "latest"}},
+		{ID: "bbbb2222", Names: []string{"/python-3.9"}, Labels: map[string]string{utils.LabelPim: "python", utils.LabelVersion: "3.9"}},
+		{ID: "cccc3333", Names: []string{"/node"}, Labels: map[string]string{utils.LabelPim: "node", utils.LabelVersion: "latest"}},
+	}
+}
+
+func TestStopName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	sc := NewStopCommand(mu)
+
+	if sc.Name() != "stop" {
+		t.Fatalf("The stop subcommand's name should be: 'stop' but was '%s'", sc.Name())
+	}
+}
+
+func TestStopInitErrors(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	sc := NewStopCommand(mu)
+
+	if err := sc.Init([]string{}); err == nil {
+		t.Fatal("Stop: Expected an error when no pim or container is passed")
+	}
+
+	if err := sc.Init([]string{"--timeout=-1", "python"}); err == nil {
+		t.Fatal("Stop: Expected an error for a negative timeout")
+	}
+}
+
+//Test the containers are matched by pim, pim:version, container name and ID
+func TestStopMatches(t *testing.T) {
+	tests := map[string][]string{
+		"python":     {"aaaa1111", "bbbb2222"},
+		"python:3.9": {"bbbb2222"},
+		"node":       {"cccc3333"},
+		"python-3.9": {"bbbb2222"},
+		"cccc33":     {"cccc3333"},
+	}
+
+	for name, expected := range tests {
+		mu := utils.NewMockUtility()
+		mu.Containers = stopTestContainers()
+
+		sc := NewStopCommand(mu)
+
+		err := sc.Init([]string{"--timeout=5", name})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = sc.Run()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(expected, mu.StoppedContainers) {
+			t.Fatalf("Stop %s: Expected stopped containers: %v | Received: %v", name, expected, mu.StoppedContainers)
+		}
+
+		if mu.StopTimeout != 5*time.Second {
+			t.Fatalf("Stop %s: Expected the timeout to be 5s | Received: %s", name, mu.StopTimeout)
+		}
+	}
+}
+
+//Test the timeout can be passed as a separate argument when stopping several pims
+func TestStopTimeoutSeparateArgument(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.Containers = stopTestContainers()
+
+	err := SubCommand([]string{"stop", "--timeout", "5", "node", "python:3.9"}, []Runner{NewStopCommand(mu)}, mu)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"cccc3333", "bbbb2222"}
+
+	if !reflect.DeepEqual(expected, mu.StoppedContainers) {
+		t.Fatalf("Stop: Expected stopped containers: %v | Received: %v", expected, mu.StoppedContainers)
+	}
+
+	if mu.StopTimeout != 5*time.Second {
+		t.Fatalf("Stop: Expected the timeout to be 5s | Received: %s", mu.StopTimeout)
+	}
+}
+
+//Test an error is returned when nothing matches
+func TestStopNoMatch(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.Containers = stopTestContainers()
+
+	sc := NewStopCommand(mu)
+
+	err := sc.Init([]string{"ruby"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if err == nil {
+		t.Fatal("Stop: Expected an error when no running container matches")
+	}
+
+	callStack := []string{"ListPimContainers"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+}
+
+//Test the stop subcommand getting an error when stopping a container
+func TestStopErrorAtStopContainer(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.Containers = stopTestContainers()
+	mu.ErrorAt = "StopContainer"
+
+	sc := NewStopCommand(mu)
+
+	err := sc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if err == nil {
+		t.Fatal("Stop: Expected an error when the container could not be stopped")
+	}
+
+	callStack := []string{"ListPimContainers", "Emit", "Emit", "StopContainer"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+}
@@ -2,7 +2,12 @@ package subcommands
 
 import (
 	"errors"
+	"flag"
 	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/everettraven/packageless/utils"
 )
 
 //Runner - Interface to enable easy interactions with the different subcommand objects
@@ -10,46 +15,591 @@ type Runner interface {
 	Init(args []string) error
 	Run() error
 	Name() string
+	Description() string
+	Usage() string
+	Flags() *flag.FlagSet
+}
+
+//Batcher - Interface for subcommands that should be ran once for every pim passed as an argument
+type Batcher interface {
+	Batch() bool
+}
+
+//Hider - Interface for subcommands that are only used internally and should not be shown to users
+type Hider interface {
+	Hidden() bool
+}
+
+//PluginFinder - Interface for subcommands that find the external plugins providing the subcommands that are not registered
+type PluginFinder interface {
+	FindPlugin(name string) (Runner, bool, error)
+	FindPlugins() ([]Runner, error)
+}
+
+//findPlugins - Gets the subcommands provided by plugins, plugins that have the name of a registered subcommand are left out
+func findPlugins(scmds []Runner) []Runner {
+	var plugins []Runner
+
+	for _, cmd := range scmds {
+		finder, ok := cmd.(PluginFinder)
+
+		if !ok {
+			continue
+		}
+
+		//Plugins are only listed, a problem finding them should not prevent showing the usage
+		found, _ := finder.FindPlugins()
+
+		for _, plugin := range found {
+			if findRunner(scmds, plugin.Name()) == nil {
+				plugins = append(plugins, plugin)
+			}
+		}
+	}
+
+	return plugins
+}
+
+//findRunner - Gets the registered subcommand with the name, nil if there is none
+func findRunner(scmds []Runner, name string) Runner {
+	for _, cmd := range scmds {
+		if cmd.Name() == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+//visibleRunners - Gets the subcommands that should be shown to users
+func visibleRunners(scmds []Runner) []Runner {
+	var visible []Runner
+
+	for _, cmd := range scmds {
+		if hider, ok := cmd.(Hider); ok && hider.Hidden() {
+			continue
+		}
+
+		visible = append(visible, cmd)
+	}
+
+	return visible
+}
+
+//SkipError - Error returned by a subcommand when a pim did not need to be processed
+type SkipError struct {
+	Reason string
+}
+
+//Error - Returns the reason the pim was skipped
+func (se *SkipError) Error() string {
+	return se.Reason
+}
+
+//Skip - Creates a new SkipError with the given reason
+func Skip(reason string) error {
+	return &SkipError{Reason: reason}
+}
+
+//ExitError - Error returned by a subcommand that ran a program which exited with a non zero exit code.
+//packageless exits with the same exit code, the program is expected to have reported the error itself.
+type ExitError struct {
+	Code int
+}
+
+//Error - Returns the exit code of the program
+func (ee *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", ee.Code)
+}
+
+//BatchResult - The outcome of running a batch subcommand for a single pim
+type BatchResult struct {
+	Pim    string `json:"pim"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
 }
 
+//Statuses that a pim can have after a batch subcommand has ran
+const (
+	StatusSucceeded = "succeeded"
+	StatusSkipped   = "skipped"
+	StatusFailed    = "failed"
+)
+
 //SubCommand - Helper function that handles setting up and running subcommands
-func SubCommand(args []string, scmds []Runner) error {
-	if len(args) < 1 {
-		return errors.New("A subcommand must be passed")
+func SubCommand(args []string, scmds []Runner, tools utils.Tools) error {
+	//Show the usage if no subcommand is passed or help is requested
+	if len(args) < 1 || isHelpFlag(args[0]) {
+		tools.RenderInfoMarkdown(renderUsage(scmds))
+		return nil
 	}
 
 	subcommand := args[0]
 
 	args = args[1:]
 
+	if cmd := findRunner(scmds, subcommand); cmd != nil {
+		return runCommand(cmd, args, tools)
+	}
+
+	//Subcommands that are not registered can be provided by plugins
 	for _, cmd := range scmds {
-		if cmd.Name() == subcommand {
-			//Subcommands that take multiple pims as arguments
-			if subcommand == "install" || subcommand == "upgrade" || subcommand == "uninstall" {
-				//Execute subcommand for each pim in arguments
-				for _, pim := range args {
-					p := []string{pim}
-					err := cmd.Init(p)
-
-					if err != nil {
-						return err
-					}
-
-					return cmd.Run()
-				}
-			} else {
-				err := cmd.Init(args)
+		if finder, ok := cmd.(PluginFinder); ok {
+			plugin, found, err := finder.FindPlugin(subcommand)
 
-				if err != nil {
-					return err
-				}
+			if err != nil {
+				return err
+			}
 
-				return cmd.Run()
+			if found {
+				return runCommand(plugin, args, tools)
 			}
+		}
+	}
+
+	return utils.WithErrorCode(utils.ErrorCodeUnknownCommand, fmt.Errorf("Unknown subcommand %s", subcommand))
+}
+
+//runCommand - Parses the arguments of the subcommand and runs it, once for every pim for batch subcommands
+func runCommand(cmd Runner, args []string, tools utils.Tools) error {
+	//Flag parsing errors are returned instead of being printed by the flag package
+	cmd.Flags().SetOutput(ioutil.Discard)
 
+	//Subcommands that take multiple pims as arguments
+	if batcher, ok := cmd.(Batcher); ok && batcher.Batch() {
+		flags, pims, failFast, err := splitBatchArgs(cmd, args)
+
+		if errors.Is(err, flag.ErrHelp) {
+			tools.RenderInfoMarkdown(renderCommandHelp(cmd))
 			return nil
 		}
+
+		if err != nil {
+			return utils.WithErrorCode(utils.ErrorCodeInvalidArguments, err)
+		}
+
+		//If no pims are passed let the subcommand decide what to do with no arguments
+		if len(pims) > 0 {
+			return runBatch(cmd, flags, pims, failFast, tools)
+		}
+
+		args = flags
+	}
+
+	err := cmd.Init(args)
+
+	if errors.Is(err, flag.ErrHelp) {
+		tools.RenderInfoMarkdown(renderCommandHelp(cmd))
+		return nil
+	}
+
+	if err != nil {
+		return utils.WithErrorCode(utils.ErrorCodeInvalidArguments, err)
+	}
+
+	return cmd.Run()
+}
+
+//isHelpFlag - Checks if the argument is a flag requesting help
+func isHelpFlag(arg string) bool {
+	return arg == "-h" || arg == "--help" || arg == "-help"
+}
+
+//HelpRequested - Checks if the arguments ask for the usage or the help of a subcommand, parsing them the same way as
+//when the subcommand is run so that e.g. a -h passed to a pim is not mistaken for it. The flags of the subcommands are not changed.
+func HelpRequested(args []string, scmds []Runner) bool {
+	if len(args) < 1 || isHelpFlag(args[0]) {
+		return true
+	}
+
+	cmd := findRunner(scmds, args[0])
+
+	if cmd == nil {
+		return false
+	}
+
+	fs := flag.NewFlagSet(cmd.Name(), flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	cmd.Flags().VisitAll(func(f *flag.Flag) {
+		fs.Var(&helpCheckValue{f.Value}, f.Name, f.Usage)
+	})
+
+	batcher, ok := cmd.(Batcher)
+
+	if !ok || !batcher.Batch() {
+		return errors.Is(fs.Parse(args[1:]), flag.ErrHelp)
+	}
+
+	fs.Bool("fail-fast", false, "stop at the first pim that fails")
+
+	//The flags of batch subcommands can come after the pims, see splitBatchArgs
+	rest := args[1:]
+
+	for len(rest) > 0 {
+		err := fs.Parse(rest)
+
+		if err != nil {
+			return errors.Is(err, flag.ErrHelp)
+		}
+
+		rest = fs.Args()
+
+		if len(rest) > 0 {
+			rest = rest[1:]
+		}
+	}
+
+	return false
+}
+
+//helpCheckValue - Flag value used while checking for the help flag, so that the value of the flag is left as is
+type helpCheckValue struct {
+	value flag.Value
+}
+
+//String - Gets the value of the flag
+func (hv *helpCheckValue) String() string {
+	if hv.value == nil {
+		return ""
+	}
+
+	return hv.value.String()
+}
+
+//Set - Ignores the value that the flag is set to
+func (hv *helpCheckValue) Set(string) error {
+	return nil
+}
+
+//IsBoolFlag - Checks if the flag takes a value, so that the argument after it is parsed the same way
+func (hv *helpCheckValue) IsBoolFlag() bool {
+	boolFlag, ok := hv.value.(interface{ IsBoolFlag() bool })
+
+	return ok && boolFlag.IsBoolFlag()
+}
+
+//splitBatchArgs - Separates the flags from the pims in the arguments of a batch subcommand.
+//The flags are parsed with the flags of the subcommand so that the flags taking a value consume it, and can come before or after the pims.
+//The --fail-fast flag is consumed here as it applies to the batch as a whole.
+func splitBatchArgs(cmd Runner, args []string) ([]string, []string, bool, error) {
+	var pims []string
+	failFast := false
+
+	fs := flag.NewFlagSet(cmd.Name(), flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+
+	cmd.Flags().VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+
+	fs.BoolVar(&failFast, "fail-fast", false, "stop at the first pim that fails")
+
+	rest := args
+
+	for {
+		err := fs.Parse(rest)
+
+		if err != nil {
+			return nil, nil, false, err
+		}
+
+		rest = fs.Args()
+
+		if len(rest) == 0 {
+			break
+		}
+
+		//Parsing stops at the first pim, the flags after it are parsed next
+		pims = append(pims, rest[0])
+		rest = rest[1:]
+	}
+
+	//The flags are passed to the subcommand for every pim with the values they were parsed to
+	var flags []string
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "fail-fast" {
+			flags = append(flags, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+
+	return flags, pims, failFast, nil
+}
+
+//runBatch - Runs the subcommand once for each pim and renders a summary of the results
+func runBatch(cmd Runner, flags []string, pims []string, failFast bool, tools utils.Tools) error {
+	var results []BatchResult
+	failed := 0
+
+	for _, pim := range pims {
+		result := BatchResult{Pim: pim, Status: StatusSucceeded}
+
+		err := cmd.Init(append(append([]string{}, flags...), pim))
+
+		if err == nil {
+			err = cmd.Run()
+		}
+
+		var skip *SkipError
+
+		if errors.As(err, &skip) {
+			result.Status = StatusSkipped
+			result.Reason = skip.Reason
+		} else if err != nil {
+			result.Status = StatusFailed
+			result.Reason = err.Error()
+			failed++
+		}
+
+		results = append(results, result)
+
+		if failed > 0 && failFast {
+			break
+		}
+	}
+
+	//Any pims that were not processed because of --fail-fast are reported as skipped
+	for _, pim := range pims[len(results):] {
+		results = append(results, BatchResult{Pim: pim, Status: StatusSkipped, Reason: "not processed because of --fail-fast"})
+	}
+
+	summary := utils.Event{
+		Type:     utils.EventResult,
+		Message:  batchSummaryText(results),
+		Data:     results,
+		Markdown: renderBatchSummary(cmd.Name(), results),
+	}
+
+	if failed > 0 {
+		summary.Code = utils.ErrorCodeBatchFailed
+		tools.Emit(summary)
+		return utils.WithErrorCode(utils.ErrorCodeBatchFailed, fmt.Errorf("%s failed for %d of %d pims", cmd.Name(), failed, len(pims)))
+	}
+
+	tools.Emit(summary)
+
+	return nil
+}
+
+//renderBatchSummary - Creates a markdown table summarizing the results of a batch subcommand
+func renderBatchSummary(name string, results []BatchResult) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s summary\n", name))
+	sb.WriteString("| pim | result | reason |\n")
+	sb.WriteString("| --- | --- | --- |\n")
+
+	for _, result := range results {
+		reason := strings.ReplaceAll(result.Reason, "|", "\\|")
+		reason = strings.ReplaceAll(reason, "\n", " ")
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", result.Pim, result.Status, reason))
+	}
+
+	return sb.String()
+}
+
+//batchSummaryText - Creates the plain text summarizing the results of a batch subcommand, one line per pim
+func batchSummaryText(results []BatchResult) string {
+	var lines []string
+
+	for _, result := range results {
+		line := result.Pim + ": " + result.Status
+
+		if result.Reason != "" {
+			line += " - " + strings.ReplaceAll(result.Reason, "\n", " ")
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+//stepStarted - Emits the event for the start of a step of the work on a pim
+func stepStarted(tools utils.Tools, pim string, step string, message string) {
+	tools.Emit(utils.Event{Type: utils.EventStepStarted, Pim: pim, Step: step, Message: message})
+}
+
+//stepFinished - Emits the event for a step of the work on a pim that finished successfully
+func stepFinished(tools utils.Tools, pim string, step string) {
+	tools.Emit(utils.Event{Type: utils.EventStepFinished, Pim: pim, Step: step})
+}
+
+//pullImage - Pulls the image of a version of a pim. When a digest is given the image is pulled by digest
+//and tagged as the image of the version, so that it is run like any other pull of the image
+func pullImage(tools utils.Tools, image string, digest string, cli utils.Client) error {
+	if digest == "" {
+		return tools.PullImage(image, cli)
+	}
+
+	ref := imageRepository(image) + "@" + digest
+
+	err := tools.PullImage(ref, cli)
+
+	if err != nil {
+		return err
+	}
+
+	return tools.TagImage(ref, image, cli)
+}
+
+//splitPimName - Splits a pim argument in the format pim[:version] into the pim name and version.
+//The version defaults to latest when it is not specified
+func splitPimName(name string) (string, string) {
+	if strings.Contains(name, ":") {
+		split := strings.Split(name, ":")
+		return split[0], split[1]
+	}
+
+	return name, "latest"
+}
+
+//findManifest - Looks for the project manifest in the working directory and its parents and parses it.
+//The path is empty if there is no project manifest
+func findManifest(tools utils.Tools) ([]utils.ManifestPim, string, error) {
+	wd, err := tools.Getwd()
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	path := tools.FindFileUp(wd, utils.ManifestFile)
+
+	if path == "" {
+		return nil, "", nil
+	}
+
+	content, err := tools.ReadFile(path)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	pims, diags := utils.LoadManifestHCL([]byte(content), path)
+
+	if diags.HasErrors() {
+		return nil, "", utils.WithErrorCode(utils.ErrorCodeInvalidConfig, errors.New("Encountered an error while parsing the project manifest "+path+": "+diags.Error()))
+	}
+
+	return pims, path, nil
+}
+
+//resolvePimName - Splits a pim argument in the format pim[:version] into the pim name and version.
+//When the version is not specified it is taken from the project manifest, defaulting to latest.
+//The explanation describes where the version came from
+func resolvePimName(tools utils.Tools, name string) (string, string, string, error) {
+	if strings.Contains(name, ":") {
+		pimName, pimVersion := splitPimName(name)
+		return pimName, pimVersion, "the version was specified in the arguments", nil
+	}
+
+	pims, path, err := findManifest(tools)
+
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if path == "" {
+		return name, "latest", "no project manifest (" + utils.ManifestFile + ") was found in the working directory or its parents, latest is the default", nil
+	}
+
+	for _, mp := range pims {
+		if mp.Name == name {
+			_, pimVersion := splitPimName(mp.Ref())
+			return name, pimVersion, "the version is set by the project manifest " + path, nil
+		}
+	}
+
+	return name, "latest", "the project manifest " + path + " does not list " + name + ", latest is the default", nil
+}
+
+//fetchIndexChecksums - Caches the latest repository index and gets the checksums of the pim configurations from it.
+//Not every repository provides an index so failing to fetch it is not an error, nothing is verified then
+//as the cached index may list older checksums than the pim configurations that are fetched
+func fetchIndexChecksums(tools utils.Tools, config utils.Config, pimRef string) (map[string]string, error) {
+	err := tools.MakeDir(config.BaseDir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tools.Emit(utils.Event{Type: utils.EventStarted, Pim: pimRef, Step: "update_index", Message: "Updating repository index"})
+
+	err = tools.FetchIndex(config.RepositoryHost, config.BaseDir)
+
+	if err != nil {
+		tools.Emit(utils.Event{Type: utils.EventWarning, Pim: pimRef, Step: "update_index", Message: "Could not update the repository index: " + err.Error()})
+		return make(map[string]string), nil
+	}
+
+	tools.Emit(utils.Event{Type: utils.EventFinished, Pim: pimRef, Step: "update_index"})
+
+	return indexChecksums(tools, config)
+}
+
+//indexChecksums - Gets the checksums of the pim configurations by pim name from the cached repository index.
+//Pims are missing if there is no cached index or it does not list a checksum for them
+func indexChecksums(tools utils.Tools, config utils.Config) (map[string]string, error) {
+	checksums := make(map[string]string)
+	indexPath := config.BaseDir + utils.IndexFile
+
+	if !tools.FileExists(indexPath) {
+		return checksums, nil
+	}
+
+	indexBody, err := tools.GetHCLBody(indexPath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	//Parse the repository index
+	parseOut, err := tools.ParseBody(indexBody, utils.RepositoryIndex{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pim := range parseOut.(utils.RepositoryIndex).Pims {
+		if pim.Checksum != "" {
+			checksums[pim.Name] = pim.Checksum
+		}
+	}
+
+	return checksums, nil
+}
+
+//verifyPimConfig - Checks that the downloaded pim configuration matches the checksum from the repository index
+func verifyPimConfig(tools utils.Tools, pimName string, pimPath string, checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	content, err := tools.ReadFile(pimPath)
+
+	if err != nil {
+		return err
+	}
+
+	if configChecksum(content) != checksum {
+		return utils.WithErrorCode(utils.ErrorCodeInvalidConfig, errors.New("The downloaded pim configuration of "+pimName+" does not match the checksum "+checksum+" listed in the repository index"))
+	}
+
+	return nil
+}
+
+//findPimVersion - Looks for the pim with the specified version in a parsed pim configuration
+func findPimVersion(pims utils.PimHCLUtil, pimName string, pimVersion string) (utils.PackageImage, utils.Version, bool) {
+	for _, pim := range pims.Pims {
+		if pim.Name == pimName {
+			for _, ver := range pim.Versions {
+				if ver.Version == pimVersion {
+					return pim, ver, true
+				}
+			}
+		}
 	}
 
-	return fmt.Errorf("Unknown subcommand %s", subcommand)
+	return utils.PackageImage{}, utils.Version{}, false
 }
@@ -2,8 +2,11 @@ package subcommands
 
 import (
 	"errors"
+	"flag"
 	"reflect"
 	"testing"
+
+	"github.com/everettraven/packageless/utils"
 )
 
 //Create a mock subcommand struct
@@ -22,11 +25,38 @@ type MockSC struct {
 
 	//Create a variable to set the error message that should be returned
 	ErrorMsg string
+
+	//Create a variable to set if the command is a batch command
+	Batched bool
+
+	//Create a variable to set the pim that should cause an error when ran
+	ErrorPim string
+
+	//Create a variable to set the pim that should be skipped when ran
+	SkipPim string
+
+	//Create a variable to keep track of the pims that were ran
+	RanPims []string
+
+	//Create a variable to hold the FlagSet of the command
+	fs *flag.FlagSet
+
+	//Create a variable to hold the value of a boolean flag
+	Flag bool
+
+	//Create a variable to hold the value of a flag that takes a value
+	Value string
 }
 
 //Function to create a new MockSC
 func NewMockSC() *MockSC {
-	msc := &MockSC{}
+	msc := &MockSC{
+		fs: flag.NewFlagSet("mock", flag.ContinueOnError),
+	}
+
+	msc.fs.BoolVar(&msc.Flag, "flag", false, "a mock flag")
+	msc.fs.StringVar(&msc.Value, "value", "", "a mock flag that takes a value")
+
 	return msc
 }
 
@@ -36,16 +66,42 @@ func (msc *MockSC) Init(args []string) error {
 		return errors.New(msc.ErrorMsg)
 	}
 
-	msc.Args = args
+	err := msc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	msc.Args = msc.fs.Args()
 
 	return nil
 }
 
+//MockSC Description function
+func (msc *MockSC) Description() string {
+	return "A mock subcommand"
+}
+
+//MockSC Usage function
+func (msc *MockSC) Usage() string {
+	return "packageless " + msc.CmdName
+}
+
+//MockSC Flags function
+func (msc *MockSC) Flags() *flag.FlagSet {
+	return msc.fs
+}
+
 //MockSC Name function
 func (msc *MockSC) Name() string {
 	return msc.CmdName
 }
 
+//MockSC Batch function
+func (msc *MockSC) Batch() bool {
+	return msc.Batched
+}
+
 //MockSC Run function
 func (msc *MockSC) Run() error {
 	if msc.ErrorAt == "Run" {
@@ -54,6 +110,19 @@ func (msc *MockSC) Run() error {
 
 	msc.Ran = true
 
+	if len(msc.Args) > 0 {
+		pim := msc.Args[len(msc.Args)-1]
+		msc.RanPims = append(msc.RanPims, pim)
+
+		if pim == msc.ErrorPim {
+			return errors.New(msc.ErrorMsg)
+		}
+
+		if pim == msc.SkipPim {
+			return Skip("skipping " + pim)
+		}
+	}
+
 	return nil
 }
 
@@ -74,7 +143,7 @@ func TestSubCommand(t *testing.T) {
 	}
 
 	//Run the SubCommand function
-	err := SubCommand(args, scmds)
+	err := SubCommand(args, scmds, utils.NewMockUtility())
 
 	//Shouldn't have an error
 	if err != nil {
@@ -115,7 +184,7 @@ func TestSubCommandErrorAtInit(t *testing.T) {
 	}
 
 	//Run the SubCommand function
-	err := SubCommand(args, scmds)
+	err := SubCommand(args, scmds, utils.NewMockUtility())
 
 	//Should have an error
 	if err == nil {
@@ -152,7 +221,7 @@ func TestSubCommandErrorAtRun(t *testing.T) {
 	}
 
 	//Run the SubCommand function
-	err := SubCommand(args, scmds)
+	err := SubCommand(args, scmds, utils.NewMockUtility())
 
 	//Should have an error
 	if err == nil {
@@ -187,7 +256,7 @@ func TestSubCommandUnknownSC(t *testing.T) {
 	}
 
 	//Run the SubCommand function
-	err := SubCommand(args, scmds)
+	err := SubCommand(args, scmds, utils.NewMockUtility())
 
 	//Should have an error
 	if err == nil {
@@ -220,7 +289,7 @@ func TestSubCommandInstallMultiple(t *testing.T) {
 	}
 
 	//Run the SubCommand function
-	err := SubCommand(args, scmds)
+	err := SubCommand(args, scmds, utils.NewMockUtility())
 
 	//There shouldn't be an error
 	if err != nil {
@@ -247,7 +316,7 @@ func TestSubCommandUninstallMultiple(t *testing.T) {
 	}
 
 	//Run the SubCommand function
-	err := SubCommand(args, scmds)
+	err := SubCommand(args, scmds, utils.NewMockUtility())
 
 	//There shouldn't be an error
 	if err != nil {
@@ -273,7 +342,7 @@ func TestSubCommandUpgradeMultiple(t *testing.T) {
 	}
 
 	//Run the SubCommand function
-	err := SubCommand(args, scmds)
+	err := SubCommand(args, scmds, utils.NewMockUtility())
 
 	//There shouldn't be an error
 	if err != nil {
@@ -285,9 +354,6 @@ func TestLengthArgs(t *testing.T) {
 	//Create a mock subcommand
 	msc := NewMockSC()
 
-	//Set the error message
-	msc.ErrorMsg = "Test error is being throw properly if the length of the args variable is < 1"
-
 	//Create an argument array
 	args := []string{}
 
@@ -296,12 +362,345 @@ func TestLengthArgs(t *testing.T) {
 		msc,
 	}
 
+	mu := utils.NewMockUtility()
+
+	//Run the SubCommand function
+	err := SubCommand(args, scmds, mu)
+
+	//Should not have an error as the usage is shown instead
+	if err != nil {
+		t.Fatalf("SubCommand: Unexpected error: %s", err)
+	}
+
+	callStack := []string{"RenderInfoMarkdown"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	//The command should not have been ran
+	if msc.Ran {
+		t.Fatalf("SubCommand: Expected the subcommand to not have been ran but it was")
+	}
+}
+
+//Test the SubCommand function parses the flags of the subcommand
+func TestSubCommandFlags(t *testing.T) {
+	//Create a mock subcommand
+	msc := NewMockSC()
+
+	//Set MockSC Values
+	msc.CmdName = "test"
+
+	//Create an argument array
+	args := []string{msc.CmdName, "--flag", "that"}
+
+	//Run the SubCommand function
+	err := SubCommand(args, []Runner{msc}, utils.NewMockUtility())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !msc.Flag {
+		t.Fatal("SubCommand: Expected the flag to have been set but it was not")
+	}
+
+	if !reflect.DeepEqual([]string{"that"}, msc.Args) {
+		t.Fatalf("SubCommand: Expected Init Args: %v | Received: %v", []string{"that"}, msc.Args)
+	}
+}
+
+//Test the SubCommand function returns an error for an unknown flag
+func TestSubCommandUnknownFlag(t *testing.T) {
+	//Create a mock subcommand
+	msc := NewMockSC()
+
+	//Set MockSC Values
+	msc.CmdName = "test"
+
+	//Create an argument array
+	args := []string{msc.CmdName, "--unknown"}
+
+	//Run the SubCommand function
+	err := SubCommand(args, []Runner{msc}, utils.NewMockUtility())
+
+	exErr := "flag provided but not defined: -unknown"
+
+	if err == nil {
+		t.Fatalf("SubCommand: Expected to have error: %s | Received No Error", exErr)
+	}
+
+	if err.Error() != exErr {
+		t.Fatalf("SubCommand: Expected to have error: %s | Received: %s", exErr, err.Error())
+	}
+}
+
+//Test the SubCommand function shows help instead of running the subcommand
+func TestSubCommandHelpFlag(t *testing.T) {
+	cases := [][]string{
+		{"-h"},
+		{"--help"},
+		{"test", "-h"},
+		{"test", "--help", "that"},
+		{"batch", "python", "--help"},
+	}
+
+	for _, args := range cases {
+		//Create the mock subcommands
+		msc := NewMockSC()
+		msc.CmdName = "test"
+
+		bmsc := NewMockSC()
+		bmsc.CmdName = "batch"
+		bmsc.Batched = true
+
+		mu := utils.NewMockUtility()
+
+		//Run the SubCommand function
+		err := SubCommand(args, []Runner{msc, bmsc}, mu)
+
+		if err != nil {
+			t.Fatalf("SubCommand(%v): Unexpected error: %s", args, err)
+		}
+
+		if msc.Ran || bmsc.Ran {
+			t.Fatalf("SubCommand(%v): Expected the subcommand to not have been ran but it was", args)
+		}
+
+		callStack := []string{"RenderInfoMarkdown"}
+
+		if !reflect.DeepEqual(callStack, mu.Calls) {
+			t.Fatalf("SubCommand(%v): Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", args, mu.Calls, callStack)
+		}
+	}
+}
+
+//Test the SubCommand function runs a batch subcommand for every pim
+func TestSubCommandBatchRunsAllPims(t *testing.T) {
+	//Create a mock subcommand
+	msc := NewMockSC()
+
+	//Set MockSC Values
+	msc.CmdName = "install"
+	msc.Batched = true
+
+	//Create an argument array
+	args := []string{msc.CmdName, "node", "python", "go"}
+
+	mu := utils.NewMockUtility()
+
+	//Run the SubCommand function
+	err := SubCommand(args, []Runner{msc}, mu)
+
+	//There shouldn't be an error
+	if err != nil {
+		t.Fatalf("SubCommand: Unexpected error: %s", err)
+	}
+
+	//Every pim should have been ran
+	if !reflect.DeepEqual(args[1:], msc.RanPims) {
+		t.Fatalf("SubCommand: Expected Ran Pims: %v | Received: %v", args[1:], msc.RanPims)
+	}
+
+	//The summary should have been emitted
+	callStack := []string{"Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+}
+
+//Test the SubCommand function continues a batch after a failure and reports the failure
+func TestSubCommandBatchContinuesAfterFailure(t *testing.T) {
+	//Create a mock subcommand
+	msc := NewMockSC()
+
+	//Set MockSC Values
+	msc.CmdName = "install"
+	msc.Batched = true
+	msc.ErrorPim = "python"
+	msc.SkipPim = "go"
+	msc.ErrorMsg = "Testing error for a single pim"
+
+	//Create an argument array
+	args := []string{msc.CmdName, "node", "python", "go", "ruby"}
+
+	mu := utils.NewMockUtility()
+
 	//Run the SubCommand function
-	err := SubCommand(args, scmds)
+	err := SubCommand(args, []Runner{msc}, mu)
+
+	exErr := "install failed for 1 of 4 pims"
 
 	//Should have an error
 	if err == nil {
-		t.Fatalf("SubCommand: Expected to have error: %s | Received No Error", msc.ErrorMsg)
+		t.Fatalf("SubCommand: Expected to have error: %s | Received No Error", exErr)
+	}
+
+	if err.Error() != exErr {
+		t.Fatalf("SubCommand: Expected to have error: %s | Received: %s", exErr, err.Error())
+	}
+
+	//Every pim should have been ran
+	if !reflect.DeepEqual(args[1:], msc.RanPims) {
+		t.Fatalf("SubCommand: Expected Ran Pims: %v | Received: %v", args[1:], msc.RanPims)
+	}
+
+	//The summary should have been emitted with the batch failed code
+	callStack := []string{"Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if mu.Events[0].Code != utils.ErrorCodeBatchFailed {
+		t.Fatalf("SubCommand: Expected the summary to have the code %s | Received: %s", utils.ErrorCodeBatchFailed, mu.Events[0].Code)
+	}
+
+	if utils.ErrorCode(err) != utils.ErrorCodeBatchFailed {
+		t.Fatalf("SubCommand: Expected the error to have the code %s | Received: %s", utils.ErrorCodeBatchFailed, utils.ErrorCode(err))
 	}
 }
 
+//Test the SubCommand function stops a batch at the first failure when --fail-fast is passed
+func TestSubCommandBatchFailFast(t *testing.T) {
+	//Create a mock subcommand
+	msc := NewMockSC()
+
+	//Set MockSC Values
+	msc.CmdName = "upgrade"
+	msc.Batched = true
+	msc.ErrorPim = "python"
+	msc.ErrorMsg = "Testing error for a single pim"
+
+	//Create an argument array
+	args := []string{msc.CmdName, "--fail-fast", "node", "python", "go"}
+
+	//Run the SubCommand function
+	err := SubCommand(args, []Runner{msc}, utils.NewMockUtility())
+
+	//Should have an error
+	if err == nil {
+		t.Fatal("SubCommand: Expected to have an error | Received No Error")
+	}
+
+	//The pims after the failure should not have been ran
+	ranPims := []string{"node", "python"}
+
+	if !reflect.DeepEqual(ranPims, msc.RanPims) {
+		t.Fatalf("SubCommand: Expected Ran Pims: %v | Received: %v", ranPims, msc.RanPims)
+	}
+}
+
+//Test the SubCommand function passes the value of a flag in a separate argument to a batch subcommand instead of running it as a pim
+func TestSubCommandBatchFlagValue(t *testing.T) {
+	//Create a mock subcommand
+	msc := NewMockSC()
+
+	//Set MockSC Values
+	msc.CmdName = "upgrade"
+	msc.Batched = true
+
+	//Create an argument array with flags before and after the pims
+	args := []string{msc.CmdName, "--value", "5", "node", "--flag", "python"}
+
+	//Run the SubCommand function
+	err := SubCommand(args, []Runner{msc}, utils.NewMockUtility())
+
+	//There shouldn't be an error
+	if err != nil {
+		t.Fatalf("SubCommand: Unexpected error: %s", err)
+	}
+
+	ranPims := []string{"node", "python"}
+
+	if !reflect.DeepEqual(ranPims, msc.RanPims) {
+		t.Fatalf("SubCommand: Expected Ran Pims: %v | Received: %v", ranPims, msc.RanPims)
+	}
+
+	if msc.Value != "5" || !msc.Flag {
+		t.Fatalf("SubCommand: Expected the flags to be passed to the subcommand | Received: %s %v", msc.Value, msc.Flag)
+	}
+}
+
+//Test the SubCommand function runs a batch subcommand once when no pims are passed
+func TestSubCommandBatchNoPims(t *testing.T) {
+	//Create a mock subcommand
+	msc := NewMockSC()
+
+	//Set MockSC Values
+	msc.CmdName = "upgrade"
+	msc.Batched = true
+
+	//Create an argument array
+	args := []string{msc.CmdName}
+
+	//Run the SubCommand function
+	err := SubCommand(args, []Runner{msc}, utils.NewMockUtility())
+
+	//There shouldn't be an error
+	if err != nil {
+		t.Fatalf("SubCommand: Unexpected error: %s", err)
+	}
+
+	//The command should still have been ran
+	if !msc.Ran {
+		t.Fatalf("SubCommand: Expected the subcommand to have been ran but it was not")
+	}
+}
+
+//Test the renderBatchSummary function
+func TestRenderBatchSummary(t *testing.T) {
+	results := []BatchResult{
+		{Pim: "node", Status: StatusSucceeded},
+		{Pim: "python", Status: StatusFailed, Reason: "a | b"},
+	}
+
+	expected := "# install summary\n" +
+		"| pim | result | reason |\n" +
+		"| --- | --- | --- |\n" +
+		"| node | succeeded |  |\n" +
+		"| python | failed | a \\| b |\n"
+
+	summary := renderBatchSummary("install", results)
+
+	if summary != expected {
+		t.Fatalf("renderBatchSummary: Expected: %q | Received: %q", expected, summary)
+	}
+}
+
+//Test the help flag is found the same way as the arguments are parsed when the subcommand is run
+func TestHelpRequested(t *testing.T) {
+	cases := []struct {
+		args    []string
+		batched bool
+		help    bool
+	}{
+		{[]string{}, false, true},
+		{[]string{"--help"}, false, true},
+		{[]string{"mock", "-h"}, false, true},
+		{[]string{"mock", "--flag", "--help"}, false, true},
+		{[]string{"mock", "python", "-h"}, false, false},
+		{[]string{"mock", "--value", "-h"}, false, false},
+		{[]string{"mock", "python", "-h"}, true, true},
+		{[]string{"mock", "python", "--value", "-h"}, true, false},
+		{[]string{"mock", "python"}, true, false},
+		{[]string{"unknown", "-h"}, false, false},
+	}
+
+	for _, c := range cases {
+		msc := NewMockSC()
+		msc.CmdName = "mock"
+		msc.Batched = c.batched
+
+		if HelpRequested(c.args, []Runner{msc}) != c.help {
+			t.Fatalf("HelpRequested(%v): Expected: %t | Received: %t", c.args, c.help, !c.help)
+		}
+
+		//The flags of the subcommand should not have been set
+		if msc.Flag || msc.Value != "" {
+			t.Fatalf("HelpRequested(%v): The flags should not have been set | Flag: %t | Value: %s", c.args, msc.Flag, msc.Value)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Changes sync makes to match the lockfile
+const (
+	syncInstall      = "install"
+	syncUpdateConfig = "update_config"
+	syncChangeImage  = "change_image"
+	syncUninstall    = "uninstall"
+)
+
+//Sync Sub-Command Object
+type SyncCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//Path of the lockfile to sync with
+	path string
+
+	//Only report the differences from the lockfile instead of changing anything
+	check bool
+
+	//Uninstall the pims that are not in the lockfile
+	remove bool
+
+	tools utils.Tools
+
+	cp utils.Copier
+
+	config utils.Config
+}
+
+//SyncChange - A difference between the installed pims and the lockfile, and the change sync makes for it
+type SyncChange struct {
+	Pim     string `json:"pim"`
+	Version string `json:"version"`
+	Action  string `json:"action"`
+	Reason  string `json:"reason"`
+
+	//Locked digest of the image to install or change to
+	digest string
+
+	//Locked checksum of the pim configuration
+	checksum string
+}
+
+//Instantiation method for a new SyncCommand
+func NewSyncCommand(tools utils.Tools, cp utils.Copier, config utils.Config) *SyncCommand {
+	//Create a new SyncCommand and set the FlagSet
+	sc := &SyncCommand{
+		fs:     flag.NewFlagSet("sync", flag.ContinueOnError),
+		tools:  tools,
+		cp:     cp,
+		config: config,
+	}
+
+	sc.fs.BoolVar(&sc.check, "check", false, "only report the differences from the lockfile and exit with an error if there are any")
+	sc.fs.BoolVar(&sc.remove, "remove", false, "uninstall the pims that are not in the lockfile")
+
+	return sc
+}
+
+//Name - Gets the name of the Sub-Command
+func (sc *SyncCommand) Name() string {
+	return sc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (sc *SyncCommand) Description() string {
+	return "Install, upgrade or downgrade pims to match a lockfile written by export"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (sc *SyncCommand) Usage() string {
+	return "packageless sync [OPTIONS] [lockfile]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (sc *SyncCommand) Flags() *flag.FlagSet {
+	return sc.fs
+}
+
+//Init - Parses and Populates values of the Sync subcommand
+func (sc *SyncCommand) Init(args []string) error {
+	err := sc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	sc.path = utils.LockFile
+
+	if sc.fs.NArg() > 0 {
+		sc.path = sc.fs.Arg(0)
+	}
+
+	return nil
+}
+
+//Run - Runs the Sync subcommand
+func (sc *SyncCommand) Run() error {
+	content, err := sc.tools.ReadFile(sc.path)
+
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return utils.WithErrorCode(utils.ErrorCodeInvalidArguments, errors.New("Could not find the lockfile "+sc.path+", it can be created with packageless export"))
+		}
+
+		return err
+	}
+
+	entries, diags := utils.LoadLockfileHCL([]byte(content), sc.path)
+
+	if diags.HasErrors() {
+		return utils.WithErrorCode(utils.ErrorCodeInvalidConfig, errors.New("Encountered an error while parsing the lockfile "+sc.path+": "+diags.Error()))
+	}
+
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	changes, err := sc.changes(entries, cli)
+
+	if err != nil {
+		return err
+	}
+
+	if sc.check {
+		sc.tools.Emit(utils.Event{
+			Type:     utils.EventResult,
+			Message:  syncText(changes, "Every pim matches the lockfile"),
+			Data:     changes,
+			Markdown: renderSync(changes, "Differences from the lockfile", "*Every pim matches the lockfile*"),
+		})
+
+		if len(changes) > 0 {
+			return utils.WithErrorCode(utils.ErrorCodeDrifted, fmt.Errorf("The installed pims differ from the lockfile %s in %d places", sc.path, len(changes)))
+		}
+
+		return nil
+	}
+
+	for _, change := range changes {
+		err = sc.apply(change)
+
+		if err != nil {
+			return errors.New("Could not " + strings.ReplaceAll(change.Action, "_", " ") + " " + change.Pim + ":" + change.Version + ": " + err.Error())
+		}
+	}
+
+	sc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  syncText(changes, "Every pim already matches the lockfile"),
+		Data:     changes,
+		Markdown: renderSync(changes, "Synced with the lockfile", "*Every pim already matches the lockfile*"),
+	})
+
+	return nil
+}
+
+//changes - Compares the installed pims with the entries of the lockfile
+func (sc *SyncCommand) changes(entries []utils.LockEntry, cli utils.Client) ([]SyncChange, error) {
+	pimConfigDir := sc.config.BaseDir + sc.config.PimsConfigDir
+
+	changes := []SyncChange{}
+
+	//Pim configurations are shared by the versions of a pim so they are only compared once
+	configChecked := map[string]bool{}
+	locked := map[string]bool{}
+
+	for _, entry := range entries {
+		locked[entry.Pim+":"+entry.Version] = true
+
+		change := SyncChange{Pim: entry.Pim, Version: entry.Version, digest: entry.Digest, checksum: entry.ConfigChecksum}
+
+		pimPath := pimConfigDir + entry.Pim + ".hcl"
+
+		if !configChecked[entry.Pim] && sc.tools.FileExists(pimPath) {
+			configChecked[entry.Pim] = true
+
+			content, err := sc.tools.ReadFile(pimPath)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if entry.ConfigChecksum != "" && configChecksum(content) != entry.ConfigChecksum {
+				change.Action = syncUpdateConfig
+				change.Reason = "the pim configuration differs from the lockfile"
+				changes = append(changes, change)
+			}
+		}
+
+		img, imgExist, err := sc.tools.FindImage(entry.Image, cli)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !imgExist {
+			change.Action = syncInstall
+			change.Reason = "it is not installed"
+			changes = append(changes, change)
+		} else if local := repoDigest(entry.Image, img.RepoDigests); entry.Digest != "" && local != entry.Digest {
+			change.Action = syncChangeImage
+			change.Reason = "the image is " + local + " instead of " + entry.Digest
+
+			if local == "" {
+				change.Reason = "the image was not pulled from a registry, the lockfile has " + entry.Digest
+			}
+
+			changes = append(changes, change)
+		}
+	}
+
+	if !sc.remove {
+		return changes, nil
+	}
+
+	//Get list of installed pims
+	pimNames, err := sc.tools.GetListOfInstalledPimConfigs(pimConfigDir)
+
+	if err != nil {
+		return nil, errors.New("Encountered an error while trying to fetch list of installed pim configuration files: " + err.Error())
+	}
+
+	for _, pimName := range pimNames {
+		pimListBody, err := sc.tools.GetHCLBody(pimConfigDir + pimName + ".hcl")
+
+		if err != nil {
+			return nil, err
+		}
+
+		//Parse the pim list
+		parseOut, err := sc.tools.ParseBody(pimListBody, utils.PimHCLUtil{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		pims := parseOut.(utils.PimHCLUtil)
+
+		for _, pim := range pims.Pims {
+			for _, ver := range pim.Versions {
+				if locked[pim.Name+":"+ver.Version] {
+					continue
+				}
+
+				imgExist, err := sc.tools.ImageExists(ver.Image, cli)
+
+				if err != nil {
+					return nil, err
+				}
+
+				if imgExist {
+					changes = append(changes, SyncChange{Pim: pim.Name, Version: ver.Version, Action: syncUninstall, Reason: "it is not in the lockfile"})
+				}
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+//apply - Makes a change so that the pim matches the lockfile, using the subcommands that make the same changes
+func (sc *SyncCommand) apply(change SyncChange) error {
+	pimRef := change.Pim + ":" + change.Version
+
+	var cmd Runner
+
+	switch change.Action {
+	case syncUpdateConfig:
+		sc.tools.Emit(utils.Event{Type: utils.EventStarted, Pim: pimRef, Message: "Updating the pim configuration"})
+
+		pimConfigDir := sc.config.BaseDir + sc.config.PimsConfigDir
+		pimPath := pimConfigDir + change.Pim + ".hcl"
+
+		//The pim configuration is verified against the repository index the same way as when updating it
+		checksums, err := fetchIndexChecksums(sc.tools, sc.config, pimRef)
+
+		if err != nil {
+			return err
+		}
+
+		checksum := checksums[change.Pim]
+
+		//Keep the current pim configuration so that it can be restored if the new one does not match the index
+		var previous string
+
+		if checksum != "" {
+			previous, err = sc.tools.ReadFile(pimPath)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		err = sc.tools.FetchPimConfig(sc.config.RepositoryHost, change.Pim, pimConfigDir)
+
+		if err != nil {
+			return err
+		}
+
+		err = verifyPimConfig(sc.tools, change.Pim, pimPath, checksum)
+
+		if err != nil {
+			sc.tools.WriteFile(pimPath, previous)
+			return err
+		}
+
+		sc.verifyConfig(change)
+
+		sc.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: pimRef, Message: "successfully updated the pim configuration"})
+
+		return nil
+	case syncInstall:
+		ic := NewInstallCommand(sc.tools, sc.cp, sc.config)
+		ic.digest = change.digest
+		cmd = ic
+	case syncChangeImage:
+		//The lockfile is what was asked for, so pinned pims are changed as well
+		uc := NewUpgradeCommand(sc.tools, sc.cp, sc.config)
+		uc.digest = change.digest
+		uc.force = true
+		cmd = uc
+	case syncUninstall:
+		cmd = NewUninstallCommand(sc.tools, sc.config)
+	}
+
+	err := cmd.Init([]string{pimRef})
+
+	if err != nil {
+		return err
+	}
+
+	err = cmd.Run()
+
+	if err != nil {
+		return err
+	}
+
+	//Installing fetches the pim configuration when it is missing
+	if change.Action == syncInstall {
+		sc.verifyConfig(change)
+	}
+
+	return nil
+}
+
+//verifyConfig - Warns when the pim configuration in the repository is not the locked one anymore, it can not be fetched in any other way
+func (sc *SyncCommand) verifyConfig(change SyncChange) {
+	if change.checksum == "" {
+		return
+	}
+
+	content, err := sc.tools.ReadFile(sc.config.BaseDir + sc.config.PimsConfigDir + change.Pim + ".hcl")
+
+	if err != nil || configChecksum(content) != change.checksum {
+		sc.tools.Emit(utils.Event{Type: utils.EventWarning, Pim: change.Pim, Message: "The repository does not have the pim configuration of " + change.Pim + " that is in the lockfile anymore, the one it has is used instead"})
+	}
+}
+
+//renderSync - Creates the markdown table listing the changes
+func renderSync(changes []SyncChange, title string, empty string) string {
+	if len(changes) == 0 {
+		return empty
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("# " + title + "\n")
+	sb.WriteString("| pim | version | change | reason |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+
+	for _, change := range changes {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", change.Pim, change.Version, strings.ReplaceAll(change.Action, "_", " "), change.Reason))
+	}
+
+	return sb.String()
+}
+
+//syncText - Creates the plain text listing the changes, one line per change
+func syncText(changes []SyncChange, empty string) string {
+	if len(changes) == 0 {
+		return empty
+	}
+
+	var lines []string
+
+	for _, change := range changes {
+		lines = append(lines, fmt.Sprintf("%s:%s %s, %s", change.Pim, change.Version, strings.ReplaceAll(change.Action, "_", " "), change.Reason))
+	}
+
+	return strings.Join(lines, "\n")
+}
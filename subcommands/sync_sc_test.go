@@ -0,0 +1,246 @@
+package subcommands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Create a mock utility with python installed exactly as the lockfile has it
+func syncTestUtility() *utils.MockUtility {
+	mu := utils.NewMockUtility()
+
+	config := "pim \"python\" {}\n"
+
+	mu.InstalledPims = []string{"python"}
+	mu.ImgExist = true
+	mu.ImgSummary = types.ImageSummary{ID: "sha256:imageid", RepoDigests: []string{"packageless/python@sha256:locked"}}
+
+	lockfile := utils.LockfileHCL([]utils.LockEntry{
+		{Pim: "python", Version: "latest", Image: "packageless/python", Digest: "sha256:locked", ConfigChecksum: utils.Checksum([]byte(config))},
+	})
+
+	mu.FileContents = map[string]string{
+		mu.Conf.BaseDir + mu.Conf.PimsConfigDir + "python.hcl": config,
+		utils.LockFile: lockfile,
+	}
+
+	return mu
+}
+
+func TestSyncName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	sc := NewSyncCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	if sc.Name() != "sync" {
+		t.Fatalf("The sync subcommand's name should be: 'sync' but was '%s'", sc.Name())
+	}
+}
+
+//Test nothing is changed when the installed pims match the lockfile
+func TestSyncInSync(t *testing.T) {
+	mu := syncTestUtility()
+
+	sc := NewSyncCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := sc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"ReadFile",
+		"FileExists",
+		"ReadFile",
+		"FindImage",
+		"Emit",
+	}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if !reflect.DeepEqual([]SyncChange{}, mu.Events[0].Data) {
+		t.Fatalf("Sync: Expected no changes | Received: %v", mu.Events[0].Data)
+	}
+}
+
+//Test checking reports the differences from the lockfile as an error without changing anything
+func TestSyncCheckDrift(t *testing.T) {
+	mu := syncTestUtility()
+	mu.ImgSummary.RepoDigests = []string{"packageless/python@sha256:newer"}
+	mu.FileContents[mu.Conf.BaseDir+mu.Conf.PimsConfigDir+"python.hcl"] = "pim \"python\" {\n  base_dir = \"python\"\n}\n"
+
+	sc := NewSyncCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := sc.Init([]string{"--check"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if utils.ErrorCode(err) != utils.ErrorCodeDrifted {
+		t.Fatalf("Sync: Expected an error with the code %s | Received: %v", utils.ErrorCodeDrifted, err)
+	}
+
+	actions := []string{}
+
+	for _, change := range mu.Events[0].Data.([]SyncChange) {
+		actions = append(actions, change.Action)
+	}
+
+	if !reflect.DeepEqual([]string{syncUpdateConfig, syncChangeImage}, actions) {
+		t.Fatalf("Sync: Expected the pim configuration and image to differ | Received: %v", actions)
+	}
+
+	if len(mu.PulledImgs) > 0 || len(mu.FetchedPims) > 0 {
+		t.Fatalf("Sync: Expected nothing to be changed when checking | Pulled: %v | Fetched: %v", mu.PulledImgs, mu.FetchedPims)
+	}
+}
+
+//Test an image that differs from the lockfile is changed to the locked digest
+func TestSyncChangesImage(t *testing.T) {
+	mu := syncTestUtility()
+	mu.ImgSummary.RepoDigests = []string{"packageless/python@sha256:newer"}
+
+	sc := NewSyncCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := sc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref := "packageless/python@sha256:locked"
+
+	if !reflect.DeepEqual([]string{ref}, mu.PulledImgs) {
+		t.Fatalf("Sync: Expected the locked image to be pulled: %s | Received: %v", ref, mu.PulledImgs)
+	}
+
+	if mu.TaggedImages["packageless/python"] != ref {
+		t.Fatalf("Sync: Expected the locked image to be tagged as packageless/python | Received: %v", mu.TaggedImages)
+	}
+}
+
+//Test a pim that is not installed is installed with the locked digest
+func TestSyncInstallsMissing(t *testing.T) {
+	mu := syncTestUtility()
+	mu.ImgExist = false
+
+	sc := NewSyncCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := sc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{"packageless/python@sha256:locked"}, mu.PulledImgs) {
+		t.Fatalf("Sync: Expected the locked image to be pulled | Received: %v", mu.PulledImgs)
+	}
+
+	last := mu.Events[len(mu.Events)-1]
+	expected := []SyncChange{{Pim: "python", Version: "latest", Action: syncInstall, Reason: "it is not installed", digest: "sha256:locked", checksum: utils.Checksum([]byte("pim \"python\" {}\n"))}}
+
+	if !reflect.DeepEqual(expected, last.Data) {
+		t.Fatalf("Sync: Expected the changes: %v | Received: %v", expected, last.Data)
+	}
+}
+
+//Test the pims that are not in the lockfile are only uninstalled when asked to
+func TestSyncRemove(t *testing.T) {
+	mu := syncTestUtility()
+	mu.FileContents[utils.LockFile] = utils.LockfileHCL(nil)
+
+	sc := NewSyncCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := sc.Init([]string{"--remove"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{"packageless/python"}, mu.RemovedImgs) {
+		t.Fatalf("Sync: Expected python to be uninstalled | Received: %v", mu.RemovedImgs)
+	}
+}
+
+//Test a lockfile that does not exist is reported as an invalid argument
+func TestSyncMissingLockfile(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	sc := NewSyncCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := sc.Init([]string{"missing.lock.hcl"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	if utils.ErrorCode(err) != utils.ErrorCodeInvalidArguments {
+		t.Fatalf("Sync: Expected an error with the code %s | Received: %v", utils.ErrorCodeInvalidArguments, err)
+	}
+}
+
+//Test a pim configuration that does not match the repository index is not kept when syncing
+func TestSyncVerifiesChecksum(t *testing.T) {
+	mu := syncTestUtility()
+
+	pimPath := mu.Conf.BaseDir + mu.Conf.PimsConfigDir + "python.hcl"
+	previous := "pim \"python\" {\n  base_dir = \"python\"\n}\n"
+
+	mu.FileContents[pimPath] = previous
+	mu.Index = utils.RepositoryIndex{Pims: []utils.IndexPim{{Name: "python", Checksum: "sha256:tampered"}}}
+
+	sc := NewSyncCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := sc.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = sc.Run()
+
+	exErr := "Could not update config python:latest: The downloaded pim configuration of python does not match the checksum sha256:tampered listed in the repository index"
+
+	if err == nil || err.Error() != exErr {
+		t.Fatalf("Sync: Expected the error: %s | Received: %v", exErr, err)
+	}
+
+	if mu.WrittenFiles[pimPath] != previous {
+		t.Fatalf("Sync: Expected the previous pim configuration to be restored | Received: %q", mu.WrittenFiles[pimPath])
+	}
+}
@@ -0,0 +1,41 @@
+# bash completion for packageless
+# Load it by adding the following line to ~/.bashrc:
+#   source <(packageless completion bash)
+
+# pim versions are separated by a colon so it can not be a word break
+COMP_WORDBREAKS=${COMP_WORDBREAKS//:}
+
+_packageless() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+
+    if [[ ${COMP_CWORD} -eq 1 ]]; then
+        if [[ ${cur} == -* ]]; then
+            COMPREPLY=($(compgen -W "--base-dir --config --no-color --output --quiet --verbose" -- "${cur}"))
+        else
+            COMPREPLY=($(compgen -W "install list run completion" -- "${cur}"))
+        fi
+        return
+    fi
+
+    if [[ ${cur} == -* ]]; then
+        case "${COMP_WORDS[1]}" in
+            install)
+                COMPREPLY=($(compgen -W "--fail-fast --help" -- "${cur}"))
+                ;;
+            list)
+                COMPREPLY=($(compgen -W "--all --help" -- "${cur}"))
+                ;;
+            run)
+                COMPREPLY=($(compgen -W "--explain --help --no-tty --tty" -- "${cur}"))
+                ;;
+            completion)
+                COMPREPLY=($(compgen -W "--help" -- "${cur}"))
+                ;;
+        esac
+        return
+    fi
+
+    COMPREPLY=($(compgen -W "$(packageless __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)" -- "${cur}"))
+}
+
+complete -F _packageless packageless
@@ -0,0 +1,25 @@
+# fish completion for packageless
+# Load it by running:
+#   packageless completion fish > ~/.config/fish/completions/packageless.fish
+
+complete -c packageless -f
+complete -c packageless -n '__fish_use_subcommand' -a install -d 'Install pims'
+complete -c packageless -n '__fish_use_subcommand' -a list -d 'List the installed pims'
+complete -c packageless -n '__fish_use_subcommand' -a run -d 'Run an installed pim'
+complete -c packageless -n '__fish_use_subcommand' -a completion -d 'Generate the completion script for bash, zsh or fish'
+complete -c packageless -n '__fish_use_subcommand' -l base-dir -r -F -d 'use DIR as the base directory instead of the base_dir configuration value'
+complete -c packageless -n '__fish_use_subcommand' -l config -r -F -d 'use the configuration file at PATH instead of ~/.packageless/config.hcl'
+complete -c packageless -n '__fish_use_subcommand' -l no-color -d 'output without colors, also enabled by setting the NO_COLOR environment variable'
+complete -c packageless -n '__fish_use_subcommand' -l output -r -F -d 'output FORMAT: markdown, plain, json'
+complete -c packageless -n '__fish_use_subcommand' -l quiet -d 'only output errors'
+complete -c packageless -n '__fish_use_subcommand' -l verbose -d 'output what packageless is doing'
+complete -c packageless -n '__fish_seen_subcommand_from install' -l fail-fast -d 'stop processing pims as soon as one of them fails'
+complete -c packageless -n '__fish_seen_subcommand_from install' -l help -d 'show the usage of the subcommand'
+complete -c packageless -n '__fish_seen_subcommand_from list' -l all -d 'include versions whose configuration exists but whose image is not downloaded'
+complete -c packageless -n '__fish_seen_subcommand_from list' -l help -d 'show the usage of the subcommand'
+complete -c packageless -n '__fish_seen_subcommand_from run' -l explain -d 'show how the version of the pim is chosen instead of running it'
+complete -c packageless -n '__fish_seen_subcommand_from run' -l help -d 'show the usage of the subcommand'
+complete -c packageless -n '__fish_seen_subcommand_from run' -l no-tty -d 'do not give the pim a terminal even if packageless runs in one'
+complete -c packageless -n '__fish_seen_subcommand_from run' -l tty -d 'give the pim a terminal even if packageless does not run in one'
+complete -c packageless -n '__fish_seen_subcommand_from completion' -l help -d 'show the usage of the subcommand'
+complete -c packageless -n 'not __fish_use_subcommand; and not string match -q -- "-*" (commandline -ct)' -a '(packageless __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
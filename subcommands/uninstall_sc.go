@@ -3,11 +3,9 @@ package subcommands
 import (
 	"errors"
 	"flag"
-	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
 
 	"github.com/docker/docker/client"
 	"github.com/everettraven/packageless/utils"
@@ -43,8 +41,35 @@ func (uc *UninstallCommand) Name() string {
 	return uc.fs.Name()
 }
 
+//Description - Gets the description of the Sub-Command
+func (uc *UninstallCommand) Description() string {
+	return "Uninstall pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (uc *UninstallCommand) Usage() string {
+	return "packageless uninstall [OPTIONS] [pim...]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (uc *UninstallCommand) Flags() *flag.FlagSet {
+	return uc.fs
+}
+
+//Batch - The uninstall subcommand is ran once for every pim passed as an argument
+func (uc *UninstallCommand) Batch() bool {
+	return true
+}
+
 //Init - Parses and Populates values of the Uninstall subcommand
 func (uc *UninstallCommand) Init(args []string) error {
+	err := uc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = uc.fs.Args()
 
 	if len(args) <= 0 {
 		return errors.New("No pim name was found. You must include the name of the pim you wish to uninstall.")
@@ -62,16 +87,11 @@ func (uc *UninstallCommand) Run() error {
 	var pim utils.PackageImage
 	var version utils.Version
 
-	var pimName string
-	var pimVersion string
+	//Versions that are not specified are resolved the same way as when running the pim
+	pimName, pimVersion, _, err := resolvePimName(uc.tools, uc.name)
 
-	if strings.Contains(uc.name, ":") {
-		split := strings.Split(uc.name, ":")
-		pimName = split[0]
-		pimVersion = split[1]
-	} else {
-		pimName = uc.name
-		pimVersion = "latest"
+	if err != nil {
+		return err
 	}
 
 	pimConfigDir := uc.config.BaseDir + uc.config.PimsConfigDir
@@ -142,13 +162,16 @@ func (uc *UninstallCommand) Run() error {
 
 	//If the image doesn't exist it can't be uninstalled
 	if !imgExist {
-		return errors.New("pim " + pim.Name + " with version '" + version.Version + "' is not installed.")
+		return Skip("pim " + pim.Name + " with version '" + version.Version + "' is not installed.")
 	}
 
-	uc.tools.RenderInfoMarkdown(fmt.Sprintf("**Uninstalling**: *%s*", pim.Name+":"+version.Version))
+	//Reference to the pim used in the events
+	pimRef := pim.Name + ":" + version.Version
+
+	uc.tools.Emit(utils.Event{Type: utils.EventStarted, Pim: pimRef, Message: "Uninstalling"})
 
 	//Check for the directories that correspond to this pims volumes
-	uc.tools.RenderInfoMarkdown("- *Removing pim directories*")
+	stepStarted(uc.tools, pimRef, "remove_directories", "Removing pim directories")
 
 	//Check the volumes and remove the directories if they exist
 	for _, vol := range version.Volumes {
@@ -166,7 +189,9 @@ func (uc *UninstallCommand) Run() error {
 		return err
 	}
 
-	uc.tools.RenderInfoMarkdown("- *Removing image*")
+	stepFinished(uc.tools, pimRef, "remove_directories")
+
+	stepStarted(uc.tools, pimRef, "remove_image", "Removing image")
 
 	//Remove the image
 	err = uc.tools.RemoveImage(version.Image, cli)
@@ -176,6 +201,19 @@ func (uc *UninstallCommand) Run() error {
 		return err
 	}
 
+	stepFinished(uc.tools, pimRef, "remove_image")
+
+	//There is nothing left to roll back to once the pim is uninstalled
+	stepStarted(uc.tools, pimRef, "remove_rollbacks", "Removing rollbacks")
+
+	err = removeRollbacks(uc.tools, uc.config, pim.Name, version.Version, cli)
+
+	if err != nil {
+		return err
+	}
+
+	stepFinished(uc.tools, pimRef, "remove_rollbacks")
+
 	//get the executable directory for removing the aliases
 	ex, err := os.Executable()
 
@@ -187,7 +225,7 @@ func (uc *UninstallCommand) Run() error {
 
 	if uc.config.Alias {
 		//Remove aliases
-		uc.tools.RenderInfoMarkdown("- *Removing Alias*")
+		stepStarted(uc.tools, pimRef, "remove_alias", "Removing Alias")
 
 		if runtime.GOOS == "windows" {
 			if version.Version != "latest" {
@@ -206,10 +244,11 @@ func (uc *UninstallCommand) Run() error {
 		if err != nil {
 			return err
 		}
+
+		stepFinished(uc.tools, pimRef, "remove_alias")
 	}
 
-	uc.tools.RenderInfoMarkdown("***")
-	uc.tools.RenderInfoMarkdown(fmt.Sprintf("*%s* **successfully uninstalled**", pim.Name))
+	uc.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: pimRef, Message: "successfully uninstalled"})
 
 	return nil
 }
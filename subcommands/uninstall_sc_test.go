@@ -126,6 +126,8 @@ func TestUninstallNonExistPackage(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -176,6 +178,8 @@ func TestUninstallImageNotExist(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -221,19 +225,27 @@ func TestUninstallFlow(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
+		"ListImages",
+		"RemoveDir",
+		"Emit",
+		"Emit",
 		"RemoveAlias",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -264,6 +276,9 @@ func TestUninstallFlow(t *testing.T) {
 			rmdirs = append(rmdirs, pimDir+vol.Path)
 		}
 
+		//The rollbacks of the version are removed with it
+		rmdirs = append(rmdirs, rollbackBaseDir(config)+pim.Name+"/"+version.Version+"/")
+
 		//Just use the first pim for the test
 		break
 	}
@@ -324,6 +339,8 @@ func TestUninstallErrorAtGetHCLBody(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 	}
@@ -371,6 +388,8 @@ func TestUninstallErrorAtParseBody(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -421,6 +440,8 @@ func TestUninstallErrorAtImageExists(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -472,12 +493,14 @@ func TestUninstallErrorAtRemoveDir(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveDir",
 	}
 
@@ -526,16 +549,24 @@ func TestUninstallErrorAtRemoveAlias(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
+		"ListImages",
+		"RemoveDir",
+		"Emit",
+		"Emit",
 		"RemoveAlias",
 	}
 
@@ -584,14 +615,17 @@ func TestUninstallErrorAtRemoveImage(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveImage",
 	}
 
@@ -631,17 +665,24 @@ func TestUninstallAliasFalse(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveImage",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
+		"ListImages",
+		"RemoveDir",
+		"Emit",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -668,6 +709,9 @@ func TestUninstallAliasFalse(t *testing.T) {
 			rmdirs = append(rmdirs, pimDir+vol.Path)
 		}
 
+		//The rollbacks of the version are removed with it
+		rmdirs = append(rmdirs, rollbackBaseDir(config)+pim.Name+"/"+version.Version+"/")
+
 		//Just use the first pim
 		break
 	}
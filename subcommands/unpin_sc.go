@@ -0,0 +1,118 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+
+	"github.com/everettraven/packageless/utils"
+)
+
+//Unpin Sub-Command Object
+type UnpinCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//String for the name of the pim to unpin
+	name string
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//Instantiation method for a new UnpinCommand
+func NewUnpinCommand(tools utils.Tools, config utils.Config) *UnpinCommand {
+	//Create a new UnpinCommand and set the FlagSet
+	uc := &UnpinCommand{
+		fs:     flag.NewFlagSet("unpin", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	return uc
+}
+
+//Name - Gets the name of the Sub-Command
+func (uc *UnpinCommand) Name() string {
+	return uc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (uc *UnpinCommand) Description() string {
+	return "Unpin pims so that upgrading or updating all pims includes them again"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (uc *UnpinCommand) Usage() string {
+	return "packageless unpin [pim[:version]...]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (uc *UnpinCommand) Flags() *flag.FlagSet {
+	return uc.fs
+}
+
+//Batch - The unpin subcommand is ran once for every pim passed as an argument
+func (uc *UnpinCommand) Batch() bool {
+	return true
+}
+
+//Init - Parses and Populates values of the Unpin subcommand
+func (uc *UnpinCommand) Init(args []string) error {
+	err := uc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = uc.fs.Args()
+
+	if len(args) <= 0 {
+		return errors.New("No pim name was found. You must include the name of the pim you wish to unpin.")
+	}
+
+	uc.name = args[0]
+
+	return nil
+}
+
+//Run - Runs the Unpin subcommand
+func (uc *UnpinCommand) Run() error {
+	holds, err := loadHolds(uc.tools, uc.config)
+
+	if err != nil {
+		return err
+	}
+
+	hold := parseHold(uc.name)
+
+	var kept []utils.Hold
+
+	for _, existing := range holds {
+		//Unpinning a pim without a version removes the holds of every version
+		if existing.Pim == hold.Pim && (hold.Version == "" || existing.Version == hold.Version) {
+			continue
+		}
+
+		kept = append(kept, existing)
+	}
+
+	if len(kept) == len(holds) {
+		//A single version can not be unpinned while every version of the pim is pinned
+		if utils.IsHeld(holds, hold.Pim, hold.Version) {
+			return errors.New(hold.String() + " is pinned by the pin of every version of " + hold.Pim + ", unpin " + hold.Pim + " instead")
+		}
+
+		return Skip(hold.String() + " is not pinned")
+	}
+
+	err = saveHolds(uc.tools, uc.config, kept)
+
+	if err != nil {
+		return err
+	}
+
+	uc.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: hold.String(), Message: "unpinned"})
+
+	return nil
+}
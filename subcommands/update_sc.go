@@ -3,7 +3,6 @@ package subcommands
 import (
 	"errors"
 	"flag"
-	"fmt"
 
 	"github.com/everettraven/packageless/utils"
 )
@@ -36,10 +35,33 @@ func (uc *UpdateCommand) Name() string {
 	return uc.fs.Name()
 }
 
+//Description - Gets the description of the Sub-Command
+func (uc *UpdateCommand) Description() string {
+	return "Update the pim configuration of the specified pim or all installed pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (uc *UpdateCommand) Usage() string {
+	return "packageless update [OPTIONAL: pim]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (uc *UpdateCommand) Flags() *flag.FlagSet {
+	return uc.fs
+}
+
 //Initialize the command, for this particular subcommand we should just do nothing
 func (uc *UpdateCommand) Init(args []string) error {
+	err := uc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = uc.fs.Args()
+
 	if len(args) <= 0 {
-		uc.tools.RenderInfoMarkdown("*No pim specified, updating all currently installed pim configurations*")
+		uc.tools.Emit(utils.Event{Type: utils.EventMessage, Message: "No pim specified, updating all currently installed pim configurations"})
 	} else {
 		uc.name = args[0]
 	}
@@ -50,6 +72,21 @@ func (uc *UpdateCommand) Init(args []string) error {
 //either the specified package or all currently installed packages
 func (uc *UpdateCommand) Run() error {
 	pimConfigDir := uc.config.BaseDir + uc.config.PimsConfigDir
+
+	//Cache the repository index first so that it is up to date even when no pims are installed,
+	//and so that the pim configurations can be verified against it
+	checksums, err := fetchIndexChecksums(uc.tools, uc.config, "")
+
+	if err != nil {
+		return err
+	}
+
+	//Nothing has been installed yet if the directory does not exist
+	if !uc.tools.FileExists(pimConfigDir) {
+		uc.tools.Emit(utils.Event{Type: utils.EventMessage, Message: "No pims are installed"})
+		return nil
+	}
+
 	//Get list of installed pims
 	pims, err := uc.tools.GetListOfInstalledPimConfigs(pimConfigDir)
 
@@ -57,6 +94,12 @@ func (uc *UpdateCommand) Run() error {
 		return errors.New("Encountered an error while trying to fetch list of installed pim configuration files: " + err.Error())
 	}
 
+	holds, err := loadHolds(uc.tools, uc.config)
+
+	if err != nil {
+		return err
+	}
+
 	//Loop and download most recent pim configuration for pims
 	for _, pim := range pims {
 
@@ -65,13 +108,39 @@ func (uc *UpdateCommand) Run() error {
 			if pim != uc.name {
 				continue
 			}
+		} else if utils.IsHeld(holds, pim, "") {
+			//A new pim configuration can change the image of a version so pinned pims are skipped when updating all pims
+			uc.tools.Emit(utils.Event{Type: utils.EventMessage, Pim: pim, Message: "Skipping " + pim + ", it is pinned"})
+			continue
+		}
+
+		uc.tools.Emit(utils.Event{Type: utils.EventStarted, Pim: pim, Message: "Updating pim"})
+
+		pimPath := pimConfigDir + pim + ".hcl"
+		checksum := checksums[pim]
+
+		//Keep the current pim configuration so that it can be restored if the new one does not match the index
+		var previous string
+
+		if checksum != "" {
+			previous, err = uc.tools.ReadFile(pimPath)
+			if err != nil {
+				return err
+			}
 		}
 
-		uc.tools.RenderInfoMarkdown(fmt.Sprintf("**Updating pim**: *%s*", pim))
 		err = uc.tools.FetchPimConfig(uc.config.RepositoryHost, pim, pimConfigDir)
 		if err != nil {
 			return errors.New("Encountered an error while trying to fetch the latest pim configuration file for pim '" + pim + "': " + err.Error())
 		}
+
+		err = verifyPimConfig(uc.tools, pim, pimPath, checksum)
+		if err != nil {
+			uc.tools.WriteFile(pimPath, previous)
+			return err
+		}
+
+		uc.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: pim})
 	}
 
 	return nil
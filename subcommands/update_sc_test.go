@@ -89,12 +89,23 @@ func TestUpdateNoArgsFlow(t *testing.T) {
 	}
 
 	callStack := []string{
-		"RenderInfoMarkdown",
+		"Emit",
+		"MakeDir",
+		"Emit",
+		"FetchIndex",
+		"Emit",
+		"FileExists",
+		"GetHCLBody",
+		"ParseBody",
+		"FileExists",
 		"GetListOfInstalledPimConfigs",
-		"RenderInfoMarkdown",
+		"ReadFile",
+		"Emit",
 		"FetchPimConfig",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"FetchPimConfig",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -140,9 +151,19 @@ func TestUpdateArgsFlow(t *testing.T) {
 	}
 
 	callStack := []string{
+		"MakeDir",
+		"Emit",
+		"FetchIndex",
+		"Emit",
+		"FileExists",
+		"GetHCLBody",
+		"ParseBody",
+		"FileExists",
 		"GetListOfInstalledPimConfigs",
-		"RenderInfoMarkdown",
+		"ReadFile",
+		"Emit",
 		"FetchPimConfig",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -194,6 +215,14 @@ func TestUpdateErrorAtGetListOfInstalledPimConfigs(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"MakeDir",
+		"Emit",
+		"FetchIndex",
+		"Emit",
+		"FileExists",
+		"GetHCLBody",
+		"ParseBody",
+		"FileExists",
 		"GetListOfInstalledPimConfigs",
 	}
 
@@ -244,8 +273,17 @@ func TestUpdateErrorAtFetchPimConfig(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"MakeDir",
+		"Emit",
+		"FetchIndex",
+		"Emit",
+		"FileExists",
+		"GetHCLBody",
+		"ParseBody",
+		"FileExists",
 		"GetListOfInstalledPimConfigs",
-		"RenderInfoMarkdown",
+		"ReadFile",
+		"Emit",
 		"FetchPimConfig",
 	}
 
@@ -253,3 +291,136 @@ func TestUpdateErrorAtFetchPimConfig(t *testing.T) {
 		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
 	}
 }
+
+func TestUpdateErrorAtFetchIndex(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ErrorAt = "FetchIndex"
+
+	mu.InstalledPims = []string{"python"}
+
+	updateCommand := NewUpdateCommand(mu, mu.Conf)
+
+	err := updateCommand.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//A repository without an index should not cause the update to fail
+	err = updateCommand.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Set a variable with the proper call stack and see if the call stack matches
+	callStack := []string{
+		"MakeDir",
+		"Emit",
+		"FetchIndex",
+		"Emit",
+		"FileExists",
+		"GetListOfInstalledPimConfigs",
+		"ReadFile",
+		"Emit",
+		"FetchPimConfig",
+		"Emit",
+	}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	if !reflect.DeepEqual(mu.FetchedIndexes, []string(nil)) {
+		t.Fatalf("The index should not have been fetched. Fetched Indexes: %v", mu.FetchedIndexes)
+	}
+}
+
+//Test updating all pims skips the pinned pims
+func TestUpdateNoArgsSkipsPinned(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.InstalledPims = []string{"python", "another"}
+	mu.FileContents = map[string]string{mu.Conf.BaseDir + utils.HoldsFile: utils.HoldsHCL([]utils.Hold{{Pim: "python", Version: "latest"}})}
+
+	updateCommand := NewUpdateCommand(mu, mu.Conf)
+
+	err := updateCommand.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = updateCommand.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{"another"}, mu.FetchedPims) {
+		t.Fatalf("Update: Expected only the pims that are not pinned to be updated | Received: %v", mu.FetchedPims)
+	}
+}
+
+//Test the previous pim configuration is restored when the downloaded one does not match the repository index
+func TestUpdateVerifiesChecksum(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	pimPath := mu.Conf.BaseDir + mu.Conf.PimsConfigDir + "python.hcl"
+
+	mu.InstalledPims = []string{"python"}
+	mu.FileContents = map[string]string{pimPath: "pim \"python\" {}\n"}
+	mu.Index = utils.RepositoryIndex{Pims: []utils.IndexPim{{Name: "python", Checksum: "sha256:tampered"}}}
+
+	updateCommand := NewUpdateCommand(mu, mu.Conf)
+
+	err := updateCommand.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = updateCommand.Run()
+
+	exErr := "The downloaded pim configuration of python does not match the checksum sha256:tampered listed in the repository index"
+
+	if err == nil || err.Error() != exErr {
+		t.Fatalf("Update: Expected the error: %s | Received: %v", exErr, err)
+	}
+
+	if mu.WrittenFiles[pimPath] != mu.FileContents[pimPath] {
+		t.Fatalf("Update: Expected the previous pim configuration to be restored | Received: %q", mu.WrittenFiles[pimPath])
+	}
+}
+
+//Test the repository index is cached even when nothing has been installed yet
+func TestUpdateNoPimConfigDir(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.PimConfigShouldExist = false
+
+	updateCommand := NewUpdateCommand(mu, mu.Conf)
+
+	err := updateCommand.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = updateCommand.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]string{mu.Conf.BaseDir}, mu.FetchedIndexes) {
+		t.Fatalf("Update: Expected the repository index to be fetched to %s | Received: %v", mu.Conf.BaseDir, mu.FetchedIndexes)
+	}
+
+	last := mu.Events[len(mu.Events)-1]
+
+	if last.Type != utils.EventMessage || last.Message != "No pims are installed" {
+		t.Fatalf("Update: Expected a message that no pims are installed | Received: %v", last)
+	}
+}
@@ -3,8 +3,7 @@ package subcommands
 import (
 	"errors"
 	"flag"
-	"fmt"
-	"strings"
+	"time"
 
 	"github.com/docker/docker/client"
 	"github.com/everettraven/packageless/utils"
@@ -18,11 +17,20 @@ type UpgradeCommand struct {
 	//String for the name of the pim to upgrade
 	name string
 
+	//Upgrade pinned pims as well
+	force bool
+
 	tools utils.Tools
 
 	cp utils.Copier
 
 	config utils.Config
+
+	//Returns the current time, so that the generations of rollbacks can be tested
+	now func() time.Time
+
+	//Digest of the image to upgrade or downgrade to instead of the latest image, set by sync
+	digest string
 }
 
 //Instantiation method for a new UpgradeCommand
@@ -33,8 +41,11 @@ func NewUpgradeCommand(tools utils.Tools, cp utils.Copier, config utils.Config)
 		tools:  tools,
 		cp:     cp,
 		config: config,
+		now:    time.Now,
 	}
 
+	ic.fs.BoolVar(&ic.force, "force", false, "upgrade pims even if they are pinned")
+
 	return ic
 }
 
@@ -43,10 +54,38 @@ func (ic *UpgradeCommand) Name() string {
 	return ic.fs.Name()
 }
 
+//Description - Gets the description of the Sub-Command
+func (ic *UpgradeCommand) Description() string {
+	return "Upgrade the specified pims or all installed pims"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (ic *UpgradeCommand) Usage() string {
+	return "packageless upgrade [OPTIONS] [OPTIONAL: pim...]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (ic *UpgradeCommand) Flags() *flag.FlagSet {
+	return ic.fs
+}
+
+//Batch - The upgrade subcommand is ran once for every pim passed as an argument
+func (ic *UpgradeCommand) Batch() bool {
+	return true
+}
+
 //Init - Parses and Populates values of the Upgrade subcommand
 func (ic *UpgradeCommand) Init(args []string) error {
+	err := ic.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = ic.fs.Args()
+
 	if len(args) <= 0 {
-		ic.tools.RenderInfoMarkdown("*No pim specified, upgrading all currently installed pims*")
+		ic.tools.Emit(utils.Event{Type: utils.EventMessage, Message: "No pim specified, upgrading all currently installed pims"})
 	} else {
 		ic.name = args[0]
 	}
@@ -60,16 +99,11 @@ func (ic *UpgradeCommand) Run() error {
 	var pim utils.PackageImage
 	var version utils.Version
 
-	var pimName string
-	var pimVersion string
+	//Versions that are not specified are resolved the same way as when running the pim
+	pimName, pimVersion, _, err := resolvePimName(ic.tools, ic.name)
 
-	if strings.Contains(ic.name, ":") {
-		split := strings.Split(ic.name, ":")
-		pimName = split[0]
-		pimVersion = split[1]
-	} else {
-		pimName = ic.name
-		pimVersion = "latest"
+	if err != nil {
+		return err
 	}
 
 	//Create the Docker client
@@ -81,6 +115,12 @@ func (ic *UpgradeCommand) Run() error {
 	pimConfigDir := ic.config.BaseDir + ic.config.PimsConfigDir
 	pimDir := ic.config.BaseDir + ic.config.PimsDir
 
+	holds, err := loadHolds(ic.tools, ic.config)
+
+	if err != nil {
+		return err
+	}
+
 	if pimName != "" {
 
 		pimPath := pimConfigDir + pimName + ".hcl"
@@ -140,16 +180,40 @@ func (ic *UpgradeCommand) Run() error {
 			return errors.New("pim: " + pim.Name + " with version '" + version.Version + "' is not installed. It must be installed before it can be upgraded.")
 		}
 
-		ic.tools.RenderInfoMarkdown(fmt.Sprintf("**Upgrading**: *%s*", pim.Name+":"+version.Version))
+		//Reference to the pim used in the events
+		pimRef := pim.Name + ":" + version.Version
+
+		//Pinned pims are only upgraded when forced
+		if !ic.force && utils.IsHeld(holds, pim.Name, version.Version) {
+			return Skip(pimRef + " is pinned, use packageless upgrade --force " + pimRef + " to upgrade it anyway")
+		}
+
+		ic.tools.Emit(utils.Event{Type: utils.EventStarted, Pim: pimRef, Message: "Upgrading"})
+
+		generation, imageID, err := ic.saveRollback(pim.Name, version, pimRef, cli)
+
+		if err != nil {
+			return err
+		}
+
 		//Pull the image down from Docker Hub
-		ic.tools.RenderInfoMarkdown(fmt.Sprintf("- *Pulling image %s*", version.Image))
-		err = ic.tools.PullImage(version.Image, cli)
+		stepStarted(ic.tools, pimRef, "pull_image", "Pulling image "+version.Image)
+
+		err = pullImage(ic.tools, version.Image, ic.digest, cli)
 
 		if err != nil {
 			return err
 		}
 
-		ic.tools.RenderInfoMarkdown("- *Updating pim directories*")
+		stepFinished(ic.tools, pimRef, "pull_image")
+
+		err = ic.keepRollback(pim.Name, version, generation, imageID, cli)
+
+		if err != nil {
+			return err
+		}
+
+		stepStarted(ic.tools, pimRef, "update_directories", "Updating pim directories")
 
 		//Check the volumes and create the directories for them if they don't already exist
 		for _, vol := range version.Volumes {
@@ -163,10 +227,13 @@ func (ic *UpgradeCommand) Run() error {
 			}
 		}
 
+		stepFinished(ic.tools, pimRef, "update_directories")
+
 		//Check and see if any files need to be copied from the container to one of the volumes on the host.
 		if len(version.Copies) > 0 {
 
-			ic.tools.RenderInfoMarkdown("- *Copying necessary files (create container)*")
+			stepStarted(ic.tools, pimRef, "create_container", "Copying necessary files (create container)")
+
 			//Create the container so that we can copy the files over to the right places
 			containerID, err := ic.tools.CreateContainer(version.Image, cli)
 
@@ -174,7 +241,10 @@ func (ic *UpgradeCommand) Run() error {
 				return err
 			}
 
-			ic.tools.RenderInfoMarkdown("- *Copying necessary files (copy files from container)*")
+			stepFinished(ic.tools, pimRef, "create_container")
+
+			stepStarted(ic.tools, pimRef, "copy_files", "Copying necessary files (copy files from container)")
+
 			//Copy the files from the container to the locations
 			for _, copy := range version.Copies {
 				err = ic.tools.CopyFromContainer(copy.Source, pimDir+copy.Dest, containerID, cli, ic.cp)
@@ -184,7 +254,10 @@ func (ic *UpgradeCommand) Run() error {
 				}
 			}
 
-			ic.tools.RenderInfoMarkdown("- *Copying necessary files (remove container)*")
+			stepFinished(ic.tools, pimRef, "copy_files")
+
+			stepStarted(ic.tools, pimRef, "remove_container", "Copying necessary files (remove container)")
+
 			//Remove the Container
 			err = ic.tools.RemoveContainer(containerID, cli)
 
@@ -192,10 +265,10 @@ func (ic *UpgradeCommand) Run() error {
 				return err
 			}
 
-			ic.tools.RenderInfoMarkdown("***")
-			ic.tools.RenderInfoMarkdown(fmt.Sprintf("*%s* **successfully upgraded**", pim.Name))
-
+			stepFinished(ic.tools, pimRef, "remove_container")
 		}
+
+		ic.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: pimRef, Message: "successfully upgraded"})
 	} else {
 
 		//Get list of installed pims
@@ -241,16 +314,41 @@ func (ic *UpgradeCommand) Run() error {
 						continue
 					}
 
-					ic.tools.RenderInfoMarkdown(fmt.Sprintf("**Upgrading**: *%s* ", pim.Name+":"+ver.Version))
-					ic.tools.RenderInfoMarkdown(fmt.Sprintf("- *Pulling image %s*", version.Image))
+					//Reference to the pim used in the events
+					pimRef := pim.Name + ":" + ver.Version
+
+					//Pinned pims are skipped when upgrading all pims
+					if !ic.force && utils.IsHeld(holds, pim.Name, ver.Version) {
+						ic.tools.Emit(utils.Event{Type: utils.EventMessage, Pim: pimRef, Message: "Skipping " + pimRef + ", it is pinned"})
+						continue
+					}
+
+					ic.tools.Emit(utils.Event{Type: utils.EventStarted, Pim: pimRef, Message: "Upgrading"})
+
+					generation, imageID, err := ic.saveRollback(pim.Name, ver, pimRef, cli)
+
+					if err != nil {
+						return err
+					}
+
 					//Pull the image down from Docker Hub
+					stepStarted(ic.tools, pimRef, "pull_image", "Pulling image "+ver.Image)
+
 					err = ic.tools.PullImage(ver.Image, cli)
 
 					if err != nil {
 						return err
 					}
 
-					ic.tools.RenderInfoMarkdown("- *Updating pim directories*")
+					stepFinished(ic.tools, pimRef, "pull_image")
+
+					err = ic.keepRollback(pim.Name, ver, generation, imageID, cli)
+
+					if err != nil {
+						return err
+					}
+
+					stepStarted(ic.tools, pimRef, "update_directories", "Updating pim directories")
 
 					//Check the volumes and create the directories for them if they don't already exist
 					for _, vol := range ver.Volumes {
@@ -264,10 +362,13 @@ func (ic *UpgradeCommand) Run() error {
 						}
 					}
 
+					stepFinished(ic.tools, pimRef, "update_directories")
+
 					//Check and see if any files need to be copied from the container to one of the volumes on the host.
 					if len(ver.Copies) > 0 {
 
-						ic.tools.RenderInfoMarkdown("- *Copying necessary files (create container)*")
+						stepStarted(ic.tools, pimRef, "create_container", "Copying necessary files (create container)")
+
 						//Create the container so that we can copy the files over to the right places
 						containerID, err := ic.tools.CreateContainer(ver.Image, cli)
 
@@ -275,7 +376,10 @@ func (ic *UpgradeCommand) Run() error {
 							return err
 						}
 
-						ic.tools.RenderInfoMarkdown("- *Copying necessary files (copy files from container)*")
+						stepFinished(ic.tools, pimRef, "create_container")
+
+						stepStarted(ic.tools, pimRef, "copy_files", "Copying necessary files (copy files from container)")
+
 						//Copy the files from the container to the locations
 						for _, copy := range ver.Copies {
 							err = ic.tools.CopyFromContainer(copy.Source, pimDir+copy.Dest, containerID, cli, ic.cp)
@@ -285,7 +389,10 @@ func (ic *UpgradeCommand) Run() error {
 							}
 						}
 
-						ic.tools.RenderInfoMarkdown("- *Copying necessary files (remove container)*")
+						stepFinished(ic.tools, pimRef, "copy_files")
+
+						stepStarted(ic.tools, pimRef, "remove_container", "Copying necessary files (remove container)")
+
 						//Remove the Container
 						err = ic.tools.RemoveContainer(containerID, cli)
 
@@ -293,10 +400,11 @@ func (ic *UpgradeCommand) Run() error {
 							return err
 						}
 
-						ic.tools.RenderInfoMarkdown("***")
-						ic.tools.RenderInfoMarkdown(fmt.Sprintf("*%s* - **successfully upgraded**", pim.Name))
+						stepFinished(ic.tools, pimRef, "remove_container")
 					}
 
+					ic.tools.Emit(utils.Event{Type: utils.EventFinished, Pim: pimRef, Message: "successfully upgraded"})
+
 				}
 
 			}
@@ -306,3 +414,33 @@ func (ic *UpgradeCommand) Run() error {
 
 	return nil
 }
+
+//saveRollback - Keeps the image and data directories the pim has before it is upgraded so that the upgrade can be rolled back
+func (ic *UpgradeCommand) saveRollback(pimName string, version utils.Version, pimRef string, cli utils.Client) (string, string, error) {
+	if ic.config.RollbackGens <= 0 {
+		return "", "", nil
+	}
+
+	stepStarted(ic.tools, pimRef, "save_rollback", "Saving the current image and pim directories for rollback")
+
+	generation, imageID, err := saveRollback(ic.tools, ic.config, pimName, version, ic.now(), cli)
+
+	if err != nil {
+		return "", "", errors.New("Could not save the current image and pim directories for rollback: " + err.Error())
+	}
+
+	stepFinished(ic.tools, pimRef, "save_rollback")
+
+	return generation, imageID, nil
+}
+
+//keepRollback - Keeps the rollback saved before the upgrade only if the upgrade changed the image of the pim
+func (ic *UpgradeCommand) keepRollback(pimName string, version utils.Version, generation string, imageID string, cli utils.Client) error {
+	err := keepRollback(ic.tools, ic.config, pimName, version, generation, imageID, cli)
+
+	if err != nil {
+		return errors.New("Could not update the rollbacks kept for the pim: " + err.Error())
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package subcommands
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -97,23 +98,30 @@ func TestUpgradeFlow(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"UpgradeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveContainer",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -231,6 +239,9 @@ func TestUpgradeErrorAtGetHCLBody(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 	}
@@ -283,6 +294,9 @@ func TestUpgradeErrorAtParseBody(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -336,6 +350,9 @@ func TestUpgradeErrorAtImageExists(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -390,12 +407,15 @@ func TestUpgradeErrorAtPullImage(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
 	}
 
@@ -447,14 +467,18 @@ func TestUpgradeErrorAtUpgradeDir(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"UpgradeDir",
 	}
 
@@ -506,16 +530,21 @@ func TestUpgradeErrorAtCreateContainer(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"UpgradeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
 	}
 
@@ -567,18 +596,24 @@ func TestUpgradeErrorAtCopyFromContainer(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"UpgradeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
 	}
 
@@ -630,20 +665,27 @@ func TestUpgradeErrorAtRemoveContainer(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"UpgradeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveContainer",
 	}
 
@@ -694,6 +736,9 @@ func TestUpgradeImageNotExists(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -743,41 +788,51 @@ func TestUpgradeNoPackageWithTwoPacks(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
-		"RenderInfoMarkdown",
+		"Emit",
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"GetListOfInstalledPimConfigs",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"UpgradeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveContainer",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
-		//Repeat the cycle from GetHCLBody since we should be reading a new pim file
+		"Emit",
+		"Emit",
 		"GetHCLBody",
 		"ParseBody",
 		"ImageExists",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"PullImage",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"UpgradeDir",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CreateContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"CopyFromContainer",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 		"RemoveContainer",
-		"RenderInfoMarkdown",
-		"RenderInfoMarkdown",
+		"Emit",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
@@ -899,6 +954,9 @@ func TestUpgradeNonExistPackage(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -949,6 +1007,7 @@ func TestUpgradeNonExistVersion(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"ReadFile",
 		"FileExists",
 		"GetHCLBody",
 		"ParseBody",
@@ -999,7 +1058,10 @@ func TestUpgradeErrorAtGetListOfInstalledPimConfigs(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
-		"RenderInfoMarkdown",
+		"Emit",
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"GetListOfInstalledPimConfigs",
 	}
 
@@ -1049,6 +1111,9 @@ func TestUpgradeErrorAtPimConfigurationFileNotFound(t *testing.T) {
 
 	//Set a variable with the proper call stack and see if the call stack matches
 	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"ReadFile",
 		"FileExists",
 	}
 
@@ -1057,3 +1122,82 @@ func TestUpgradeErrorAtPimConfigurationFileNotFound(t *testing.T) {
 	}
 
 }
+
+//Test upgrading all pims skips the pinned pims
+func TestUpgradeNoArgsSkipsPinned(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+	mu.InstalledPims = []string{"python"}
+	mu.FileContents = map[string]string{mu.Conf.BaseDir + utils.HoldsFile: utils.HoldsHCL([]utils.Hold{{Pim: "python"}})}
+
+	ic := NewUpgradeCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := ic.Init([]string{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{"Emit", "Getwd", "FindFileUp", "ReadFile", "GetListOfInstalledPimConfigs", "GetHCLBody", "ParseBody", "ImageExists", "Emit"}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	expected := "Skipping python:latest, it is pinned"
+
+	if mu.Events[1].Message != expected {
+		t.Fatalf("Upgrade: Expected message: %s | Received: %s", expected, mu.Events[1].Message)
+	}
+}
+
+//Test upgrading a pinned pim is skipped unless it is forced
+func TestUpgradePinnedForce(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	mu.ImgExist = true
+	mu.FileContents = map[string]string{mu.Conf.BaseDir + utils.HoldsFile: utils.HoldsHCL([]utils.Hold{{Pim: "python", Version: "latest"}})}
+
+	ic := NewUpgradeCommand(mu, &utils.MockCopyTool{}, mu.Conf)
+
+	err := ic.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	var skip *SkipError
+
+	if !errors.As(err, &skip) {
+		t.Fatalf("Upgrade: Expected upgrading a pinned pim to be skipped | Received: %v", err)
+	}
+
+	if len(mu.PulledImgs) > 0 {
+		t.Fatal("Upgrade: Expected the pinned pim not to be upgraded")
+	}
+
+	err = ic.Init([]string{"--force", "python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ic.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mu.PulledImgs) != 1 {
+		t.Fatal("Upgrade: Expected the pinned pim to be upgraded when forced")
+	}
+}
@@ -32,14 +32,34 @@ func (vc *VersionCommand) Name() string {
 	return vc.fs.Name()
 }
 
-//Initialize the command, for this particular subcommand we should just do nothing
+//Description - Gets the description of the Sub-Command
+func (vc *VersionCommand) Description() string {
+	return "Show the version of packageless"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (vc *VersionCommand) Usage() string {
+	return "packageless version"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (vc *VersionCommand) Flags() *flag.FlagSet {
+	return vc.fs
+}
+
+//Initialize the command, for this particular subcommand we should just parse the flags
 func (vc *VersionCommand) Init(args []string) error {
-	return nil
+	return vc.fs.Parse(args)
 }
 
 //Run the command, this particular command should be a
 //simple print of the value of the version variable
 func (vc *VersionCommand) Run() error {
-	vc.tools.RenderInfoMarkdown(fmt.Sprintf("**Packageless Version**: *%s*", version))
+	vc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  "Packageless Version: " + version,
+		Data:     map[string]string{"version": version},
+		Markdown: fmt.Sprintf("**Packageless Version**: *%s*", version),
+	})
 	return nil
 }
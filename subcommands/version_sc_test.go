@@ -48,7 +48,7 @@ func TestVersionRun(t *testing.T) {
 	}
 
 	callStack := []string{
-		"RenderInfoMarkdown",
+		"Emit",
 	}
 
 	//If the call stack doesn't match the test fails
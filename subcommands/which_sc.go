@@ -0,0 +1,245 @@
+package subcommands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/docker/docker/client"
+	"github.com/everettraven/packageless/utils"
+)
+
+//Which Sub-Command Object
+type WhichCommand struct {
+	//FlagSet so that we can create a custom flag
+	fs *flag.FlagSet
+
+	//String for the name of the pim to explain
+	name string
+
+	tools utils.Tools
+
+	config utils.Config
+}
+
+//WhichResult - Everything that running a pim uses
+type WhichResult struct {
+	Pim         string   `json:"pim"`
+	Version     string   `json:"version"`
+	Explanation string   `json:"explanation"`
+	ConfigPath  string   `json:"config_path"`
+	Image       string   `json:"image"`
+	Digest      string   `json:"digest"`
+	Installed   bool     `json:"installed"`
+	Volumes     []string `json:"volumes"`
+	Ports       []string `json:"ports"`
+	Alias       string   `json:"alias"`
+	Command     string   `json:"command"`
+}
+
+//Instantiation method for a new WhichCommand
+func NewWhichCommand(tools utils.Tools, config utils.Config) *WhichCommand {
+	//Create a new WhichCommand and set the FlagSet
+	wc := &WhichCommand{
+		fs:     flag.NewFlagSet("which", flag.ContinueOnError),
+		tools:  tools,
+		config: config,
+	}
+
+	return wc
+}
+
+//Name - Gets the name of the Sub-Command
+func (wc *WhichCommand) Name() string {
+	return wc.fs.Name()
+}
+
+//Description - Gets the description of the Sub-Command
+func (wc *WhichCommand) Description() string {
+	return "Show what running a pim does without running it"
+}
+
+//Usage - Gets the usage of the Sub-Command
+func (wc *WhichCommand) Usage() string {
+	return "packageless which [pim[:version]]"
+}
+
+//Flags - Gets the flags of the Sub-Command
+func (wc *WhichCommand) Flags() *flag.FlagSet {
+	return wc.fs
+}
+
+//Init - Parses and Populates values of the Which subcommand
+func (wc *WhichCommand) Init(args []string) error {
+	err := wc.fs.Parse(args)
+
+	if err != nil {
+		return err
+	}
+
+	args = wc.fs.Args()
+
+	if len(args) <= 0 {
+		return errors.New("No pim name was found. You must include the name of the pim you wish to see how it is run.")
+	}
+
+	wc.name = args[0]
+
+	return nil
+}
+
+//Run - Runs the Which subcommand
+func (wc *WhichCommand) Run() error {
+	//The version is resolved the same way as the run subcommand does it
+	pimName, pimVersion, explanation, err := resolvePimName(wc.tools, wc.name)
+
+	if err != nil {
+		return err
+	}
+
+	//Create the Docker client
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+
+	plan, err := resolveRun(wc.tools, wc.config, pimName, pimVersion)
+
+	if err != nil {
+		return err
+	}
+
+	result := WhichResult{
+		Pim:         plan.pim.Name,
+		Version:     plan.version.Version,
+		Explanation: explanation,
+		ConfigPath:  plan.configPath,
+		Image:       plan.version.Image,
+		Volumes:     plan.options.Volumes,
+		Ports:       plan.options.Ports,
+	}
+
+	img, imgExist, err := wc.tools.FindImage(plan.version.Image, cli)
+
+	if err != nil {
+		return err
+	}
+
+	if imgExist {
+		result.Installed = true
+		result.Digest = imageDigest(img.ID, img.RepoDigests)
+	}
+
+	if wc.config.Alias {
+		//get the executable directory for checking the alias
+		ex, err := os.Executable()
+
+		if err != nil {
+			return err
+		}
+
+		executableDir := filepath.Dir(ex)
+
+		aliasName := plan.pim.Name
+
+		if plan.version.Version != "latest" {
+			aliasName = plan.pim.Name + ":" + plan.version.Version
+		}
+
+		//An alias that can not be checked, e.g. because of an unsupported shell, is reported as not set
+		var aliasExist bool
+
+		if runtime.GOOS == "windows" {
+			aliasExist, _ = wc.tools.AliasExistsWin(aliasName, executableDir)
+		} else {
+			aliasExist, _ = wc.tools.AliasExistsUnix(aliasName, executableDir)
+		}
+
+		if aliasExist {
+			result.Alias = utils.AliasLine(aliasName, executableDir)
+		}
+	}
+
+	command, err := utils.DockerRunCommand(plan.options)
+
+	if err != nil {
+		return err
+	}
+
+	//The arguments passed to the pim follow the image
+	result.Command = strings.TrimSpace(command)
+
+	wc.tools.Emit(utils.Event{
+		Type:     utils.EventResult,
+		Message:  whichText(result),
+		Data:     result,
+		Markdown: renderWhich(result),
+	})
+
+	return nil
+}
+
+//whichFields - Gets the fields of the result in the order they are shown
+func whichFields(result WhichResult) [][2]string {
+	installed := "no"
+	digest := result.Digest
+
+	if result.Installed {
+		installed = "yes"
+	} else {
+		digest = "not installed"
+	}
+
+	alias := result.Alias
+
+	if alias == "" {
+		alias = "no alias is set"
+	}
+
+	return [][2]string{
+		{"pim", result.Pim},
+		{"version", result.Version + ", " + result.Explanation},
+		{"pim configuration", result.ConfigPath},
+		{"image", result.Image},
+		{"installed", installed},
+		{"digest", digest},
+		{"volumes", strings.Join(result.Volumes, ", ")},
+		{"ports", strings.Join(result.Ports, ", ")},
+		{"alias", alias},
+	}
+}
+
+//renderWhich - Creates the markdown table describing how the pim is run, followed by the docker command
+func renderWhich(result WhichResult) string {
+	var sb strings.Builder
+
+	sb.WriteString("# " + result.Pim + ":" + result.Version + "\n")
+	sb.WriteString("| | |\n")
+	sb.WriteString("| --- | --- |\n")
+
+	for _, field := range whichFields(result) {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", field[0], strings.ReplaceAll(field[1], "|", "\\|")))
+	}
+
+	sb.WriteString("\n## Equivalent docker command\n")
+	sb.WriteString("```\n" + result.Command + "\n```\n")
+
+	return sb.String()
+}
+
+//whichText - Creates the plain text describing how the pim is run, one line per field
+func whichText(result WhichResult) string {
+	var lines []string
+
+	for _, field := range whichFields(result) {
+		lines = append(lines, field[0]+": "+field[1])
+	}
+
+	lines = append(lines, "command: "+result.Command)
+
+	return strings.Join(lines, "\n")
+}
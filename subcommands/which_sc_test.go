@@ -0,0 +1,113 @@
+package subcommands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/everettraven/packageless/utils"
+)
+
+func TestWhichName(t *testing.T) {
+	mu := utils.NewMockUtility()
+
+	wc := NewWhichCommand(mu, mu.Conf)
+
+	if wc.Name() != "which" {
+		t.Fatalf("The which subcommand's name should be: 'which' but was '%s'", wc.Name())
+	}
+}
+
+//Test everything running the pim uses is shown without running it
+func TestWhichFlow(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.ImgExist = true
+	mu.ImgSummary = types.ImageSummary{ID: "sha256:imageid", RepoDigests: []string{"packageless/python@sha256:digest"}}
+
+	wc := NewWhichCommand(mu, mu.Conf)
+
+	err := wc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = wc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callStack := []string{
+		"Getwd",
+		"FindFileUp",
+		"FileExists",
+		"GetHCLBody",
+		"ParseBody",
+		"PortAvailable",
+		"FindImage",
+		"AliasExists",
+		"Emit",
+	}
+
+	if !reflect.DeepEqual(callStack, mu.Calls) {
+		t.Fatalf("Call Stack does not match the expected call stack. Call Stack: %v | Expected Call Stack: %v", mu.Calls, callStack)
+	}
+
+	result := mu.Events[0].Data.(WhichResult)
+
+	pimDir := mu.Conf.BaseDir + mu.Conf.PimsDir
+
+	expected := WhichResult{
+		Pim:         "python",
+		Version:     "latest",
+		Explanation: result.Explanation,
+		ConfigPath:  mu.Conf.BaseDir + mu.Conf.PimsConfigDir + "python.hcl",
+		Image:       "packageless/python",
+		Digest:      "sha256:digest",
+		Installed:   true,
+		Volumes:     []string{pimDir + "a/path:/another/one"},
+		Ports:       []string{"3000:3000/tcp"},
+		Command:     result.Command,
+	}
+
+	if !reflect.DeepEqual(expected, result) {
+		t.Fatalf("Which: Expected: %v | Received: %v", expected, result)
+	}
+
+	//Whether a terminal is asked for depends on the terminal the tests run in
+	if !strings.HasPrefix(result.Command, "docker run -i") || !strings.Contains(result.Command, " --rm --name packageless-python-latest-") || !strings.HasSuffix(result.Command, " packageless/python") {
+		t.Fatalf("Which: Expected the docker command to run packageless/python | Received: %s", result.Command)
+	}
+}
+
+//Test the alias line in effect is shown when the alias is set
+func TestWhichAlias(t *testing.T) {
+	mu := utils.NewMockUtility()
+	mu.AliasExist = true
+
+	wc := NewWhichCommand(mu, mu.Conf)
+
+	err := wc.Init([]string{"python"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = wc.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := mu.Events[0].Data.(WhichResult)
+
+	if !strings.Contains(result.Alias, "packageless run python") && !strings.Contains(result.Alias, "packageless.exe run python") {
+		t.Fatalf("Which: Expected the alias line of python | Received: %s", result.Alias)
+	}
+
+	if result.Installed || result.Digest != "" {
+		t.Fatalf("Which: Expected python to not be installed | Received: %v", result)
+	}
+}
@@ -14,50 +14,13 @@ import (
 //AddAlias will add the alias for the package name specified
 func (u *Utility) AddAliasUnix(name string, ed string) error {
 
-	//get the home directory file path
-	home, err := os.UserHomeDir()
+	//Get the filepath for the correct shell rc file
+	path, err := shellRcPath()
 
 	if err != nil {
 		return err
 	}
 
-	//Get the shell PID
-	ppid := fmt.Sprint(os.Getppid())
-
-	//Create a list of commands to run and pipe together
-	var cmds []exec.Cmd
-	cmds = append(cmds, *exec.Command("ps", "-ef"))
-	cmds = append(cmds, *exec.Command("awk", "{print $2 \" \" $8}"))
-	cmds = append(cmds, *exec.Command("grep", ppid))
-	cmds = append(cmds, *exec.Command("awk", "{print $2}"))
-
-	var output []byte
-
-	//Loop through the commands and run them
-	for i, _ := range cmds {
-		cmds[i].Stdin = bytes.NewReader(output)
-
-		output, err = cmds[i].Output()
-
-		if err != nil {
-			return err
-		}
-	}
-
-	var path string
-
-	//Trim the output to get rid of any whitespace
-	shell := strings.TrimSpace(string(output[:]))
-
-	//Get the filepath for the correct shell rc file
-	if shell == "bash" || shell == "-bash" {
-		path = home + "/.bashrc"
-	} else if shell == "zsh" || shell == "-zsh" {
-		path = home + "/.zshrc"
-	} else {
-		return errors.New("Shell: " + shell + " is currently unsupported.")
-	}
-
 	//If run on linux lets modify the shell rc file to include the new aliases
 	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0755)
 
@@ -66,7 +29,7 @@ func (u *Utility) AddAliasUnix(name string, ed string) error {
 	}
 
 	//Create the alias and write it to the file
-	alias := "alias " + name + "=" + "\"" + ed + "/packageless run " + name + "\"" + "\n"
+	alias := aliasLineUnix(name, ed)
 
 	_, err = file.WriteString(alias)
 
@@ -82,50 +45,13 @@ func (u *Utility) AddAliasUnix(name string, ed string) error {
 //Remove Alias will remove the alias for the specified package name from the corresponding files
 func (u *Utility) RemoveAliasUnix(name string, ed string) error {
 
-	//get the home directory file path
-	home, err := os.UserHomeDir()
+	//Get the filepath for the correct shell rc file
+	path, err := shellRcPath()
 
 	if err != nil {
 		return err
 	}
 
-	//Get the shell PID
-	ppid := fmt.Sprint(os.Getppid())
-
-	//Create a list of commands to run and pipe them together
-	var cmds []exec.Cmd
-	cmds = append(cmds, *exec.Command("ps", "-ef"))
-	cmds = append(cmds, *exec.Command("awk", "{print $2 \" \" $8}"))
-	cmds = append(cmds, *exec.Command("grep", ppid))
-	cmds = append(cmds, *exec.Command("awk", "{print $2}"))
-
-	var output []byte
-
-	//Loop through the commands and run them
-	for i, _ := range cmds {
-		cmds[i].Stdin = bytes.NewReader(output)
-
-		output, err = cmds[i].Output()
-
-		if err != nil {
-			return err
-		}
-	}
-
-	var path string
-
-	//Trim the output whitespace
-	shell := strings.TrimSpace(string(output[:]))
-
-	//Get the filepath for the correct shell rc file
-	if shell == "bash" || shell == "-bash" {
-		path = home + "/.bashrc"
-	} else if shell == "zsh" || shell == "-zsh" {
-		path = home + "/.zshrc"
-	} else {
-		return errors.New("Shell: " + shell + " is currently unsupported.")
-	}
-
 	//Open the shell rc file
 	file, err := os.OpenFile(path, os.O_RDWR, 0755)
 
@@ -150,7 +76,7 @@ func (u *Utility) RemoveAliasUnix(name string, ed string) error {
 			return err
 		}
 
-		alias := "alias " + name + "=" + "\"" + ed + "/packageless run " + name + "\"" + "\n"
+		alias := aliasLineUnix(name, ed)
 
 		//if the line is the alias for this package dont include it in the new file
 		if line != alias {
@@ -182,3 +108,100 @@ func (u *Utility) RemoveAliasUnix(name string, ed string) error {
 
 	return nil
 }
+
+//AliasExistsUnix checks if the alias for the specified package name is set in the shell rc file
+func (u *Utility) AliasExistsUnix(name string, ed string) (bool, error) {
+	//Get the filepath for the correct shell rc file
+	path, err := shellRcPath()
+
+	if err != nil {
+		return false, err
+	}
+
+	return fileContainsLine(path, aliasLineUnix(name, ed))
+}
+
+//shellRcPath returns the path to the rc file of the shell that packageless was ran from
+func shellRcPath() (string, error) {
+	//get the home directory file path
+	home, err := os.UserHomeDir()
+
+	if err != nil {
+		return "", err
+	}
+
+	//Get the shell PID
+	ppid := fmt.Sprint(os.Getppid())
+
+	//Create a list of commands to run and pipe together
+	var cmds []exec.Cmd
+	cmds = append(cmds, *exec.Command("ps", "-ef"))
+	cmds = append(cmds, *exec.Command("awk", "{print $2 \" \" $8}"))
+	cmds = append(cmds, *exec.Command("grep", ppid))
+	cmds = append(cmds, *exec.Command("awk", "{print $2}"))
+
+	var output []byte
+
+	//Loop through the commands and run them
+	for i := range cmds {
+		cmds[i].Stdin = bytes.NewReader(output)
+
+		output, err = cmds[i].Output()
+
+		if err != nil {
+			return "", err
+		}
+	}
+
+	//Trim the output to get rid of any whitespace
+	shell := strings.TrimSpace(string(output[:]))
+
+	//Get the filepath for the correct shell rc file
+	if shell == "bash" || shell == "-bash" {
+		return home + "/.bashrc", nil
+	} else if shell == "zsh" || shell == "-zsh" {
+		return home + "/.zshrc", nil
+	}
+
+	return "", errors.New("Shell: " + shell + " is currently unsupported.")
+}
+
+//aliasLineUnix returns the line that is written to the shell rc file for the alias of the specified package name
+func aliasLineUnix(name string, ed string) string {
+	return "alias " + name + "=" + "\"" + ed + "/packageless run " + name + "\"" + "\n"
+}
+
+//fileContainsLine checks if the file at the specified path contains the exact line.
+//A file that does not exist does not contain the line
+func fileContainsLine(path string, line string) (bool, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	//Read the file line by line
+	for {
+		fileLine, err := reader.ReadString('\n')
+
+		if fileLine == line || (err == io.EOF && fileLine+"\n" == line) {
+			return true, nil
+		}
+
+		//Check for EOF
+		if err == io.EOF {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+	}
+}
@@ -26,7 +26,7 @@ func (u *Utility) AddAliasWin(name string, ed string) error {
 		return err
 	}
 
-	alias := "function " + name + "(){ " + ed + "\\packageless.exe run " + name + " $args }\n"
+	alias := aliasLineWin(name, ed)
 
 	_, err = file.WriteString(alias)
 
@@ -70,7 +70,7 @@ func (u *Utility) RemoveAliasWin(name string, ed string) error {
 			return err
 		}
 
-		alias := "function " + name + "(){ " + ed + "\\packageless.exe run " + name + " $args }\n"
+		alias := aliasLineWin(name, ed)
 
 		//if the line is the alias for this package dont include it in the new file
 		if line != alias {
@@ -102,3 +102,15 @@ func (u *Utility) RemoveAliasWin(name string, ed string) error {
 
 	return nil
 }
+
+//AliasExistsWin checks if the alias for the specified package name is set in the PowerShell profile
+func (u *Utility) AliasExistsWin(name string, ed string) (bool, error) {
+	pwshPath := os.Getenv("USERPROFILE") + "/Documents/WindowsPowerShell/"
+
+	return fileContainsLine(pwshPath+"Microsoft.PowerShell_profile.ps1", aliasLineWin(name, ed))
+}
+
+//aliasLineWin returns the line that is written to the PowerShell profile for the alias of the specified package name
+func aliasLineWin(name string, ed string) string {
+	return "function " + name + "(){ " + ed + "\\packageless.exe run " + name + " $args }\n"
+}
@@ -0,0 +1,310 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl2/gohcl"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+	"github.com/hashicorp/hcl2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+//Kinds of values that a configuration field can have
+const (
+	ConfigString = "string"
+	ConfigNumber = "number"
+	ConfigBool   = "bool"
+)
+
+//ConfigField describes a single value of the configuration file
+type ConfigField struct {
+	Name        string
+	Kind        string
+	Description string
+
+	//Paths are joined by appending to the value so it has to end with a separator
+	Dir bool
+
+	//Smallest and largest value of a number, a Max of 0 means there is no largest value
+	Min int
+	Max int
+}
+
+//ConfigFields lists every value of the configuration file in the order they are written
+var ConfigFields = []ConfigField{
+	{Name: "base_dir", Kind: ConfigString, Dir: true, Description: "The base directory that packageless downloads pim configurations to and creates the volumes of pims in"},
+	{Name: "start_port", Kind: ConfigNumber, Min: 1, Max: 65535, Description: "The port to start with when running containers that need a port exposed"},
+	{Name: "port_increment", Kind: ConfigNumber, Min: 1, Description: "The value to increment the start port by if the port is already taken"},
+	{Name: "alias", Kind: ConfigBool, Description: "Whether packageless should set an alias for the pims it installs"},
+	{Name: "repository_host", Kind: ConfigString, Dir: true, Description: "The repository that pim configurations are fetched from. Can be a URL, a file:// URL or a local directory"},
+	{Name: "pims_config_dir", Kind: ConfigString, Dir: true, Description: "The directory, relative to base_dir, that pim configurations are stored in"},
+	{Name: "pims_dir", Kind: ConfigString, Dir: true, Description: "The directory, relative to base_dir, that the volumes of pims are created in"},
+	{Name: "rollback_generations", Kind: ConfigNumber, Description: "The number of previous images and data directories kept for every pim version when upgrading, 0 disables rollbacks"},
+}
+
+//DefaultConfig returns the configuration that is used when no configuration file exists
+func DefaultConfig() Config {
+	return Config{
+		BaseDir:        "~/.packageless/",
+		StartPort:      3000,
+		PortInc:        1,
+		Alias:          false,
+		RepositoryHost: "https://raw.githubusercontent.com/everettraven/packageless-pims/main/pims/",
+		PimsConfigDir:  "pims_config/",
+		PimsDir:        "pims/",
+		RollbackGens:   2,
+	}
+}
+
+//FindConfigField returns the configuration field with the specified name
+func FindConfigField(name string) (ConfigField, bool) {
+	for _, field := range ConfigFields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+
+	return ConfigField{}, false
+}
+
+//GetConfigValue returns the value of the configuration field with the specified name as a string
+func GetConfigValue(config Config, name string) (string, error) {
+	switch name {
+	case "base_dir":
+		return config.BaseDir, nil
+	case "start_port":
+		return strconv.Itoa(config.StartPort), nil
+	case "port_increment":
+		return strconv.Itoa(config.PortInc), nil
+	case "alias":
+		return strconv.FormatBool(config.Alias), nil
+	case "repository_host":
+		return config.RepositoryHost, nil
+	case "pims_config_dir":
+		return config.PimsConfigDir, nil
+	case "pims_dir":
+		return config.PimsDir, nil
+	case "rollback_generations":
+		return strconv.Itoa(config.RollbackGens), nil
+	}
+
+	return "", errors.New("Unknown configuration value: " + name)
+}
+
+//ValidateConfigValue checks that the value is valid for the configuration field with the specified name
+func ValidateConfigValue(name string, value string) error {
+	field, ok := FindConfigField(name)
+
+	if !ok {
+		return errors.New("Unknown configuration value: " + name)
+	}
+
+	switch field.Kind {
+	case ConfigNumber:
+		number, err := strconv.Atoi(value)
+
+		if err != nil {
+			return fmt.Errorf("Invalid value for %s: %s is not a whole number", name, value)
+		}
+
+		if field.Max != 0 && (number < field.Min || number > field.Max) {
+			return fmt.Errorf("Invalid value for %s: %s must be between %d and %d", name, value, field.Min, field.Max)
+		}
+
+		if number < field.Min {
+			return fmt.Errorf("Invalid value for %s: %s must be at least %d", name, value, field.Min)
+		}
+	case ConfigBool:
+		_, err := strconv.ParseBool(value)
+
+		if err != nil {
+			return fmt.Errorf("Invalid value for %s: %s must be either true or false", name, value)
+		}
+	case ConfigString:
+		if value == "" {
+			return fmt.Errorf("Invalid value for %s: the value can not be empty", name)
+		}
+
+		if field.Dir && !strings.HasSuffix(value, "/") && !strings.HasSuffix(value, "\\") {
+			return fmt.Errorf("Invalid value for %s: %s must end with a /", name, value)
+		}
+	}
+
+	return nil
+}
+
+//SetConfigValue validates the value and sets the configuration field with the specified name to it
+func SetConfigValue(config *Config, name string, value string) error {
+	err := ValidateConfigValue(name, value)
+
+	if err != nil {
+		return err
+	}
+
+	switch name {
+	case "base_dir":
+		config.BaseDir = value
+	case "start_port":
+		config.StartPort, _ = strconv.Atoi(value)
+	case "port_increment":
+		config.PortInc, _ = strconv.Atoi(value)
+	case "alias":
+		config.Alias, _ = strconv.ParseBool(value)
+	case "repository_host":
+		config.RepositoryHost = value
+	case "pims_config_dir":
+		config.PimsConfigDir = value
+	case "pims_dir":
+		config.PimsDir = value
+	case "rollback_generations":
+		config.RollbackGens, _ = strconv.Atoi(value)
+	}
+
+	return nil
+}
+
+//LoadConfigHCL parses the contents of a configuration file on top of the default configuration.
+//Besides syntax and type errors, every value set in the file is validated and reported at its location in the file.
+func LoadConfigHCL(src []byte, filename string) (Config, hcl.Diagnostics) {
+	config := DefaultConfig()
+
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+
+	if diags.HasErrors() {
+		return config, diags
+	}
+
+	diags = append(diags, gohcl.DecodeBody(file.Body, nil, &config)...)
+
+	if diags.HasErrors() {
+		return config, diags
+	}
+
+	for name, rng := range ConfigLocations(src, filename) {
+		value, _ := GetConfigValue(config, name)
+
+		if err := ValidateConfigValue(name, value); err != nil {
+			subject := rng
+
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid configuration value",
+				Detail:   err.Error(),
+				Subject:  &subject,
+			})
+		}
+	}
+
+	return config, diags
+}
+
+//ConfigLocations returns the location in the configuration file of every configuration value that is set in it
+func ConfigLocations(src []byte, filename string) map[string]hcl.Range {
+	locations := make(map[string]hcl.Range)
+
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+
+	if diags.HasErrors() {
+		return locations
+	}
+
+	body, ok := file.Body.(*hclsyntax.Body)
+
+	if !ok {
+		return locations
+	}
+
+	for name, attr := range body.Attributes {
+		if _, ok := FindConfigField(name); ok {
+			locations[name] = attr.SrcRange
+		}
+	}
+
+	return locations
+}
+
+//SetConfigHCLValue sets a value in the contents of a configuration file, keeping the comments and formatting of the rest of the file.
+//The value is validated and an error is returned if the resulting configuration file would be invalid.
+func SetConfigHCLValue(src []byte, filename string, name string, value string) ([]byte, error) {
+	err := ValidateConfigValue(name, value)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, diags := hclwrite.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+
+	if diags.HasErrors() {
+		return nil, errors.New("The configuration file is invalid: " + FormatDiagnostics(diags))
+	}
+
+	field, _ := FindConfigField(name)
+
+	var val cty.Value
+
+	switch field.Kind {
+	case ConfigNumber:
+		number, _ := strconv.Atoi(value)
+		val = cty.NumberIntVal(int64(number))
+	case ConfigBool:
+		boolean, _ := strconv.ParseBool(value)
+		val = cty.BoolVal(boolean)
+	default:
+		val = cty.StringVal(value)
+	}
+
+	file.Body().SetAttributeValue(name, val)
+
+	out := file.Bytes()
+
+	//Make sure the whole file is still valid, e.g. other values might have been invalid already
+	_, diags = LoadConfigHCL(out, filename)
+
+	if diags.HasErrors() {
+		return nil, errors.New("The configuration file would be invalid: " + FormatDiagnostics(diags))
+	}
+
+	return out, nil
+}
+
+//FormatDiagnostics returns all the diagnostics as a single message, unlike Error which only describes the first one
+func FormatDiagnostics(diags hcl.Diagnostics) string {
+	var messages []string
+
+	for _, diag := range diags {
+		messages = append(messages, diag.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+//ConfigHCL returns the contents of a configuration file for the configuration, with a comment describing every value
+func ConfigHCL(config Config) string {
+	var sb strings.Builder
+
+	sb.WriteString("# packageless configuration\n")
+	sb.WriteString("# See https://everettraven.github.io/packageless/docs/configuration for more information\n")
+
+	for _, field := range ConfigFields {
+		value, _ := GetConfigValue(config, field.Name)
+
+		if field.Kind == ConfigString {
+			value = quoteHCLString(value)
+		}
+
+		sb.WriteString(fmt.Sprintf("\n# %s\n%s = %s\n", field.Description, field.Name, value))
+	}
+
+	return sb.String()
+}
+
+//quoteHCLString quotes and escapes a string so that it can be used as a literal in an HCL file
+func quoteHCLString(value string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n", "\r", "\\r", "\t", "\\t", "${", "$${", "%{", "%%{")
+
+	return "\"" + replacer.Replace(value) + "\""
+}
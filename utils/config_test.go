@@ -0,0 +1,223 @@
+package utils
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//Test the default configuration can be written to a file and parsed back
+func TestConfigHCLRoundTrip(t *testing.T) {
+	config := DefaultConfig()
+	config.RepositoryHost = "C:\\pims\\${repo}\\"
+
+	path := filepath.Join(t.TempDir(), "config.hcl")
+
+	err := ioutil.WriteFile(path, []byte(ConfigHCL(config)), 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	util := NewUtility()
+
+	body, err := util.GetHCLBody(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parseOut, err := util.ParseBody(body, Config{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(config, parseOut.(Config)) {
+		t.Fatalf("ConfigHCL: Parsed configuration does not match | Received: %v | Expected: %v", parseOut, config)
+	}
+}
+
+//Test getting and setting configuration values
+func TestSetConfigValue(t *testing.T) {
+	config := DefaultConfig()
+
+	values := map[string]string{
+		"base_dir":             "/opt/packageless/",
+		"start_port":           "4000",
+		"port_increment":       "2",
+		"alias":                "true",
+		"repository_host":      "file:///pims/",
+		"pims_config_dir":      "configs/",
+		"pims_dir":             "data/",
+		"rollback_generations": "5",
+	}
+
+	for name, value := range values {
+		err := SetConfigValue(&config, name, value)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := GetConfigValue(config, name)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != value {
+			t.Fatalf("SetConfigValue: Expected %s to be %s | Received: %s", name, value, got)
+		}
+	}
+}
+
+//Test invalid configuration values are rejected
+func TestSetConfigValueInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"start_port": "abc",
+		"alias":      "maybe",
+		"base_dir":   "/opt/packageless",
+		"pims_dir":   "",
+		"unknown":    "value",
+	}
+
+	for name, value := range invalid {
+		config := DefaultConfig()
+
+		err := SetConfigValue(&config, name, value)
+
+		if err == nil {
+			t.Fatalf("SetConfigValue: Expected an error when setting %s to %q but did not receive one", name, value)
+		}
+
+		if !reflect.DeepEqual(DefaultConfig(), config) {
+			t.Fatalf("SetConfigValue: The configuration should not change when the value is invalid | Received: %v", config)
+		}
+	}
+}
+
+//Test numbers are checked against the bounds of their configuration field
+func TestValidateConfigValueBounds(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		err   string
+	}{
+		{"start_port", "1", ""},
+		{"start_port", "65535", ""},
+		{"start_port", "0", "Invalid value for start_port: 0 must be between 1 and 65535"},
+		{"start_port", "65536", "Invalid value for start_port: 65536 must be between 1 and 65535"},
+		{"port_increment", "1", ""},
+		{"port_increment", "0", "Invalid value for port_increment: 0 must be at least 1"},
+		{"rollback_generations", "0", ""},
+		{"rollback_generations", "-1", "Invalid value for rollback_generations: -1 must be at least 0"},
+		{"rollback_generations", "two", "Invalid value for rollback_generations: two is not a whole number"},
+	}
+
+	for _, c := range cases {
+		err := ValidateConfigValue(c.name, c.value)
+
+		received := ""
+
+		if err != nil {
+			received = err.Error()
+		}
+
+		if received != c.err {
+			t.Fatalf("ValidateConfigValue(%s, %s): Expected error: %q | Received: %q", c.name, c.value, c.err, received)
+		}
+	}
+}
+
+//Test values that are not set in the configuration file use the defaults and invalid values are reported
+func TestLoadConfigHCL(t *testing.T) {
+	config, diags := LoadConfigHCL([]byte("start_port = 4000\n"), "config.hcl")
+
+	if diags.HasErrors() {
+		t.Fatal(FormatDiagnostics(diags))
+	}
+
+	expected := DefaultConfig()
+	expected.StartPort = 4000
+
+	if !reflect.DeepEqual(expected, config) {
+		t.Fatalf("LoadConfigHCL: Expected: %v | Received: %v", expected, config)
+	}
+
+	_, diags = LoadConfigHCL([]byte("alias = true\npims_dir = \"data\"\n"), "config.hcl")
+
+	if len(diags) != 1 {
+		t.Fatalf("LoadConfigHCL: Expected a single diagnostic | Received: %s", FormatDiagnostics(diags))
+	}
+
+	if diags[0].Subject == nil || diags[0].Subject.Start.Line != 2 {
+		t.Fatalf("LoadConfigHCL: Expected the diagnostic to be reported on line 2 | Received: %s", diags[0].Error())
+	}
+}
+
+//Test the locations of the values set in the configuration file are found
+func TestConfigLocations(t *testing.T) {
+	locations := ConfigLocations([]byte("# comment\nalias = true\n\nbase_dir = \"/opt/\"\nunknown = 1\n"), "config.hcl")
+
+	if len(locations) != 2 {
+		t.Fatalf("ConfigLocations: Expected 2 locations | Received: %v", locations)
+	}
+
+	if locations["alias"].Start.Line != 2 || locations["base_dir"].Start.Line != 4 {
+		t.Fatalf("ConfigLocations: Expected alias on line 2 and base_dir on line 4 | Received: %v", locations)
+	}
+}
+
+//Test setting a value keeps the comments of the configuration file
+func TestSetConfigHCLValue(t *testing.T) {
+	src := "# The base directory\nbase_dir = \"/opt/\" # trailing\n"
+
+	out, err := SetConfigHCLValue([]byte(src), "config.hcl", "start_port", "4000")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "# The base directory\nbase_dir   = \"/opt/\" # trailing\nstart_port = 4000\n"
+
+	if string(out) != expected {
+		t.Fatalf("SetConfigHCLValue: Expected: %q | Received: %q", expected, string(out))
+	}
+
+	out, err = SetConfigHCLValue(out, "config.hcl", "base_dir", "/srv/")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, diags := LoadConfigHCL(out, "config.hcl")
+
+	if diags.HasErrors() {
+		t.Fatal(FormatDiagnostics(diags))
+	}
+
+	if config.BaseDir != "/srv/" || config.StartPort != 4000 {
+		t.Fatalf("SetConfigHCLValue: Expected base_dir /srv/ and start_port 4000 | Received: %v", config)
+	}
+}
+
+//Test setting a value refuses to produce an invalid configuration file
+func TestSetConfigHCLValueInvalid(t *testing.T) {
+	_, err := SetConfigHCLValue([]byte("alias = true\n"), "config.hcl", "alias", "maybe")
+
+	exErr := "Invalid value for alias: maybe must be either true or false"
+
+	if err == nil || err.Error() != exErr {
+		t.Fatalf("SetConfigHCLValue: Expected to have error: %s | Received: %v", exErr, err)
+	}
+
+	//The file is already invalid so setting another value should fail
+	_, err = SetConfigHCLValue([]byte("pims_dir = \"data\"\n"), "config.hcl", "alias", "true")
+
+	if err == nil || !strings.HasPrefix(err.Error(), "The configuration file would be invalid: ") {
+		t.Fatalf("SetConfigHCLValue: Expected the configuration file to be reported as invalid | Received: %v", err)
+	}
+}
@@ -2,23 +2,64 @@ package utils
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
-	"runtime"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
 )
 
+//Labels that packageless sets on the containers it runs so that they can be found later
+const (
+	//Name of the pim the container runs
+	LabelPim = "packageless.pim"
+
+	//Version of the pim the container runs
+	LabelVersion = "packageless.version"
+
+	//Time the container was started at, in RFC 3339 format
+	LabelStarted = "packageless.started"
+
+	//Directory on the host that is mounted as the working directory of the pim, if any
+	LabelWorkdir = "packageless.workdir"
+
+	//Set on the containers that are only created to copy files out of an image
+	LabelTemporary = "packageless.temporary"
+)
+
+//ImagePrefix is the prefix of the images of the pims in the packageless organization in Docker Hub
+const ImagePrefix = "packageless/"
+
+//RollbackPrefix is the prefix of the tags that keep the images a pim had before it was upgraded
+const RollbackPrefix = "packageless-rollback/"
+
+//RollbackTag returns the tag that keeps the image a version of a pim had before the upgrade of the generation
+func RollbackTag(pim string, version string, generation string) string {
+	return RollbackPrefix + pim + ":" + version + "-" + generation
+}
+
 //PullImage - This function pulls a Docker Image from the packageless organization in Docker Hub
 func (u *Utility) PullImage(name string, cli Client) error {
 	//Set the context
 	ctx := context.Background()
 
+	u.Verbosef("pulling image %s", name)
+
 	//Begin pulling the image
 	out, err := cli.ImagePull(ctx, name, types.ImagePullOptions{})
 
@@ -38,6 +79,21 @@ func (u *Utility) PullImage(name string, cli Client) error {
 	return nil
 }
 
+//DockerVersion - Function to check that the Docker daemon can be reached and get its version
+func (u *Utility) DockerVersion(cli Client) (types.Version, error) {
+	//Set the context
+	ctx := context.Background()
+
+	//Make sure the daemon responds before asking for its version
+	_, err := cli.Ping(ctx)
+
+	if err != nil {
+		return types.Version{}, err
+	}
+
+	return cli.ServerVersion(ctx)
+}
+
 //ImageExists - Function to check and see if Docker has the image downloaded
 func (u *Utility) ImageExists(imageID string, cli Client) (bool, error) {
 	//Create a context and get a list of images on the system
@@ -66,11 +122,37 @@ func (u *Utility) ImageExists(imageID string, cli Client) (bool, error) {
 	return false, nil
 }
 
+//FindImage - Function to get the summary Docker has for a downloaded image. The boolean returned is false if the image is not downloaded
+func (u *Utility) FindImage(imageID string, cli Client) (types.ImageSummary, bool, error) {
+	//Create a context and get a list of images on the system
+	ctx := context.Background()
+	images, err := cli.ImageList(ctx, types.ImageListOptions{})
+
+	//Check for errors
+	if err != nil {
+		return types.ImageSummary{}, false, err
+	}
+
+	//Loop through all the images and check if a match is found
+	for _, image := range images {
+		for _, tag := range image.RepoTags {
+			if tag == imageID {
+				return image, true, nil
+			}
+		}
+	}
+
+	//No match found
+	return types.ImageSummary{}, false, nil
+}
+
 //CreateContainer - Create a Docker Container from a Docker Image. Returns the containerID and any errors
 func (u *Utility) CreateContainer(image string, cli Client) (string, error) {
 	//Create the context and create the container
 	ctx := context.Background()
-	container, err := cli.ContainerCreate(ctx, &container.Config{Image: image, Cmd: []string{"bash"}}, nil, nil, nil, "")
+	//The label lets prune remove the container if it is left behind, e.g. when copying fails
+	config := &container.Config{Image: image, Cmd: []string{"bash"}, Labels: map[string]string{LabelTemporary: "true"}}
+	container, err := cli.ContainerCreate(ctx, config, nil, nil, nil, "")
 
 	//Check for errors
 	if err != nil {
@@ -83,6 +165,8 @@ func (u *Utility) CreateContainer(image string, cli Client) (string, error) {
 
 //CopyFromContainer will copy files from within a Docker Container to the source location on the host
 func (u *Utility) CopyFromContainer(source string, dest string, containerID string, cli Client, cp Copier) error {
+	u.Verbosef("copying %s from the container to %s", source, dest)
+
 	//Set the context and begin copying from the container
 	ctx := context.Background()
 	reader, _, err := cli.CopyFromContainer(ctx, containerID, source)
@@ -121,23 +205,284 @@ func (u *Utility) RemoveContainer(containerID string, cli Client) error {
 	return nil
 }
 
-//RunContainer - Runs a container for the specified package
-func (u *Utility) RunContainer(image string, ports []string, volumes []string, containerName string, args []string) (string, error) {
-	// Build the command to run the docker container
-	var cmdStr string
-	var cmd *exec.Cmd
+//Characters that can not be used in the name of a container
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
 
-	cmdStr += "docker "
+//ContainerName - Creates a name for a container running the pim that is unique to this run of packageless,
+//so that the same pim can be run several times at once
+func ContainerName(pim string, version string) string {
+	suffix := make([]byte, 3)
 
-	// add the base docker command details
-	cmdStr += "run -it --rm --name " + containerName + " "
+	//The process ID tells the runs of packageless on one host apart, the suffix keeps the names unique when hosts share a Docker Engine
+	rand.Read(suffix)
 
-	// add the ports to the command
-	for _, port := range ports {
-		cmdStr += "-p " + port + " "
+	name := "packageless-" + pim + "-" + version + "-" + strconv.Itoa(os.Getpid()) + "-" + hex.EncodeToString(suffix)
+
+	return invalidNameChars.ReplaceAllString(name, "-")
+}
+
+//RunOptions - How the container of a pim is run
+type RunOptions struct {
+	Image string
+
+	//Name of the container
+	Name string
+
+	//Port mappings in the host:container format
+	Ports []string
+
+	//Volumes in the source:target[:options] format
+	Volumes []string
+
+	Labels map[string]string
+
+	//Arguments passed to the pim, they replace the command of the image
+	Args []string
+
+	//Whether the container is given a terminal, when nil it is given one only if packageless runs in a terminal
+	TTY *bool
+}
+
+//RunContainer - Runs the container of a pim attached to the terminal and removes it once it exits, returning its exit code.
+//The arguments are passed to the container as they are, without being interpreted by a shell
+func (u *Utility) RunContainer(opts RunOptions, cli Client) (int, error) {
+	ctx := context.Background()
+
+	exposedPorts, portBindings, err := nat.ParsePortSpecs(opts.Ports)
+
+	if err != nil {
+		return 1, err
 	}
 
-	// add the volumes to the command
+	binds, err := volumeBinds(opts.Volumes)
+
+	if err != nil {
+		return 1, err
+	}
+
+	stdin, stdout, stderr := u.stdio()
+
+	stdinFd, stdinTerminal := terminalFd(stdin)
+	stdoutFd, stdoutTerminal := terminalFd(stdout)
+
+	tty := containerTTY(opts.TTY, stdinTerminal, stdoutTerminal)
+
+	//The input is always streamed to the container, when it is a pipe the container reads it until it is closed
+	config := &container.Config{
+		Image:        opts.Image,
+		Labels:       opts.Labels,
+		ExposedPorts: exposedPorts,
+		Tty:          tty,
+		OpenStdin:    true,
+		StdinOnce:    true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+
+	//Without arguments the command of the image is run
+	if len(opts.Args) > 0 {
+		config.Cmd = strslice.StrSlice(opts.Args)
+	}
+
+	//The Docker Engine removes the container once it exits, even when packageless is killed before it can remove it
+	hostConfig := &container.HostConfig{
+		PortBindings: portBindings,
+		Binds:        binds,
+		AutoRemove:   true,
+	}
+
+	u.Verbosef("creating container %s from %s", opts.Name, opts.Image)
+
+	created, err := cli.ContainerCreate(ctx, config, hostConfig, nil, nil, opts.Name)
+
+	if err != nil {
+		return 1, err
+	}
+
+	//The container is removed if it is never started
+	defer cli.ContainerRemove(context.Background(), created.ID, types.ContainerRemoveOptions{Force: true})
+
+	//Signals are caught from now on so that packageless is not stopped by them without removing the container
+	signals := make(chan os.Signal, 16)
+	signal.Notify(signals, append(signalList(), resizeSignals...)...)
+
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+
+	attach, err := cli.ContainerAttach(ctx, created.ID, types.ContainerAttachOptions{Stream: true, Stdin: true, Stdout: true, Stderr: true})
+
+	if err != nil {
+		return 1, err
+	}
+
+	defer attach.Close()
+
+	//Wait before starting the container so that a container that exits right away is not missed
+	statusCh, errCh := cli.ContainerWait(ctx, created.ID, container.WaitConditionRemoved)
+
+	//Every key press is sent to the container as it is, the terminal of the container handles them
+	if tty && stdinTerminal {
+		restore, err := makeRaw(stdinFd)
+
+		if err != nil {
+			return 1, err
+		}
+
+		defer restore()
+	}
+
+	outputDone := make(chan error, 1)
+
+	go func() {
+		//Without a terminal the output of the container is multiplexed, its standard output and error are kept separate
+		if !tty {
+			_, err := stdcopy.StdCopy(stdout, stderr, attach.Reader)
+			outputDone <- err
+			return
+		}
+
+		_, err := io.Copy(stdout, attach.Reader)
+		outputDone <- err
+	}()
+
+	go func() {
+		io.Copy(attach.Conn, stdin)
+		attach.CloseWrite()
+	}()
+
+	u.Verbosef("starting container %s", created.ID)
+
+	err = cli.ContainerStart(ctx, created.ID, types.ContainerStartOptions{})
+
+	if err != nil {
+		return 1, err
+	}
+
+	resize := func() {}
+
+	//The terminal of the container has the size of the terminal packageless runs in
+	if tty && stdoutTerminal {
+		resize = func() {
+			u.resizeContainer(cli, created.ID, stdoutFd)
+		}
+
+		resize()
+	}
+
+	go u.forwardSignals(cli, created.ID, signals, resize)
+
+	select {
+	case status := <-statusCh:
+		//Make sure everything the container wrote has been shown
+		<-outputDone
+
+		if status.Error != nil {
+			return 1, errors.New(status.Error.Message)
+		}
+
+		return int(status.StatusCode), nil
+	case err := <-errCh:
+		return 1, err
+	}
+}
+
+//signalList - Gets the signals that are sent on to the containers
+func signalList() []os.Signal {
+	var list []os.Signal
+
+	for sig := range forwardedSignals {
+		list = append(list, sig)
+	}
+
+	return list
+}
+
+//forwardSignals - Sends the signals packageless receives to the container, and resizes the terminal of the container
+//when the terminal of packageless is resized, until the channel of signals is closed
+func (u *Utility) forwardSignals(cli Client, containerID string, signals <-chan os.Signal, resize func()) {
+	for sig := range signals {
+		name, ok := forwardedSignals[sig]
+
+		if !ok {
+			resize()
+			continue
+		}
+
+		u.Verbosef("sending %s to container %s", name, containerID)
+
+		//The container may have exited already, in which case there is nothing to send the signal to
+		err := cli.ContainerKill(context.Background(), containerID, name)
+
+		if err != nil {
+			u.Verbosef("could not send %s to container %s: %s", name, containerID, err)
+		}
+	}
+}
+
+//resizeContainer - Sets the size of the terminal of the container to the size of the terminal
+func (u *Utility) resizeContainer(cli Client, containerID string, fd uintptr) {
+	height, width, err := terminalSize(fd)
+
+	if err != nil {
+		return
+	}
+
+	err = cli.ContainerResize(context.Background(), containerID, types.ResizeOptions{Height: height, Width: width})
+
+	if err != nil {
+		u.Verbosef("could not resize the terminal of container %s: %s", containerID, err)
+	}
+}
+
+//stdio - Gets the standard input, output and error the containers are attached to
+func (u *Utility) stdio() (io.Reader, io.Writer, io.Writer) {
+	var stdin io.Reader = os.Stdin
+	var stdout io.Writer = os.Stdout
+	var stderr io.Writer = os.Stderr
+
+	if u.stdin != nil {
+		stdin = u.stdin
+	}
+
+	if u.stdout != nil {
+		stdout = u.stdout
+	}
+
+	if u.stderr != nil {
+		stderr = u.stderr
+	}
+
+	return stdin, stdout, stderr
+}
+
+//containerTTY - Checks if the container is given a terminal. Unless it is set, pims used in pipes and scripts
+//are not given a terminal so that their input and output are not changed by it
+func containerTTY(tty *bool, stdinTerminal bool, stdoutTerminal bool) bool {
+	if tty != nil {
+		return *tty
+	}
+
+	return stdinTerminal && stdoutTerminal
+}
+
+//terminalFd - Gets the file descriptor of the standard input or output if it is a terminal
+func terminalFd(stdio interface{}) (uintptr, bool) {
+	file, ok := stdio.(*os.File)
+
+	if !ok || !isTerminal(file.Fd()) {
+		return 0, false
+	}
+
+	return file.Fd(), true
+}
+
+//volumeBinds - Converts the volumes to binds with absolute source paths
+func volumeBinds(volumes []string) ([]string, error) {
+	var binds []string
+
 	for _, vol := range volumes {
 		// Define variables needed for volume parsing
 		var source string
@@ -148,8 +493,8 @@ func (u *Utility) RunContainer(image string, ports []string, volumes []string, c
 			vol = strings.TrimLeft(vol, "C:")
 		}
 
-		if vErr := u.validateRunContainerVolume(vol); vErr != nil {
-			return "", vErr
+		if vErr := validateRunContainerVolume(vol); vErr != nil {
+			return nil, vErr
 		}
 
 		splitVol := strings.Split(vol, ":")
@@ -166,46 +511,84 @@ func (u *Utility) RunContainer(image string, ports []string, volumes []string, c
 		source, err := filepath.Abs(source)
 
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		cmdStr += "-v " + source + ":" + target + options + " "
+		binds = append(binds, source+":"+target+options)
 	}
 
-	// add the image name and the arguments
-	cmdStr += image + " "
+	return binds, nil
+}
 
-	//Combine the arguments into one string
-	argStr := strings.Join(args, " ")
+//DockerRunCommand - Builds the docker run command line that is equivalent to running the container with RunContainer
+//from the current terminal. Every argument is quoted so that the command line can be pasted into a shell
+func DockerRunCommand(opts RunOptions) (string, error) {
+	// add the base docker command details, asking for a terminal only when RunContainer would give the container one
+	interactive := "-it"
 
-	//add the arguments
-	cmdStr += argStr
+	_, stdinTerminal := terminalFd(os.Stdin)
+	_, stdoutTerminal := terminalFd(os.Stdout)
 
-	//Instantiate the command based on OS
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("powershell", cmdStr)
-	} else {
-		cmd = exec.Command("bash", "-c", cmdStr)
+	if !containerTTY(opts.TTY, stdinTerminal, stdoutTerminal) {
+		interactive = "-i"
 	}
 
-	//Connect the command stderr, stdout, and stdin to the OS stderr, stdout, stdin
-	cmd.Stderr = os.Stderr
-	cmd.Stdout = os.Stdout
-	cmd.Stdin = os.Stdin
+	cmd := []string{"docker", "run", interactive, "--rm", "--name", opts.Name}
 
-	//Run the command
-	err := cmd.Run()
+	// add the labels to the command, sorted so that the command is always the same
+	var labelKeys []string
+
+	for key := range opts.Labels {
+		labelKeys = append(labelKeys, key)
+	}
+
+	sort.Strings(labelKeys)
+
+	for _, key := range labelKeys {
+		cmd = append(cmd, "--label", key+"="+opts.Labels[key])
+	}
+
+	// add the ports to the command
+	for _, port := range opts.Ports {
+		cmd = append(cmd, "-p", port)
+	}
+
+	binds, err := volumeBinds(opts.Volumes)
 
-	//Check for errors
 	if err != nil {
-		return cmdStr, err
+		return "", err
+	}
+
+	// add the volumes to the command
+	for _, bind := range binds {
+		cmd = append(cmd, "-v", bind)
 	}
 
-	return cmdStr, nil
+	// add the image name and the arguments
+	cmd = append(cmd, opts.Image)
+	cmd = append(cmd, opts.Args...)
 
+	for i, arg := range cmd {
+		cmd[i] = shellQuote(arg)
+	}
+
+	return strings.Join(cmd, " "), nil
 }
 
-func (u *Utility) validateRunContainerVolume(volume string) error {
+//shellUnsafe matches the characters that have to be quoted for a POSIX shell to read an argument as is
+var shellUnsafe = regexp.MustCompile(`[^A-Za-z0-9_@%+=:,./-]`)
+
+//shellQuote - Quotes an argument for a POSIX shell, arguments that do not need quoting are returned as they are
+func shellQuote(arg string) string {
+	if arg != "" && !shellUnsafe.MatchString(arg) {
+		return arg
+	}
+
+	//A single quote can not appear inside single quotes, so it is closed, escaped and opened again
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+func validateRunContainerVolume(volume string) error {
 	splitVolume := strings.Split(volume, ":")
 
 	if len(splitVolume) != 2 && len(splitVolume) != 3 {
@@ -215,11 +598,77 @@ func (u *Utility) validateRunContainerVolume(volume string) error {
 	return nil
 }
 
+//ListPimContainers lists the running containers that were started by packageless
+func (u *Utility) ListPimContainers(cli Client) ([]types.Container, error) {
+	ctx := context.Background()
+
+	return cli.ContainerList(ctx, types.ContainerListOptions{Filters: filters.NewArgs(filters.Arg("label", LabelPim))})
+}
+
+//ListTemporaryContainers lists the containers, running or not, that packageless created to copy files out of an image
+func (u *Utility) ListTemporaryContainers(cli Client) ([]types.Container, error) {
+	ctx := context.Background()
+
+	return cli.ContainerList(ctx, types.ContainerListOptions{All: true, Filters: filters.NewArgs(filters.Arg("label", LabelTemporary))})
+}
+
+//ListImages lists every image that Docker has downloaded
+func (u *Utility) ListImages(cli Client) ([]types.ImageSummary, error) {
+	ctx := context.Background()
+
+	return cli.ImageList(ctx, types.ImageListOptions{})
+}
+
+//StopContainer stops a container gracefully, killing it if it does not stop within the timeout
+func (u *Utility) StopContainer(containerID string, timeout time.Duration, cli Client) error {
+	//Give the Docker daemon some time on top of the timeout to kill the container itself
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+10*time.Second)
+	defer cancel()
+
+	u.Verbosef("stopping container %s", containerID)
+
+	err := cli.ContainerStop(ctx, containerID, &timeout)
+
+	if err == nil {
+		return nil
+	}
+
+	u.Verbosef("could not stop container %s, killing it: %s", containerID, err)
+
+	return cli.ContainerKill(context.Background(), containerID, "SIGKILL")
+}
+
+//RemoteDigest gets the digest of the manifest that the registry currently has for the image, without pulling it
+func (u *Utility) RemoteDigest(image string, cli Client) (string, error) {
+	ctx := context.Background()
+
+	u.Verbosef("inspecting image %s in the registry", image)
+
+	inspect, err := cli.DistributionInspect(ctx, image, "")
+
+	if err != nil {
+		return "", err
+	}
+
+	return inspect.Descriptor.Digest.String(), nil
+}
+
+//TagImage adds the target tag to the source image, moving the tag if another image already has it
+func (u *Utility) TagImage(source string, target string, cli Client) error {
+	ctx := context.Background()
+
+	u.Verbosef("tagging image %s as %s", source, target)
+
+	return cli.ImageTag(ctx, source, target)
+}
+
 //RemoveImage removes the image with the given name from local Docker
 func (u *Utility) RemoveImage(image string, cli Client) error {
 	//Create the context and search for the image in the list of images
 	ctx := context.Background()
 
+	u.Verbosef("removing image %s", image)
+
 	//Remove the image
 	_, err := cli.ImageRemove(ctx, image, types.ImageRemoveOptions{Force: true})
 
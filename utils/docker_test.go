@@ -1,16 +1,25 @@
 package utils
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 //Unit Tests
@@ -205,6 +214,148 @@ func TestImageExistContinueOnEmptyRepoTag(t *testing.T) {
 
 }
 
+//Test FindImage Function when the image does exist
+func TestFindImageDoesExist(t *testing.T) {
+	//Create the Mock Docker Client
+	dm := NewDockMock()
+
+	//Set the image for testing
+	img := "image:faketag"
+
+	//Set the return images array in the Mock Docker Client
+	dm.ILRet = []types.ImageSummary{
+		{
+			ID:       "sha256:other",
+			RepoTags: []string{"other:faketag"},
+		},
+		{
+			ID:       "sha256:image",
+			RepoTags: []string{"image:othertag", "image:faketag"},
+			Size:     1024,
+		},
+	}
+
+	//Create a new utility
+	util := NewUtility()
+
+	//Find the image
+	summary, exists, err := util.FindImage(img, dm)
+
+	//If an error occurs, the test fails
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//The image should exist in this case
+	if !exists {
+		t.Fatal("FindImage: Image should exist, but it does not.")
+	}
+
+	//The summary should be for the matching image
+	if summary.ID != "sha256:image" {
+		t.Fatal("FindImage: Expected Image ID: sha256:image | Received: " + summary.ID)
+	}
+}
+
+//Test FindImage Function when the image does not exist
+func TestFindImageDoesNotExist(t *testing.T) {
+	//Create the Mock Docker Client
+	dm := NewDockMock()
+
+	//Set the return images array in the Mock Docker Client
+	dm.ILRet = []types.ImageSummary{
+		{
+			RepoTags: []string{},
+		},
+	}
+
+	//Create a new utility
+	util := NewUtility()
+
+	//Find the image
+	_, exists, err := util.FindImage("image", dm)
+
+	//If an error occurs, the test fails
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//The image should not exist in this case
+	if exists {
+		t.Fatal("FindImage: Image should not exist, but it does.")
+	}
+}
+
+//Test the FindImage Function when an error occurs
+func TestFindImageError(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+
+	//Set the error at and error message
+	dm.ErrorAt = "ImageList"
+	dm.ErrorMsg = "Testing error at ImageList()"
+
+	//Create a new utility object
+	util := NewUtility()
+
+	//Find the image
+	_, _, err := util.FindImage("image", dm)
+
+	//Error should occur
+	if err == nil {
+		t.Fatal("FindImage: Expected to receive an error, but did not receive one.")
+	}
+
+	if err.Error() != dm.ErrorMsg {
+		t.Fatal("FindImage: Expected Error: " + dm.ErrorMsg + " | Received Error: " + err.Error())
+	}
+}
+
+//Test DockerVersion Function
+func TestDockerVersion(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+
+	dm.SVRet = types.Version{Version: "20.10.7", APIVersion: "1.41"}
+
+	//Create a new utility object
+	util := NewUtility()
+
+	version, err := util.DockerVersion(dm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if version.Version != "20.10.7" || version.APIVersion != "1.41" {
+		t.Fatalf("DockerVersion: Expected version 20.10.7 with API version 1.41 | Received: %s with API version %s", version.Version, version.APIVersion)
+	}
+}
+
+//Test DockerVersion Function when the daemon can not be reached
+func TestDockerVersionError(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+
+	//Set the error at and error message
+	dm.ErrorAt = "Ping"
+	dm.ErrorMsg = "Testing error at Ping()"
+
+	//Create a new utility object
+	util := NewUtility()
+
+	_, err := util.DockerVersion(dm)
+
+	//Error should occur
+	if err == nil {
+		t.Fatal("DockerVersion: Expected to receive an error, but did not receive one.")
+	}
+
+	if err.Error() != dm.ErrorMsg {
+		t.Fatal("DockerVersion: Expected Error: " + dm.ErrorMsg + " | Received Error: " + err.Error())
+	}
+}
+
 //Test CreateContainer Function
 func TestCreateContainer(t *testing.T) {
 	//Create the Mock Docker Client
@@ -247,6 +398,11 @@ func TestCreateContainer(t *testing.T) {
 	if dm.CCConfig.Cmd[0] != cmd[0] {
 		t.Fatalf("CreateContainer: Expected Container Config Cmd: %v | Received: %v", cmd, dm.CCConfig.Cmd)
 	}
+
+	//The container should be labelled so that prune can remove it if it is left behind
+	if dm.CCConfig.Labels[LabelTemporary] != "true" {
+		t.Fatalf("CreateContainer: Expected the %s label | Received: %v", LabelTemporary, dm.CCConfig.Labels)
+	}
 }
 
 //Test CreateContainer Function with an error
@@ -470,8 +626,8 @@ func TestRemoveContainerError(t *testing.T) {
 	}
 }
 
-//Test RunContainer Function without arguments
-func TestRunContainerNoArgs(t *testing.T) {
+//Test DockerRunCommand Function without arguments
+func TestDockerRunCommandNoArgs(t *testing.T) {
 	//Ser the image to be run
 	image := "image"
 
@@ -492,28 +648,27 @@ func TestRunContainerNoArgs(t *testing.T) {
 	//Set the container name
 	cName := "test"
 
-	//Set the empty args
-	args := []string{}
-
 	//Set the expected command
 	paths := strings.Split(volumes[0], ":")
-	exCmd := "docker run -it --rm --name " + cName + " -p " + ports[0] + " -v " + absPath + ":" + paths[1] + " " + image + " "
+	exCmd := "docker run -it --rm --name " + cName + " -p " + ports[0] + " -v " + absPath + ":" + paths[1] + " " + image
 
-	//Create the util tool
-	util := NewUtility()
+	tty := true
+
+	cmd, err := DockerRunCommand(RunOptions{Image: image, Name: cName, Ports: ports, Volumes: volumes, TTY: &tty})
 
-	//Run the RunContainer function and ignore any errors since we just want to make sure the cmd is built properly
-	cmd, _ := util.RunContainer(image, ports, volumes, cName, args)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	//Returned cmd should equal the expected one
 	if cmd != exCmd {
-		t.Fatalf("RunContainer: Expected CMD: %s | Received CMD: %s", exCmd, cmd)
+		t.Fatalf("DockerRunCommand: Expected CMD: %s | Received CMD: %s", exCmd, cmd)
 	}
 
 }
 
-//Test RunContainer Function with arguments
-func TestRunContainerWithArgs(t *testing.T) {
+//Test DockerRunCommand Function with arguments
+func TestDockerRunCommandWithArgs(t *testing.T) {
 	//Ser the image to be run
 	image := "image"
 
@@ -534,7 +689,7 @@ func TestRunContainerWithArgs(t *testing.T) {
 	//Set the container name
 	cName := "test"
 
-	//Set the empty args
+	//Set the args
 	args := []string{"some", "arguments"}
 
 	argStr := strings.Join(args, " ")
@@ -543,53 +698,70 @@ func TestRunContainerWithArgs(t *testing.T) {
 	paths := strings.Split(volumes[0], ":")
 	exCmd := "docker run -it --rm --name " + cName + " -p " + ports[0] + " -v " + absPath + ":" + paths[1] + " " + image + " " + argStr
 
-	//Create the util tool
-	util := NewUtility()
+	tty := true
 
-	//Run the RunContainer function and ignore any errors since we just want to make sure the cmd is built properly
-	cmd, _ := util.RunContainer(image, ports, volumes, cName, args)
+	cmd, err := DockerRunCommand(RunOptions{Image: image, Name: cName, Ports: ports, Volumes: volumes, Args: args, TTY: &tty})
+
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	//Returned cmd should equal the expected one
 	if cmd != exCmd {
-		t.Fatalf("RunContainer: Expected CMD: %s | Received CMD: %s", exCmd, cmd)
+		t.Fatalf("DockerRunCommand: Expected CMD: %s | Received CMD: %s", exCmd, cmd)
 	}
 
 }
 
 func TestRunContainerReturnErrorWhenSplitVolumeIs1(t *testing.T) {
-	//Set the image to be run
-	image := "image"
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
 
-	//Set the ports
-	ports := []string{"3000:3000"}
+	//Create the util tool
+	util := NewUtility()
+
+	//Run the RunContainer function and assert the error
+	exErr := errors.New("utils: Invalid split volume of length 1")
+	_, err := util.RunContainer(RunOptions{Image: "image", Name: "test", Ports: []string{"3000:3000"}, Volumes: []string{"/path1"}}, dm)
+	if err == nil || err.Error() != exErr.Error() {
+		t.Fatalf("RunContainer: Expected err: %s | Received err: %v", exErr.Error(), err)
+	}
+
+	if dm.CCName != "" {
+		t.Fatalf("RunContainer: Expected no container to be created for an invalid volume | Received: %s", dm.CCName)
+	}
+}
 
+func TestRunContainerBindsWhenSplitVolumeIs2(t *testing.T) {
 	//Set the volumes
-	volumes := []string{"/path1"}
+	path, err := filepath.Abs("/path1")
 
-	//Set the container name
-	cName := "test"
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	//Set the empty args
-	args := []string{}
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
 
 	//Create the util tool
 	util := NewUtility()
+	util.stdin = strings.NewReader("")
+	util.stdout = ioutil.Discard
 
-	//Run the RunContainer function and assert the error
-	exErr := errors.New("utils: Invalid split volume of length 1")
-	_, err := util.RunContainer(image, ports, volumes, cName, args)
-	if err.Error() != exErr.Error() {
-		t.Fatalf("RunContainer: Expected err: %s | Received err: %s", exErr.Error(), err.Error())
+	_, err = util.RunContainer(RunOptions{Image: "image", Name: "test", Volumes: []string{"/path1:/path2"}}, dm)
+
+	if err != nil {
+		t.Fatal(err)
 	}
-}
 
-func TestRunContainerReturnCorrectCmdStrWhenSplitVolumeIs2(t *testing.T) {
-	//Set the image to be run
-	image := "image"
+	binds := []string{path + ":/path2"}
 
-	//Set the ports
-	ports := []string{"3000:3000"}
+	if !reflect.DeepEqual(binds, dm.CCHostConfig.Binds) {
+		t.Fatalf("RunContainer: Expected Binds: %v | Received Binds: %v", binds, dm.CCHostConfig.Binds)
+	}
+}
 
+func TestRunContainerBindsWhenSplitVolumeIs3(t *testing.T) {
 	//Set the volumes
 	path, err := filepath.Abs("/path1")
 
@@ -597,99 +769,282 @@ func TestRunContainerReturnCorrectCmdStrWhenSplitVolumeIs2(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	volumes := []string{"/path1:/path2"}
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
 
-	//Set the container name
-	cName := "test"
+	//Create the util tool
+	util := NewUtility()
+	util.stdin = strings.NewReader("")
+	util.stdout = ioutil.Discard
 
-	//Set the empty args
-	args := []string{}
+	_, err = util.RunContainer(RunOptions{Image: "image", Name: "test", Volumes: []string{"/path1:/path2:ro"}}, dm)
 
-	//Set the expected command
-	paths := strings.Split(volumes[0], ":")
-	exCmd := "docker run -it --rm --name " + cName + " -p " + ports[0] + " -v " + path + ":" + paths[1] + " " + image + " "
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	binds := []string{path + ":/path2:ro"}
+
+	if !reflect.DeepEqual(binds, dm.CCHostConfig.Binds) {
+		t.Fatalf("RunContainer: Expected Binds: %v | Received Binds: %v", binds, dm.CCHostConfig.Binds)
+	}
+}
+
+func TestRunContainerReturnErrorWhenSplitVolumeIs4(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+
+	//Create the util tool
+	util := NewUtility()
+
+	//Run the RunContainer function and assert the error
+	exErr := errors.New("utils: Invalid split volume of length 4")
+	_, err := util.RunContainer(RunOptions{Image: "image", Name: "test", Volumes: []string{"/path1:/path2:/path3:/path4"}}, dm)
+	if err == nil || err.Error() != exErr.Error() {
+		t.Fatalf("RunContainer: Expected err: %s | Received err: %v", exErr.Error(), err)
+	}
+}
+
+//Test RunContainer Function creates, attaches to, starts, waits for and removes the container
+func TestRunContainer(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+	dm.CCRet = container.ContainerCreateCreatedBody{ID: "fakeID"}
+	dm.CAOutput = "output of the pim"
+
+	var stdout bytes.Buffer
 
 	//Create the util tool
 	util := NewUtility()
+	util.stdin = strings.NewReader("")
+	util.stdout = &stdout
+
+	labels := map[string]string{LabelPim: "python"}
 
-	//Run the RunContainer function and ignore any errors since we just want to make sure the cmdStr is built properly
-	cmdStr, err := util.RunContainer(image, ports, volumes, cName, args)
+	_, err := util.RunContainer(RunOptions{Image: "image", Name: "test", Ports: []string{"3000:8080"}, Labels: labels}, dm)
 
-	//When running the docker command, it is expected to return an
-	//error of exit status 1 because no TTY was mounted. If a different error occurs, fail.
-	if err != nil && err.Error() != "exit status 1" {
+	if err != nil {
 		t.Fatal(err)
 	}
 
-	if cmdStr != exCmd {
-		t.Fatalf("RunContainer: Expected CMD: %s | Received CMD: %s", exCmd, cmdStr)
+	if dm.CCName != "test" || dm.CCConfig.Image != "image" || !reflect.DeepEqual(labels, dm.CCConfig.Labels) {
+		t.Fatalf("RunContainer: Expected container test to be created from image with the labels | Received: %s %v", dm.CCName, dm.CCConfig)
+	}
+
+	//Without arguments the command of the image is run
+	if dm.CCConfig.Cmd != nil {
+		t.Fatalf("RunContainer: Expected the command of the image to be run | Received: %v", dm.CCConfig.Cmd)
+	}
+
+	bindings := dm.CCHostConfig.PortBindings[nat.Port("8080/tcp")]
+
+	if len(bindings) != 1 || bindings[0].HostPort != "3000" {
+		t.Fatalf("RunContainer: Expected the container port 8080 to be bound to 3000 | Received: %v", dm.CCHostConfig.PortBindings)
+	}
+
+	if dm.CAContainer != "fakeID" || dm.CStartContainer != "fakeID" || dm.CWContainer != "fakeID" || dm.CRContainer != "fakeID" {
+		t.Fatalf("RunContainer: Expected the container to be attached to, started, waited for and removed | Received: %s %s %s %s", dm.CAContainer, dm.CStartContainer, dm.CWContainer, dm.CRContainer)
+	}
+
+	//The Docker Engine removes the container even if packageless is killed
+	if !dm.CCHostConfig.AutoRemove || dm.CWCondition != container.WaitConditionRemoved {
+		t.Fatalf("RunContainer: Expected the container to be removed once it exits | Received: %v %s", dm.CCHostConfig.AutoRemove, dm.CWCondition)
+	}
+
+	if stdout.String() != dm.CAOutput {
+		t.Fatalf("RunContainer: Expected the output of the container: %s | Received: %s", dm.CAOutput, stdout.String())
 	}
 }
 
-func TestRunContainerReturnCorrectCmdStrWhenSplitVolumeIs3(t *testing.T) {
-	//Set the image to be run
-	image := "image"
+//Test RunContainer Function does not give the container a terminal when the input is piped, keeping its output and errors separate
+func TestRunContainerWithoutTerminal(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+	dm.CAOutput = "{\"key\": \"value\"}"
+	dm.CAErrOutput = "a warning"
 
-	//Set the ports
-	ports := []string{"3000:3000"}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
 
-	//Set the volumes
-	path, err := filepath.Abs("/path1")
+	//Create the util tool
+	util := NewUtility()
+	util.stdin = strings.NewReader("{\"key\": \"value\"}")
+	util.stdout = &stdout
+	util.stderr = &stderr
+
+	_, err := util.RunContainer(RunOptions{Image: "image", Name: "test", Args: []string{"."}}, dm)
 
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	volumes := []string{"/path1:/path2:/path3"}
+	if dm.CCConfig.Tty || !dm.CCConfig.OpenStdin || !dm.CAOptions.Stdin {
+		t.Fatalf("RunContainer: Expected the input to be streamed to a container without a terminal | Received: %v", dm.CCConfig)
+	}
 
-	//Set the container name
-	cName := "test"
+	if stdout.String() != dm.CAOutput || stderr.String() != dm.CAErrOutput {
+		t.Fatalf("RunContainer: Expected the output: %s and the errors: %s | Received output: %s | Received errors: %s", dm.CAOutput, dm.CAErrOutput, stdout.String(), stderr.String())
+	}
+}
 
-	//Set the empty args
-	args := []string{}
+//Test RunContainer Function gives the container a terminal when it is asked for, even if packageless does not run in one
+func TestRunContainerForceTTY(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+	dm.CAOutput = "output of the pim"
 
-	//Set the expected command
-	paths := strings.Split(volumes[0], ":")
-	exCmd := "docker run -it --rm --name " + cName + " -p " + ports[0] + " -v " + path + ":" + paths[1] + ":" + paths[2] + " " + image + " "
+	var stdout bytes.Buffer
 
 	//Create the util tool
 	util := NewUtility()
+	util.stdin = strings.NewReader("")
+	util.stdout = &stdout
+
+	tty := true
 
-	//Run the RunContainer function and ignore any errors since we just want to make sure the cmdStr is built properly
-	cmdStr, err := util.RunContainer(image, ports, volumes, cName, args)
+	_, err := util.RunContainer(RunOptions{Image: "image", Name: "test", TTY: &tty}, dm)
 
-	if err != nil && err.Error() != "exit status 1" {
+	if err != nil {
 		t.Fatal(err)
 	}
-	if cmdStr != exCmd {
-		t.Fatalf("RunContainer: Expected CMD: %s | Received CMD: %s", exCmd, cmdStr)
+
+	if !dm.CCConfig.Tty {
+		t.Fatal("RunContainer: Expected the container to be given a terminal")
+	}
+
+	//The output of a container with a terminal is not multiplexed
+	if stdout.String() != dm.CAOutput {
+		t.Fatalf("RunContainer: Expected the output of the container: %s | Received: %s", dm.CAOutput, stdout.String())
 	}
 }
 
-func TestRunContainerReturnErrorWhenSplitVolumeIs4(t *testing.T) {
-	//Set the image to be run
-	image := "image"
+//Test RunContainer Function passes the arguments to the container without interpreting them
+func TestRunContainerTrickyArgs(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
 
-	//Set the ports
-	ports := []string{"3000:3000"}
+	//Create the util tool
+	util := NewUtility()
+	util.stdin = strings.NewReader("")
+	util.stdout = ioutil.Discard
 
-	//Set the volumes
-	volumes := []string{"/path1:/path2:/path3:/path4"}
+	args := []string{"-c", "print('hello world')", "two words", "$HOME", "a;rm -rf /", "`id`", "\"it's\"", "&&", "|", "*", ""}
 
-	//Set the container name
-	cName := "test"
+	_, err := util.RunContainer(RunOptions{Image: "image", Name: "test", Args: args}, dm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(strslice.StrSlice(args), dm.CCConfig.Cmd) {
+		t.Fatalf("RunContainer: Expected the arguments to be passed as they are: %q | Received: %q", args, dm.CCConfig.Cmd)
+	}
+}
 
-	//Set the empty args
-	args := []string{}
+//Test RunContainer Function returns the exit code of the container
+func TestRunContainerExitStatus(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+	dm.CCRet = container.ContainerCreateCreatedBody{ID: "fakeID"}
+	dm.CWStatusCode = 3
 
 	//Create the util tool
 	util := NewUtility()
+	util.stdin = strings.NewReader("")
+	util.stdout = ioutil.Discard
 
-	//Run the RunContainer function and assert the error
-	exErr := errors.New("utils: Invalid split volume of length 4")
-	_, err := util.RunContainer(image, ports, volumes, cName, args)
-	if err.Error() != exErr.Error() {
-		t.Fatalf("RunContainer: Expected err: %s | Received err: %s", exErr.Error(), err.Error())
+	exitCode, err := util.RunContainer(RunOptions{Image: "image", Name: "test"}, dm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exitCode != 3 {
+		t.Fatalf("RunContainer: Expected exit code: 3 | Received exit code: %d", exitCode)
+	}
+}
+
+//Test forwardSignals Function sends the signals to the container and resizes it for the other signals
+func TestForwardSignals(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+
+	//Create the util tool
+	util := NewUtility()
+
+	signals := make(chan os.Signal, 2)
+	signals <- os.Interrupt
+	signals <- resizeSignal{}
+	close(signals)
+
+	resized := false
+
+	util.forwardSignals(dm, "fakeID", signals, func() { resized = true })
+
+	if dm.CKContainer != "fakeID" || dm.CKSignal != "SIGINT" {
+		t.Fatalf("forwardSignals: Expected SIGINT to be sent to the container | Received: %s %s", dm.CKSignal, dm.CKContainer)
+	}
+
+	if !resized {
+		t.Fatal("forwardSignals: Expected the container to be resized")
+	}
+}
+
+//Test forwardSignals Function keeps forwarding signals when the container has already exited
+func TestForwardSignalsErrorAtContainerKill(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+	dm.ErrorAt = "ContainerKill"
+
+	//Create the util tool
+	util := NewUtility()
+
+	signals := make(chan os.Signal, 2)
+	signals <- os.Interrupt
+	signals <- resizeSignal{}
+	close(signals)
+
+	resized := false
+
+	util.forwardSignals(dm, "fakeID", signals, func() { resized = true })
+
+	if !resized {
+		t.Fatal("forwardSignals: Expected the signals after the failed one to be handled")
+	}
+}
+
+//resizeSignal - Stand-in for the signal received when the terminal is resized, which not every operating system has
+type resizeSignal struct{}
+
+//String - Gets the name of the signal
+func (resizeSignal) String() string {
+	return "resize"
+}
+
+//Signal - Marks the type as a signal
+func (resizeSignal) Signal() {}
+
+//Test RunContainer Function removes the container when it can not be started
+func TestRunContainerErrorAtContainerStart(t *testing.T) {
+	//Create a new Docker Client Mock
+	dm := NewDockMock()
+	dm.CCRet = container.ContainerCreateCreatedBody{ID: "fakeID"}
+	dm.ErrorAt = "ContainerStart"
+	dm.ErrorMsg = "Testing error at ContainerStart()"
+
+	//Create the util tool
+	util := NewUtility()
+	util.stdin = strings.NewReader("")
+	util.stdout = ioutil.Discard
+
+	_, err := util.RunContainer(RunOptions{Image: "image", Name: "test"}, dm)
+
+	if err == nil || err.Error() != dm.ErrorMsg {
+		t.Fatalf("RunContainer: Expected err: %s | Received err: %v", dm.ErrorMsg, err)
+	}
+
+	if dm.CRContainer != "fakeID" {
+		t.Fatalf("RunContainer: Expected the container to be removed | Received: %s", dm.CRContainer)
 	}
 }
 
@@ -906,3 +1261,277 @@ func TestDocker_Integration(t *testing.T) {
 		t.Fatalf("Docker: Expected Image %s to not exist but it did", img)
 	}
 }
+
+//Test ContainerName Function creates unique names that Docker accepts
+func TestContainerName(t *testing.T) {
+	first := ContainerName("python", "3.7")
+	second := ContainerName("python", "3.7")
+
+	if first == second {
+		t.Fatalf("ContainerName: Expected unique names | Received: %s and %s", first, second)
+	}
+
+	prefix := "packageless-python-3.7-" + strconv.Itoa(os.Getpid()) + "-"
+
+	if !strings.HasPrefix(first, prefix) {
+		t.Fatalf("ContainerName: Expected the name to start with: %s | Received: %s", prefix, first)
+	}
+
+	//Versions can contain characters that are not allowed in names
+	if name := ContainerName("node", "16/alpine+1"); !strings.HasPrefix(name, "packageless-node-16-alpine-1-") {
+		t.Fatalf("ContainerName: Expected the invalid characters to be replaced | Received: %s", name)
+	}
+}
+
+//Test DockerRunCommand Function does not ask for a terminal when the container is not given one
+func TestDockerRunCommandNoTTY(t *testing.T) {
+	tty := false
+
+	exCmd := "docker run -i --rm --name test image"
+
+	cmd, err := DockerRunCommand(RunOptions{Image: "image", Name: "test", TTY: &tty})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Returned cmd should equal the expected one
+	if cmd != exCmd {
+		t.Fatalf("DockerRunCommand: Expected CMD: %s | Received CMD: %s", exCmd, cmd)
+	}
+}
+
+//Test DockerRunCommand Function adds the labels to the command
+func TestDockerRunCommandWithLabels(t *testing.T) {
+	//Set the container name
+	cName := "test"
+
+	labels := map[string]string{
+		LabelVersion: "latest",
+		LabelPim:     "python",
+	}
+
+	//The labels should be sorted by their key
+	exCmd := "docker run -it --rm --name " + cName + " --label packageless.pim=python --label packageless.version=latest image"
+
+	tty := true
+
+	cmd, err := DockerRunCommand(RunOptions{Image: "image", Name: cName, Labels: labels, TTY: &tty})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Returned cmd should equal the expected one
+	if cmd != exCmd {
+		t.Fatalf("DockerRunCommand: Expected CMD: %s | Received CMD: %s", exCmd, cmd)
+	}
+}
+
+//Test ListPimContainers Function only lists the containers with the pim label
+func TestListPimContainers(t *testing.T) {
+	//Create the Mock Docker Client
+	dm := NewDockMock()
+	dm.CLRet = []types.Container{{ID: "fake"}}
+
+	//Create the util
+	util := NewUtility()
+
+	containers, err := util.ListPimContainers(dm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(dm.CLRet, containers) {
+		t.Fatalf("ListPimContainers: Expected Containers: %v | Received: %v", dm.CLRet, containers)
+	}
+
+	if !dm.CLOptions.Filters.ExactMatch("label", LabelPim) {
+		t.Fatalf("ListPimContainers: Expected the containers to be filtered by the %s label", LabelPim)
+	}
+}
+
+//Test StopContainer Function stops the container gracefully with the timeout
+func TestStopContainer(t *testing.T) {
+	//Create the Mock Docker Client
+	dm := NewDockMock()
+
+	//Create the util
+	util := NewUtility()
+
+	err := util.StopContainer("fake", 5*time.Second, dm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dm.CSContainer != "fake" || dm.CSTimeout == nil || *dm.CSTimeout != 5*time.Second {
+		t.Fatalf("StopContainer: Expected the container fake to be stopped with a 5s timeout | Received: %s %v", dm.CSContainer, dm.CSTimeout)
+	}
+
+	//The container should not have been killed
+	if dm.CKContainer != "" {
+		t.Fatalf("StopContainer: Expected the container to not be killed | Received: %s", dm.CKContainer)
+	}
+}
+
+//Test StopContainer Function kills the container when it can not be stopped
+func TestStopContainerKillsWhenStopFails(t *testing.T) {
+	//Create the Mock Docker Client
+	dm := NewDockMock()
+
+	//Set the error at and error message
+	dm.ErrorAt = "ContainerStop"
+	dm.ErrorMsg = "Testing error at ContainerStop()"
+
+	//Create the util
+	util := NewUtility()
+
+	err := util.StopContainer("fake", time.Second, dm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dm.CKContainer != "fake" || dm.CKSignal != "SIGKILL" {
+		t.Fatalf("StopContainer: Expected the container fake to be killed with SIGKILL | Received: %s %s", dm.CKContainer, dm.CKSignal)
+	}
+}
+
+//Test getting the digest the registry has for an image
+func TestRemoteDigest(t *testing.T) {
+	//Create the Mock Docker Client
+	dm := NewDockMock()
+	dm.DIRet = registry.DistributionInspect{Descriptor: specs.Descriptor{Digest: "sha256:remote"}}
+
+	//Create the util
+	util := NewUtility()
+
+	digest, err := util.RemoteDigest("packageless/python:latest", dm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if digest != "sha256:remote" {
+		t.Fatalf("RemoteDigest: Expected digest: sha256:remote | Received: %s", digest)
+	}
+
+	if dm.DIImage != "packageless/python:latest" {
+		t.Fatalf("RemoteDigest: Expected the image packageless/python:latest to be inspected | Received: %s", dm.DIImage)
+	}
+}
+
+//Test getting an error when inspecting the image in the registry
+func TestRemoteDigestErrorAtDistributionInspect(t *testing.T) {
+	//Create the Mock Docker Client
+	dm := NewDockMock()
+
+	//Set the error at and error message
+	dm.ErrorAt = "DistributionInspect"
+	dm.ErrorMsg = "Testing error at DistributionInspect()"
+
+	//Create the util
+	util := NewUtility()
+
+	_, err := util.RemoteDigest("packageless/python:latest", dm)
+
+	if err == nil || err.Error() != dm.ErrorMsg {
+		t.Fatalf("RemoteDigest: Expected error: %s | Received: %v", dm.ErrorMsg, err)
+	}
+}
+
+//Test adding a tag to an image
+func TestTagImage(t *testing.T) {
+	//Create the Mock Docker Client
+	dm := NewDockMock()
+
+	//Create the util
+	util := NewUtility()
+
+	err := util.TagImage("sha256:current", "packageless-rollback/python:latest-20260301T120000Z", dm)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dm.ITSource != "sha256:current" || dm.ITTarget != "packageless-rollback/python:latest-20260301T120000Z" {
+		t.Fatalf("TagImage: Expected the image to be tagged | Received: %s %s", dm.ITSource, dm.ITTarget)
+	}
+}
+
+//Test the docker run command line is built with the labels, ports, volumes and arguments
+func TestDockerRunCommand(t *testing.T) {
+	path, err := filepath.Abs("/path1")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := map[string]string{LabelVersion: "latest", LabelPim: "python"}
+
+	exCmd := "docker run -it --rm --name python --label " + LabelPim + "=python --label " + LabelVersion + "=latest -p 3000:3000 -v " + path + ":/path2 image --version"
+
+	tty := true
+
+	cmdStr, err := DockerRunCommand(RunOptions{Image: "image", Name: "python", Ports: []string{"3000:3000"}, Volumes: []string{"/path1:/path2"}, Labels: labels, Args: []string{"--version"}, TTY: &tty})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cmdStr != exCmd {
+		t.Fatalf("DockerRunCommand: Expected CMD: %s | Received CMD: %s", exCmd, cmdStr)
+	}
+}
+
+//Test every argument of the docker run command line that a shell would interpret is quoted
+func TestDockerRunCommandQuoting(t *testing.T) {
+	tty := false
+
+	labels := map[string]string{LabelWorkdir: "/home/me/it's here"}
+
+	exCmd := `docker run -i --rm --name python --label 'packageless.workdir=/home/me/it'\''s here' image -c 'print("a b")' '' '$HOME' '*'`
+
+	cmdStr, err := DockerRunCommand(RunOptions{Image: "image", Name: "python", Labels: labels, Args: []string{"-c", `print("a b")`, "", "$HOME", "*"}, TTY: &tty})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cmdStr != exCmd {
+		t.Fatalf("DockerRunCommand: Expected CMD: %s | Received CMD: %s", exCmd, cmdStr)
+	}
+}
+
+//Test the docker run command line asks for a terminal only when RunContainer would give the container one
+func TestDockerRunCommandDetectsTTY(t *testing.T) {
+	_, stdinTerminal := terminalFd(os.Stdin)
+	_, stdoutTerminal := terminalFd(os.Stdout)
+
+	exCmd := "docker run -i --rm --name test image"
+
+	if stdinTerminal && stdoutTerminal {
+		exCmd = "docker run -it --rm --name test image"
+	}
+
+	cmd, err := DockerRunCommand(RunOptions{Image: "image", Name: "test"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cmd != exCmd {
+		t.Fatalf("DockerRunCommand: Expected CMD: %s | Received CMD: %s", exCmd, cmd)
+	}
+}
+
+//Test the docker run command line is not built for an invalid volume
+func TestDockerRunCommandInvalidVolume(t *testing.T) {
+	_, err := DockerRunCommand(RunOptions{Image: "image", Name: "python", Volumes: []string{"/path1"}})
+
+	if err == nil {
+		t.Fatal("DockerRunCommand: Expected an error for the invalid volume")
+	}
+}
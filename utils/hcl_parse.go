@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"reflect"
 
 	"github.com/hashicorp/hcl2/gohcl"
 	"github.com/hashicorp/hcl2/hcl"
@@ -27,12 +28,45 @@ type PackageImage struct {
 	Versions []Version `hcl:"version,block"`
 }
 
+//Port object to parse the port block in the package list, a port of the container that is published on the host
+type Port struct {
+	Container string `hcl:"container,attr" json:"container"`
+
+	//Protocol of the port, tcp if it is not set
+	Protocol string `hcl:"protocol,optional" json:"protocol"`
+
+	//Host port the container port is published on, a free one is found from the start_port when it is not set
+	Host int `hcl:"host,optional" json:"host"`
+}
+
 type Version struct {
 	Version string   `hcl:"version,label"`
 	Image   string   `hcl:"image,attr"`
 	Volumes []Volume `hcl:"volume,block"`
 	Copies  []*Copy  `hcl:"copy,block"`
-	Port    string   `hcl:"port,optional"`
+
+	//A single container port, the way pim configurations declared their port before port blocks
+	Port  string `hcl:"port,optional"`
+	Ports []Port `hcl:"port,block"`
+}
+
+//PublishedPorts - Gets every port of the container that is published on the host, with the protocol set
+func (v Version) PublishedPorts() []Port {
+	var ports []Port
+
+	if v.Port != "" {
+		ports = append(ports, Port{Container: v.Port})
+	}
+
+	ports = append(ports, v.Ports...)
+
+	for i := range ports {
+		if ports[i].Protocol == "" {
+			ports[i].Protocol = "tcp"
+		}
+	}
+
+	return ports
 }
 
 //PackageHCLUtil object to contain a list of packages and all their attributes after the parsing of the package list
@@ -40,15 +74,30 @@ type PimHCLUtil struct {
 	Pims []PackageImage `hcl:"pim,block"`
 }
 
+//IndexPim object to parse the pim block in the repository index
+type IndexPim struct {
+	Name        string   `hcl:"name,label"`
+	Description string   `hcl:"description,optional"`
+	Versions    []string `hcl:"versions,optional"`
+	Checksum    string   `hcl:"checksum,optional"`
+}
+
+//RepositoryIndex object to contain the list of pims that are available in a repository
+type RepositoryIndex struct {
+	Pims []IndexPim `hcl:"pim,block"`
+}
+
 //Config object to contain the configuration details
+//Values that are not set in the configuration file keep their default value
 type Config struct {
-	BaseDir        string `hcl:"base_dir,attr"`
-	StartPort      int    `hcl:"start_port,attr"`
-	PortInc        int    `hcl:"port_increment,attr"`
-	Alias          bool   `hcl:"alias,attr"`
-	RepositoryHost string `hcl:"repository_host,attr"`
-	PimsConfigDir  string `hcl:"pims_config_dir,attr"`
-	PimsDir        string `hcl:"pims_dir,attr"`
+	BaseDir        string `hcl:"base_dir,optional"`
+	StartPort      int    `hcl:"start_port,optional"`
+	PortInc        int    `hcl:"port_increment,optional"`
+	Alias          bool   `hcl:"alias,optional"`
+	RepositoryHost string `hcl:"repository_host,optional"`
+	PimsConfigDir  string `hcl:"pims_config_dir,optional"`
+	PimsDir        string `hcl:"pims_dir,optional"`
+	RollbackGens   int    `hcl:"rollback_generations,optional"`
 }
 
 //Parse function to parse the HCL body given
@@ -73,8 +122,8 @@ func (u *Utility) ParseBody(body hcl.Body, out interface{}) (interface{}, error)
 		return pims, nil
 
 	case Config:
-		//Create the object to be decoded to
-		var config Config
+		//Create the object to be decoded to, starting from the defaults
+		config := DefaultConfig()
 
 		//Decode the parsed HCL to the Object
 		decodeDiags := gohcl.DecodeBody(body, nil, &config)
@@ -85,6 +134,20 @@ func (u *Utility) ParseBody(body hcl.Body, out interface{}) (interface{}, error)
 		}
 
 		return config, nil
+
+	case RepositoryIndex:
+		//Create the object to be decoded to
+		var index RepositoryIndex
+
+		//Decode the parsed HCL to the Object
+		decodeDiags := gohcl.DecodeBody(body, nil, &index)
+
+		//Check for errors
+		if decodeDiags.HasErrors() {
+			return index, errors.New("DecodeDiags: " + decodeDiags.Error())
+		}
+
+		return index, nil
 	}
 
 }
@@ -102,3 +165,21 @@ func (u *Utility) GetHCLBody(filepath string) (hcl.Body, error) {
 
 	return file.Body, nil
 }
+
+//HCLDiagnostics parses the HCL file at the given filepath and decodes it to the type of out, returning all the diagnostics
+//so that problems can be reported with their exact location in the file
+func (u *Utility) HCLDiagnostics(path string, out interface{}) hcl.Diagnostics {
+	//create a parser
+	parser := hclparse.NewParser()
+
+	file, diags := parser.ParseHCLFile(path)
+
+	if diags.HasErrors() {
+		return diags
+	}
+
+	//Decode to a new value of the same type as out
+	val := reflect.New(reflect.TypeOf(out))
+
+	return append(diags, gohcl.DecodeBody(file.Body, nil, val.Interface())...)
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -289,6 +292,63 @@ func TestParseBodyPackageNoCopy(t *testing.T) {
 	}
 }
 
+//Test the parse body function with a pim version that publishes several ports with port blocks
+func TestParseBodyPackageWithPortBlocks(t *testing.T) {
+	//Create the HCL byte array
+	hcl := []byte(`pim "test_pack" {
+		base_dir="/base"
+		version "latest" {
+			image="test"
+
+			port="3000"
+
+			port {
+				container="8080"
+			}
+
+			port {
+				container="53"
+				protocol="udp"
+				host=5353
+			}
+		}
+	}`)
+
+	//Create the parser
+	parser := hclparse.NewParser()
+
+	//Parse the byte array
+	f, diags := parser.ParseHCL(hcl, "config_test")
+
+	//If error it fails
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	//Create a new utility
+	util := NewUtility()
+
+	//Parse the HCL Body
+	parseOut, err := util.ParseBody(f.Body, PimHCLUtil{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version := parseOut.(PimHCLUtil).Pims[0].Versions[0]
+
+	//The port attribute is published along with the port blocks
+	expected := []Port{
+		{Container: "3000", Protocol: "tcp"},
+		{Container: "8080", Protocol: "tcp"},
+		{Container: "53", Protocol: "udp", Host: 5353},
+	}
+
+	if ports := version.PublishedPorts(); !reflect.DeepEqual(expected, ports) {
+		t.Fatalf("pim ports should be %v | Received: %v", expected, ports)
+	}
+}
+
 func TestParseBodyReturnErrorWhenTypeIsUnexpected(t *testing.T) {
 	//Parse the HCL Body
 	_, err := NewUtility().ParseBody(hcl.EmptyBody(), nil)
@@ -304,6 +364,40 @@ func TestParseBodyReturnErrorWhenTypeIsUnexpected(t *testing.T) {
 	}
 }
 
+//Test the HCLDiagnostics function reports the location of every problem in the file
+func TestHCLDiagnostics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.hcl")
+
+	err := ioutil.WriteFile(path, []byte("base_dir = \"~/.packageless/\"\nstart_port = \"abc\"\nport_increment = 1\nalias = true\nrepository_host = \"https://example.com/\"\npims_config_dir = \"pims_config/\"\npims_dir = \"pims/\"\n"), 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags := NewUtility().HCLDiagnostics(path, Config{})
+
+	if len(diags) != 1 {
+		t.Fatalf("HCLDiagnostics: Expected 1 diagnostic | Received: %v", diags)
+	}
+
+	if diags[0].Subject == nil || diags[0].Subject.Start.Line != 2 {
+		t.Fatalf("HCLDiagnostics: Expected the diagnostic to be on line 2 | Received: %s", diags[0].Error())
+	}
+
+	//A valid file should not have any diagnostics
+	err = ioutil.WriteFile(path, []byte("base_dir = \"~/.packageless/\"\nstart_port = 3000\nport_increment = 1\nalias = true\nrepository_host = \"https://example.com/\"\npims_config_dir = \"pims_config/\"\npims_dir = \"pims/\"\n"), 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diags = NewUtility().HCLDiagnostics(path, Config{})
+
+	if len(diags) != 0 {
+		t.Fatalf("HCLDiagnostics: Expected no diagnostics | Received: %v", diags)
+	}
+}
+
 // Integration Tests For HCL Parsing
 //-----------------------------------------------------------------------------------------
 
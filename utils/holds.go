@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl2/gohcl"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+)
+
+//HoldsFile is the name of the file in the base directory that lists the pinned pims
+const HoldsFile = "holds.hcl"
+
+//Hold object to parse the hold block in the holds file.
+//A hold without a version pins every version of the pim
+type Hold struct {
+	Pim     string `hcl:"pim,label"`
+	Version string `hcl:"version,optional"`
+}
+
+//Holds object to contain the list of pinned pims
+type Holds struct {
+	Holds []Hold `hcl:"hold,block"`
+}
+
+//String returns the hold in the pim[:version] format
+func (h Hold) String() string {
+	if h.Version == "" {
+		return h.Pim
+	}
+
+	return h.Pim + ":" + h.Version
+}
+
+//Matches checks if the hold pins the specified version of the pim
+func (h Hold) Matches(pim string, version string) bool {
+	return h.Pim == pim && (h.Version == "" || h.Version == version)
+}
+
+//IsHeld checks if any of the holds pins the specified version of the pim.
+//An empty version checks if any version of the pim is pinned
+func IsHeld(holds []Hold, pim string, version string) bool {
+	for _, hold := range holds {
+		if hold.Pim == pim && (version == "" || hold.Matches(pim, version)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//LoadHoldsHCL parses the contents of a holds file
+func LoadHoldsHCL(src []byte, filename string) ([]Hold, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var holds Holds
+
+	diags = append(diags, gohcl.DecodeBody(file.Body, nil, &holds)...)
+
+	return holds.Holds, diags
+}
+
+//HoldsHCL returns the contents of a holds file for the holds
+func HoldsHCL(holds []Hold) string {
+	var sb strings.Builder
+
+	sb.WriteString("# Pims pinned with packageless pin, upgrading or updating all pims skips them\n")
+
+	for _, hold := range holds {
+		if hold.Version == "" {
+			sb.WriteString(fmt.Sprintf("\nhold %s {}\n", quoteHCLString(hold.Pim)))
+		} else {
+			sb.WriteString(fmt.Sprintf("\nhold %s {\n  version = %s\n}\n", quoteHCLString(hold.Pim), quoteHCLString(hold.Version)))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+//Test the holds can be written to a holds file and parsed back
+func TestHoldsHCLRoundTrip(t *testing.T) {
+	holds := []Hold{{Pim: "node", Version: "16"}, {Pim: "python"}}
+
+	parsed, diags := LoadHoldsHCL([]byte(HoldsHCL(holds)), HoldsFile)
+
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if !reflect.DeepEqual(holds, parsed) {
+		t.Fatalf("HoldsHCL: Parsed holds do not match | Received: %v | Expected: %v", parsed, holds)
+	}
+}
+
+//Test checking if a version of a pim is held
+func TestIsHeld(t *testing.T) {
+	holds := []Hold{{Pim: "node", Version: "16"}, {Pim: "python"}}
+
+	cases := []struct {
+		pim      string
+		version  string
+		expected bool
+	}{
+		{"python", "latest", true},
+		{"python", "", true},
+		{"node", "16", true},
+		{"node", "latest", false},
+		{"node", "", true},
+		{"ruby", "", false},
+	}
+
+	for _, c := range cases {
+		if IsHeld(holds, c.pim, c.version) != c.expected {
+			t.Fatalf("IsHeld: Expected %s:%s to be held: %t", c.pim, c.version, c.expected)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl2/gohcl"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+)
+
+//LockFile is the name of the lockfile that is written by export and read by sync when no path is given
+const LockFile = "packageless.lock.hcl"
+
+//LockEntry object to parse the lock block in the lockfile, a single installed version of a pim
+type LockEntry struct {
+	Pim     string `hcl:"pim,label" json:"pim"`
+	Version string `hcl:"version,label" json:"version"`
+	Image   string `hcl:"image,attr" json:"image"`
+
+	//Digest of the image manifest in the registry, empty for images that were not pulled from a registry
+	Digest string `hcl:"digest,optional" json:"digest"`
+
+	//Checksum of the pim configuration file in the form sha256:<hex digest>
+	ConfigChecksum string `hcl:"config_checksum,optional" json:"config_checksum"`
+}
+
+//Lockfile object to contain the list of locked pim versions
+type Lockfile struct {
+	Entries []LockEntry `hcl:"lock,block"`
+}
+
+//Checksum returns the checksum of the content in the form sha256:<hex digest>, the form used by the repository index
+func Checksum(content []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+}
+
+//LoadLockfileHCL parses the contents of a lockfile
+func LoadLockfileHCL(src []byte, filename string) ([]LockEntry, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var lockfile Lockfile
+
+	diags = append(diags, gohcl.DecodeBody(file.Body, nil, &lockfile)...)
+
+	return lockfile.Entries, diags
+}
+
+//LockfileHCL returns the contents of a lockfile for the entries
+func LockfileHCL(entries []LockEntry) string {
+	var sb strings.Builder
+
+	sb.WriteString("# packageless lockfile, created with packageless export and applied with packageless sync\n")
+
+	for _, entry := range entries {
+		sb.WriteString(fmt.Sprintf("\nlock %s %s {\n", quoteHCLString(entry.Pim), quoteHCLString(entry.Version)))
+		sb.WriteString(fmt.Sprintf("  image           = %s\n", quoteHCLString(entry.Image)))
+		sb.WriteString(fmt.Sprintf("  digest          = %s\n", quoteHCLString(entry.Digest)))
+		sb.WriteString(fmt.Sprintf("  config_checksum = %s\n", quoteHCLString(entry.ConfigChecksum)))
+		sb.WriteString("}\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+//Test the lock entries can be written to a lockfile and parsed back
+func TestLockfileHCLRoundTrip(t *testing.T) {
+	entries := []LockEntry{
+		{Pim: "python", Version: "latest", Image: "packageless/python", Digest: "sha256:digest", ConfigChecksum: Checksum([]byte("pim \"python\" {}"))},
+		{Pim: "node", Version: "16", Image: "local/node:16"},
+	}
+
+	parsed, diags := LoadLockfileHCL([]byte(LockfileHCL(entries)), LockFile)
+
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	if !reflect.DeepEqual(entries, parsed) {
+		t.Fatalf("LockfileHCL: Parsed entries do not match | Received: %v | Expected: %v", parsed, entries)
+	}
+}
+
+//Test the checksum has the form used by the repository index
+func TestChecksum(t *testing.T) {
+	expected := "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	if checksum := Checksum([]byte{}); checksum != expected {
+		t.Fatalf("Checksum: Expected: %s | Received: %s", expected, checksum)
+	}
+}
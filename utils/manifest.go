@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"github.com/hashicorp/hcl2/gohcl"
+	"github.com/hashicorp/hcl2/hcl"
+	"github.com/hashicorp/hcl2/hcl/hclsyntax"
+)
+
+//ManifestFile is the name of the project manifest, it is looked for in the working directory and its parents
+const ManifestFile = ".packageless.hcl"
+
+//ManifestPim object to parse the pim block in the project manifest, a pim the project needs
+type ManifestPim struct {
+	Name string `hcl:"name,label" json:"name"`
+
+	//Version of the pim the project needs, latest when it is not set
+	Version string `hcl:"version,optional" json:"version"`
+}
+
+//Manifest object to contain the list of pims a project needs
+type Manifest struct {
+	Pims []ManifestPim `hcl:"pim,block"`
+}
+
+//Ref returns the pim in the pim:version format, with latest if the version is not set
+func (mp ManifestPim) Ref() string {
+	if mp.Version == "" {
+		return mp.Name + ":latest"
+	}
+
+	return mp.Name + ":" + mp.Version
+}
+
+//LoadManifestHCL parses the contents of a project manifest
+func LoadManifestHCL(src []byte, filename string) ([]ManifestPim, hcl.Diagnostics) {
+	file, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var manifest Manifest
+
+	diags = append(diags, gohcl.DecodeBody(file.Body, nil, &manifest)...)
+
+	return manifest.Pims, diags
+}
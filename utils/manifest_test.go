@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+//Test a project manifest is parsed with the versions it sets
+func TestLoadManifestHCL(t *testing.T) {
+	src := "pim \"node\" {\n  version = \"16\"\n}\n\npim \"terraform\" {}\n"
+
+	pims, diags := LoadManifestHCL([]byte(src), ManifestFile)
+
+	if diags.HasErrors() {
+		t.Fatal(diags)
+	}
+
+	expected := []ManifestPim{{Name: "node", Version: "16"}, {Name: "terraform"}}
+
+	if !reflect.DeepEqual(expected, pims) {
+		t.Fatalf("LoadManifestHCL: Expected: %v | Received: %v", expected, pims)
+	}
+
+	if pims[1].Ref() != "terraform:latest" {
+		t.Fatalf("Ref: Expected: terraform:latest | Received: %s", pims[1].Ref())
+	}
+}
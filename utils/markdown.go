@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/glamour/ansi"
@@ -9,6 +11,26 @@ import (
 
 //RenderMarkdown - renders markdown and outputs it to the console
 func RenderMarkdown(input string, colors []string) error {
+	return FprintMarkdown(os.Stdout, input, colors)
+}
+
+//FprintMarkdown - renders markdown and writes it to the writer
+//If no colors are passed the markdown is rendered as plain text
+func FprintMarkdown(w io.Writer, input string, colors []string) error {
+	if len(colors) == 0 {
+		renderer, _ := glamour.NewTermRenderer(glamour.WithStandardStyle("notty"))
+
+		out, err := renderer.Render(input)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprint(w, out)
+
+		return err
+	}
+
 	// Set up the renderer
 	bold := true
 	italic := true
@@ -58,25 +80,51 @@ func RenderMarkdown(input string, colors []string) error {
 		return err
 	}
 
-	fmt.Print(out)
+	_, err = fmt.Fprint(w, out)
 
-	return nil
+	return err
 }
 
 //RenderInfoMarkdown - Renders markdown and outputs it with a color scheme specific to info messages
 func (u *Utility) RenderInfoMarkdown(input string) {
-	err := RenderMarkdown(input, []string{"45", "232", "231"})
-
-	if err != nil {
-		fmt.Println(input)
-	}
+	u.output().Info(input)
 }
 
 //RenderErrorMarkdown - Renders markdown and outputs it with a color scheme specific to error messages
 func (u *Utility) RenderErrorMarkdown(input string) {
-	err := RenderMarkdown(input, []string{"88", "255", "231"})
+	u.output().Error(input)
+}
 
-	if err != nil {
-		fmt.Println(input)
+//Emit - Outputs an event of a subcommand
+func (u *Utility) Emit(event Event) {
+	u.output().Emit(event)
+}
+
+//Finish - Outputs the final result of the subcommand, err being the error it failed with if any
+func (u *Utility) Finish(exitCode int, err error) {
+	u.output().Finish(exitCode, err)
+}
+
+//RedirectOutput - Outputs to the writer instead of stdout from now on
+func (u *Utility) RedirectOutput(out io.Writer) {
+	u.output().Redirect(out)
+}
+
+//output - Gets the output of the utility, markdown is used if none was set
+func (u *Utility) output() Output {
+	if u.Output == nil {
+		u.Output = &MarkdownOutput{Out: os.Stdout}
+	}
+
+	return u.Output
+}
+
+//Verbosef - Outputs a message describing what packageless is doing when verbose output is enabled.
+//The messages are written to stderr so that they do not mix with the output of the subcommands
+func (u *Utility) Verbosef(format string, args ...interface{}) {
+	if !u.Verbose {
+		return
 	}
+
+	fmt.Fprintf(os.Stderr, "packageless: "+format+"\n", args...)
 }
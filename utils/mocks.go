@@ -1,15 +1,20 @@
 package utils
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
 	"io"
+	"net"
 	"os"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/hashicorp/hcl2/hcl"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
@@ -74,17 +79,53 @@ type MockUtility struct {
 	RunPorts         []string
 	RunVolumes       []string
 	RunContainerName string
+	RunLabels        map[string]string
 	RunArgs          []string
+	RunTTY           *bool
+
+	//Exit code of the container to return from RunContainer
+	RunExitCode int
+
+	//Containers to return from ListPimContainers
+	Containers []types.Container
+
+	//Containers to return from ListTemporaryContainers
+	TempContainers []types.Container
+
+	//Images to return from ListImages
+	Images []types.ImageSummary
+
+	//Entries of the directories to return from ListDir
+	DirEntries map[string][]string
+
+	//Keep track of the containers that are removed
+	RemovedContainers []string
+
+	//Keep track of the containers that are stopped
+	StoppedContainers []string
+	StopTimeout       time.Duration
 
 	//Keep track of the RemoveImage data
 	RemovedImgs []string
 
+	//Digests that the registry has for the images, returned from RemoteDigest
+	RemoteDigests map[string]string
+
+	//Keep track of the tags added with TagImage and the image they were added to
+	TaggedImages map[string]string
+
+	//Keep track of the directories copied with CopyDir and the directory they were copied from
+	CopiedDirs map[string]string
+
 	//Keep track of the alias data
 	CmdToAlias []string
 
 	//Should the Pim Configuration file exist
 	PimConfigShouldExist bool
 
+	//Whether FileExists finds a file by its path, overriding PimConfigShouldExist
+	ExistingFiles map[string]bool
+
 	//Pim Config Directory passed in
 	PimConfigDir string
 
@@ -93,6 +134,74 @@ type MockUtility struct {
 
 	//List of pims fetched using FetchPimConfigs
 	FetchedPims []string
+
+	//Image summary to return from FindImage
+	ImgSummary types.ImageSummary
+
+	//Image summary to return from FindImage once an image has been pulled, the image does not change if it has no ID
+	PulledImgSummary types.ImageSummary
+
+	//Size to return from DirSize
+	DirSizeRet int64
+
+	//Keep track of the directories that had their size calculated
+	SizedDirs []string
+
+	//Set if the alias should exist or not for testing
+	AliasExist bool
+
+	//Repository index object that can be changed for different tests
+	Index RepositoryIndex
+
+	//Keep track of the directories the repository index was fetched to
+	FetchedIndexes []string
+
+	//Keep track of the paths that pim configurations were fetched to
+	FetchedPaths []string
+
+	//Keep track of the files that are removed
+	RemovedFiles []string
+
+	//Docker version to return from DockerVersion
+	DockerVer types.Version
+
+	//Diagnostics to return from HCLDiagnostics
+	Diags hcl.Diagnostics
+
+	//Keep track of the directories that were checked for being writable
+	WritableDirs []string
+
+	//Keep track of the files written with WriteFile and their content
+	WrittenFiles map[string]string
+
+	//Content of the files that can be read with ReadFile, files that were written can be read as well
+	FileContents map[string]string
+
+	//Content that the file is changed to when it is opened in the editor
+	EditorContent string
+
+	//Keep track of the events that were emitted
+	Events []Event
+
+	//Plugins to return from FindPlugins
+	Plugins []Plugin
+
+	//Keep track of the directories that plugins were looked for in
+	PluginDirs []string
+
+	//Keep track of the RunPlugin data
+	RunPluginPath string
+	RunPluginArgs []string
+	RunPluginEnv  []string
+
+	//Exit code to return from RunPlugin
+	PluginExitCode int
+
+	//Paths to return from FindFileUp by the name of the file
+	FoundFiles map[string]string
+
+	//Ports that PortAvailable reports as used, whatever their protocol is
+	UsedPorts map[int]bool
 }
 
 //Create a new Mock Utility and set any default variables
@@ -168,6 +277,59 @@ func (mu *MockUtility) OpenFile(path string) (*os.File, error) {
 	return &os.File{}, nil
 }
 
+//Mock of the WriteFile Utility function
+func (mu *MockUtility) WriteFile(path string, content string) error {
+	mu.Calls = append(mu.Calls, "WriteFile")
+
+	if mu.ErrorAt == "WriteFile" {
+		return errors.New(mu.ErrorMsg)
+	}
+
+	if mu.WrittenFiles == nil {
+		mu.WrittenFiles = make(map[string]string)
+	}
+
+	mu.WrittenFiles[path] = content
+
+	return nil
+}
+
+//Mock of the ReadFile Utility function
+func (mu *MockUtility) ReadFile(path string) (string, error) {
+	mu.Calls = append(mu.Calls, "ReadFile")
+
+	if mu.ErrorAt == "ReadFile" {
+		return "", errors.New(mu.ErrorMsg)
+	}
+
+	if content, ok := mu.WrittenFiles[path]; ok {
+		return content, nil
+	}
+
+	if content, ok := mu.FileContents[path]; ok {
+		return content, nil
+	}
+
+	return "", os.ErrNotExist
+}
+
+//Mock of the OpenEditor Utility function
+func (mu *MockUtility) OpenEditor(path string) error {
+	mu.Calls = append(mu.Calls, "OpenEditor")
+
+	if mu.ErrorAt == "OpenEditor" {
+		return errors.New(mu.ErrorMsg)
+	}
+
+	if mu.WrittenFiles == nil {
+		mu.WrittenFiles = make(map[string]string)
+	}
+
+	mu.WrittenFiles[path] = mu.EditorContent
+
+	return nil
+}
+
 //Mock of the RemoveDir Utility function
 func (mu *MockUtility) RemoveDir(path string) error {
 	mu.Calls = append(mu.Calls, "RemoveDir")
@@ -180,6 +342,23 @@ func (mu *MockUtility) RemoveDir(path string) error {
 	return nil
 }
 
+//Mock of the CopyDir Utility function
+func (mu *MockUtility) CopyDir(source string, dest string) error {
+	mu.Calls = append(mu.Calls, "CopyDir")
+
+	if mu.ErrorAt == "CopyDir" {
+		return errors.New(mu.ErrorMsg)
+	}
+
+	if mu.CopiedDirs == nil {
+		mu.CopiedDirs = make(map[string]string)
+	}
+
+	mu.CopiedDirs[dest] = source
+
+	return nil
+}
+
 //Mock of the UpgradeDir Utility function
 func (mu *MockUtility) UpgradeDir(path string) error {
 	mu.Calls = append(mu.Calls, "UpgradeDir")
@@ -207,6 +386,8 @@ func (mu *MockUtility) ParseBody(body hcl.Body, out interface{}) (interface{}, e
 		return mu.Pim, nil
 	case Config:
 		return mu.Conf, nil
+	case RepositoryIndex:
+		return mu.Index, nil
 	}
 }
 
@@ -222,6 +403,14 @@ func (mu *MockUtility) GetHCLBody(filepath string) (hcl.Body, error) {
 	return mu.HCLBody, nil
 }
 
+//Mock of the HCLDiagnostics Utility function
+func (mu *MockUtility) HCLDiagnostics(path string, out interface{}) hcl.Diagnostics {
+	mu.Calls = append(mu.Calls, "HCLDiagnostics")
+	mu.HCLFiles = append(mu.HCLFiles, path)
+
+	return mu.Diags
+}
+
 //Mock of the PullImage Utility function
 func (mu *MockUtility) PullImage(name string, cli Client) error {
 	mu.Calls = append(mu.Calls, "PullImage")
@@ -231,6 +420,10 @@ func (mu *MockUtility) PullImage(name string, cli Client) error {
 		return errors.New(mu.ErrorMsg)
 	}
 
+	if mu.PulledImgSummary.ID != "" {
+		mu.ImgSummary = mu.PulledImgSummary
+	}
+
 	return nil
 }
 
@@ -245,6 +438,21 @@ func (mu *MockUtility) ImageExists(imageID string, cli Client) (bool, error) {
 	return mu.ImgExist, nil
 }
 
+//Mock of the FindImage Utility function
+func (mu *MockUtility) FindImage(imageID string, cli Client) (types.ImageSummary, bool, error) {
+	mu.Calls = append(mu.Calls, "FindImage")
+
+	if mu.ErrorAt == "FindImage" {
+		return types.ImageSummary{}, false, errors.New(mu.ErrorMsg)
+	}
+
+	if !mu.ImgExist {
+		return types.ImageSummary{}, false, nil
+	}
+
+	return mu.ImgSummary, true, nil
+}
+
 //Mock of the CreateContainer Utility function
 func (mu *MockUtility) CreateContainer(image string, cli Client) (string, error) {
 	mu.Calls = append(mu.Calls, "CreateContainer")
@@ -275,6 +483,7 @@ func (mu *MockUtility) CopyFromContainer(source string, dest string, containerID
 func (mu *MockUtility) RemoveContainer(containerID string, cli Client) error {
 	mu.Calls = append(mu.Calls, "RemoveContainer")
 	mu.RemoveContainerID = containerID
+	mu.RemovedContainers = append(mu.RemovedContainers, containerID)
 
 	if mu.ErrorAt == "RemoveContainer" {
 		return errors.New(mu.ErrorMsg)
@@ -284,19 +493,106 @@ func (mu *MockUtility) RemoveContainer(containerID string, cli Client) error {
 }
 
 //Mock of the RunContainer Utility function
-func (mu *MockUtility) RunContainer(image string, ports []string, volumes []string, containerName string, args []string) (string, error) {
+func (mu *MockUtility) RunContainer(opts RunOptions, cli Client) (int, error) {
 	mu.Calls = append(mu.Calls, "RunContainer")
-	mu.RunImage = image
-	mu.RunPorts = ports
-	mu.RunVolumes = volumes
-	mu.RunContainerName = containerName
-	mu.RunArgs = args
+	mu.RunImage = opts.Image
+	mu.RunPorts = opts.Ports
+	mu.RunVolumes = opts.Volumes
+	mu.RunContainerName = opts.Name
+	mu.RunLabels = opts.Labels
+	mu.RunArgs = opts.Args
+	mu.RunTTY = opts.TTY
 
 	if mu.ErrorAt == "RunContainer" {
+		return 1, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.RunExitCode, nil
+}
+
+//Mock of the ListPimContainers Utility function
+func (mu *MockUtility) ListPimContainers(cli Client) ([]types.Container, error) {
+	mu.Calls = append(mu.Calls, "ListPimContainers")
+
+	if mu.ErrorAt == "ListPimContainers" {
+		return nil, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.Containers, nil
+}
+
+//Mock of the ListTemporaryContainers Utility function
+func (mu *MockUtility) ListTemporaryContainers(cli Client) ([]types.Container, error) {
+	mu.Calls = append(mu.Calls, "ListTemporaryContainers")
+
+	if mu.ErrorAt == "ListTemporaryContainers" {
+		return nil, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.TempContainers, nil
+}
+
+//Mock of the ListImages Utility function
+func (mu *MockUtility) ListImages(cli Client) ([]types.ImageSummary, error) {
+	mu.Calls = append(mu.Calls, "ListImages")
+
+	if mu.ErrorAt == "ListImages" {
+		return nil, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.Images, nil
+}
+
+//Mock of the ListDir Utility function
+func (mu *MockUtility) ListDir(path string) ([]string, error) {
+	mu.Calls = append(mu.Calls, "ListDir")
+
+	if mu.ErrorAt == "ListDir" {
+		return nil, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.DirEntries[path], nil
+}
+
+//Mock of the StopContainer Utility function
+func (mu *MockUtility) StopContainer(containerID string, timeout time.Duration, cli Client) error {
+	mu.Calls = append(mu.Calls, "StopContainer")
+	mu.StoppedContainers = append(mu.StoppedContainers, containerID)
+	mu.StopTimeout = timeout
+
+	if mu.ErrorAt == "StopContainer" {
+		return errors.New(mu.ErrorMsg)
+	}
+
+	return nil
+}
+
+//Mock of the RemoteDigest Utility function
+func (mu *MockUtility) RemoteDigest(image string, cli Client) (string, error) {
+	mu.Calls = append(mu.Calls, "RemoteDigest")
+
+	if mu.ErrorAt == "RemoteDigest" {
 		return "", errors.New(mu.ErrorMsg)
 	}
 
-	return "", nil
+	return mu.RemoteDigests[image], nil
+}
+
+//Mock of the TagImage Utility function
+func (mu *MockUtility) TagImage(source string, target string, cli Client) error {
+	mu.Calls = append(mu.Calls, "TagImage")
+
+	if mu.ErrorAt == "TagImage" {
+		return errors.New(mu.ErrorMsg)
+	}
+
+	if mu.TaggedImages == nil {
+		mu.TaggedImages = make(map[string]string)
+	}
+
+	mu.TaggedImages[target] = source
+
+	return nil
 }
 
 //Mock of the RemoveImage Utility function
@@ -311,6 +607,17 @@ func (mu *MockUtility) RemoveImage(image string, cli Client) error {
 	return nil
 }
 
+//Mock of the DockerVersion Utility function
+func (mu *MockUtility) DockerVersion(cli Client) (types.Version, error) {
+	mu.Calls = append(mu.Calls, "DockerVersion")
+
+	if mu.ErrorAt == "DockerVersion" {
+		return types.Version{}, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.DockerVer, nil
+}
+
 //Mock of the AddAliasWin Utility function
 func (mu *MockUtility) AddAliasWin(name string, ed string) error {
 	mu.Calls = append(mu.Calls, "AddAlias")
@@ -359,6 +666,28 @@ func (mu *MockUtility) RemoveAliasUnix(name string, ed string) error {
 	return nil
 }
 
+//Mock of the AliasExistsWin Utility function
+func (mu *MockUtility) AliasExistsWin(name string, ed string) (bool, error) {
+	mu.Calls = append(mu.Calls, "AliasExists")
+
+	if mu.ErrorAt == "AliasExists" {
+		return false, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.AliasExist, nil
+}
+
+//Mock of the AliasExistsUnix Utility function
+func (mu *MockUtility) AliasExistsUnix(name string, ed string) (bool, error) {
+	mu.Calls = append(mu.Calls, "AliasExists")
+
+	if mu.ErrorAt == "AliasExists" {
+		return false, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.AliasExist, nil
+}
+
 func (mu *MockUtility) FetchPimConfig(baseUrl string, pimName string, savePath string) error {
 	mu.Calls = append(mu.Calls, "FetchPimConfig")
 
@@ -367,6 +696,20 @@ func (mu *MockUtility) FetchPimConfig(baseUrl string, pimName string, savePath s
 	}
 
 	mu.FetchedPims = append(mu.FetchedPims, pimName)
+	mu.FetchedPaths = append(mu.FetchedPaths, savePath)
+
+	return nil
+}
+
+//Mock of the FetchIndex Utility function
+func (mu *MockUtility) FetchIndex(baseUrl string, savePath string) error {
+	mu.Calls = append(mu.Calls, "FetchIndex")
+
+	if mu.ErrorAt == "FetchIndex" {
+		return errors.New(mu.ErrorMsg)
+	}
+
+	mu.FetchedIndexes = append(mu.FetchedIndexes, savePath)
 
 	return nil
 }
@@ -374,11 +717,16 @@ func (mu *MockUtility) FetchPimConfig(baseUrl string, pimName string, savePath s
 func (mu *MockUtility) FileExists(path string) bool {
 	mu.Calls = append(mu.Calls, "FileExists")
 
+	if exists, ok := mu.ExistingFiles[path]; ok {
+		return exists
+	}
+
 	return mu.PimConfigShouldExist
 }
 
 func (mu *MockUtility) RemoveFile(path string) error {
 	mu.Calls = append(mu.Calls, "RemoveFile")
+	mu.RemovedFiles = append(mu.RemovedFiles, path)
 
 	if mu.ErrorAt == "RemoveFile" {
 		return errors.New(mu.ErrorMsg)
@@ -387,6 +735,41 @@ func (mu *MockUtility) RemoveFile(path string) error {
 	return nil
 }
 
+//Mock of the CheckRepositoryHost Utility function
+func (mu *MockUtility) CheckRepositoryHost(baseUrl string) error {
+	mu.Calls = append(mu.Calls, "CheckRepositoryHost")
+
+	if mu.ErrorAt == "CheckRepositoryHost" {
+		return errors.New(mu.ErrorMsg)
+	}
+
+	return nil
+}
+
+//Mock of the DirWritable Utility function
+func (mu *MockUtility) DirWritable(path string) error {
+	mu.Calls = append(mu.Calls, "DirWritable")
+	mu.WritableDirs = append(mu.WritableDirs, path)
+
+	if mu.ErrorAt == "DirWritable" {
+		return errors.New(mu.ErrorMsg)
+	}
+
+	return nil
+}
+
+//Mock of the DirSize Utility function
+func (mu *MockUtility) DirSize(path string) (int64, error) {
+	mu.Calls = append(mu.Calls, "DirSize")
+	mu.SizedDirs = append(mu.SizedDirs, path)
+
+	if mu.ErrorAt == "DirSize" {
+		return 0, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.DirSizeRet, nil
+}
+
 func (mu *MockUtility) GetListOfInstalledPimConfigs(pimConfigDir string) ([]string, error) {
 	mu.Calls = append(mu.Calls, "GetListOfInstalledPimConfigs")
 
@@ -399,6 +782,17 @@ func (mu *MockUtility) GetListOfInstalledPimConfigs(pimConfigDir string) ([]stri
 	return mu.InstalledPims, nil
 }
 
+//Mock of the TempDir Utility function
+func (mu *MockUtility) TempDir(pattern string) (string, error) {
+	mu.Calls = append(mu.Calls, "TempDir")
+
+	if mu.ErrorAt == "TempDir" {
+		return "", errors.New(mu.ErrorMsg)
+	}
+
+	return "/tmp/" + pattern + "/", nil
+}
+
 //Mock of the Getwd Utility function
 func (mu *MockUtility) Getwd() (dir string, err error) {
 	mu.Calls = append(mu.Calls, "Getwd")
@@ -410,6 +804,46 @@ func (mu *MockUtility) Getwd() (dir string, err error) {
 	return os.Getwd()
 }
 
+//Mock of the FindFileUp Utility function
+func (mu *MockUtility) FindFileUp(dir string, name string) string {
+	mu.Calls = append(mu.Calls, "FindFileUp")
+
+	return mu.FoundFiles[name]
+}
+
+//Mock of the PortAvailable Utility function
+func (mu *MockUtility) PortAvailable(protocol string, port int) bool {
+	mu.Calls = append(mu.Calls, "PortAvailable")
+
+	return !mu.UsedPorts[port]
+}
+
+//Mock of the FindPlugins Utility function
+func (mu *MockUtility) FindPlugins(dirs []string) ([]Plugin, error) {
+	mu.Calls = append(mu.Calls, "FindPlugins")
+	mu.PluginDirs = dirs
+
+	if mu.ErrorAt == "FindPlugins" {
+		return nil, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.Plugins, nil
+}
+
+//Mock of the RunPlugin Utility function
+func (mu *MockUtility) RunPlugin(path string, args []string, env []string) (int, error) {
+	mu.Calls = append(mu.Calls, "RunPlugin")
+	mu.RunPluginPath = path
+	mu.RunPluginArgs = args
+	mu.RunPluginEnv = env
+
+	if mu.ErrorAt == "RunPlugin" {
+		return 1, errors.New(mu.ErrorMsg)
+	}
+
+	return mu.PluginExitCode, nil
+}
+
 //Mock of the RenderInfoMarkdown utility function
 func (mu *MockUtility) RenderInfoMarkdown(input string) {
 	mu.Calls = append(mu.Calls, "RenderInfoMarkdown")
@@ -420,6 +854,17 @@ func (mu *MockUtility) RenderErrorMarkdown(input string) {
 	mu.Calls = append(mu.Calls, "RenderErrorMarkdown")
 }
 
+//Mock of the Emit utility function
+func (mu *MockUtility) Emit(event Event) {
+	mu.Calls = append(mu.Calls, "Emit")
+	mu.Events = append(mu.Events, event)
+}
+
+//Mock of the RedirectOutput utility function
+func (mu *MockUtility) RedirectOutput(out io.Writer) {
+	mu.Calls = append(mu.Calls, "RedirectOutput")
+}
+
 //Create a Mock for the Docker client
 type DockMock struct {
 	//Variable to know what function to return an error from
@@ -432,9 +877,10 @@ type DockMock struct {
 	IPRefStr string
 
 	//Keep track of the values from the ContainerCreate Function
-	CCConfig *container.Config
-	CCName   string
-	CCRet    container.ContainerCreateCreatedBody
+	CCConfig     *container.Config
+	CCHostConfig *container.HostConfig
+	CCName       string
+	CCRet        container.ContainerCreateCreatedBody
 
 	//Keep track of the values from the CopyFromContainer Function
 	CFCID     string
@@ -450,6 +896,55 @@ type DockMock struct {
 
 	//ImageList return value
 	ILRet []types.ImageSummary
+
+	//ServerVersion return value
+	SVRet types.Version
+
+	//ContainerList return value
+	CLRet []types.Container
+
+	//Keep track of the values from the ContainerList Function
+	CLOptions types.ContainerListOptions
+
+	//Keep track of the values from the ContainerStop Function
+	CSContainer string
+	CSTimeout   *time.Duration
+
+	//Keep track of the values from the ContainerKill Function
+	CKContainer string
+	CKSignal    string
+
+	//DistributionInspect return value
+	DIRet registry.DistributionInspect
+
+	//Keep track of the values from the ImageTag Function
+	ITSource string
+	ITTarget string
+
+	//Keep track of the values from the DistributionInspect Function
+	DIImage string
+
+	//Keep track of the values from the ContainerAttach Function
+	CAContainer string
+	CAOptions   types.ContainerAttachOptions
+
+	//Output of the container to return from ContainerAttach, the standard error is only used for containers without a terminal
+	CAOutput    string
+	CAErrOutput string
+
+	//Keep track of the values from the ContainerStart Function
+	CStartContainer string
+
+	//Keep track of the values from the ContainerWait Function
+	CWContainer string
+	CWCondition container.WaitCondition
+
+	//Exit code of the container to return from ContainerWait
+	CWStatusCode int64
+
+	//Keep track of the values from the ContainerResize Function
+	CRSContainer string
+	CRSOptions   types.ResizeOptions
 }
 
 //Function to create a new DockMock
@@ -489,6 +984,7 @@ func (dm *DockMock) ContainerCreate(ctx context.Context, config *container.Confi
 	}
 
 	dm.CCConfig = config
+	dm.CCHostConfig = hostConfig
 	dm.CCName = containerName
 	return dm.CCRet, nil
 }
@@ -530,6 +1026,141 @@ func (dm *DockMock) ImageRemove(ctx context.Context, imageID string, options typ
 	return nil, nil
 }
 
+//Mock function of the Docker SDK Ping function
+func (dm *DockMock) Ping(ctx context.Context) (types.Ping, error) {
+	if dm.ErrorAt == "Ping" {
+		return types.Ping{}, errors.New(dm.ErrorMsg)
+	}
+
+	return types.Ping{APIVersion: dm.SVRet.APIVersion}, nil
+}
+
+//Mock function of the Docker SDK ServerVersion function
+func (dm *DockMock) ServerVersion(ctx context.Context) (types.Version, error) {
+	if dm.ErrorAt == "ServerVersion" {
+		return types.Version{}, errors.New(dm.ErrorMsg)
+	}
+
+	return dm.SVRet, nil
+}
+
+//Mock function of the Docker SDK ContainerList function
+func (dm *DockMock) ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error) {
+	if dm.ErrorAt == "ContainerList" {
+		return nil, errors.New(dm.ErrorMsg)
+	}
+
+	dm.CLOptions = options
+	return dm.CLRet, nil
+}
+
+//Mock function of the Docker SDK ContainerStop function
+func (dm *DockMock) ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error {
+	if dm.ErrorAt == "ContainerStop" {
+		return errors.New(dm.ErrorMsg)
+	}
+
+	dm.CSContainer = containerID
+	dm.CSTimeout = timeout
+	return nil
+}
+
+//Mock function of the Docker SDK ContainerKill function
+func (dm *DockMock) ContainerKill(ctx context.Context, containerID string, signal string) error {
+	if dm.ErrorAt == "ContainerKill" {
+		return errors.New(dm.ErrorMsg)
+	}
+
+	dm.CKContainer = containerID
+	dm.CKSignal = signal
+	return nil
+}
+
+//Mock function of the Docker SDK DistributionInspect function
+func (dm *DockMock) DistributionInspect(ctx context.Context, image string, encodedRegistryAuth string) (registry.DistributionInspect, error) {
+	if dm.ErrorAt == "DistributionInspect" {
+		return registry.DistributionInspect{}, errors.New(dm.ErrorMsg)
+	}
+
+	dm.DIImage = image
+	return dm.DIRet, nil
+}
+
+//Mock function of the Docker SDK ImageTag function
+func (dm *DockMock) ImageTag(ctx context.Context, source string, target string) error {
+	if dm.ErrorAt == "ImageTag" {
+		return errors.New(dm.ErrorMsg)
+	}
+
+	dm.ITSource = source
+	dm.ITTarget = target
+	return nil
+}
+
+//Mock function of the Docker SDK ContainerAttach function
+func (dm *DockMock) ContainerAttach(ctx context.Context, container string, options types.ContainerAttachOptions) (types.HijackedResponse, error) {
+	if dm.ErrorAt == "ContainerAttach" {
+		return types.HijackedResponse{}, errors.New(dm.ErrorMsg)
+	}
+
+	dm.CAContainer = container
+	dm.CAOptions = options
+
+	//The input sent to the container is discarded
+	server, conn := net.Pipe()
+	go io.Copy(io.Discard, server)
+
+	output := &bytes.Buffer{}
+
+	//Like the Docker Engine, the output of a container without a terminal is multiplexed
+	if dm.CCConfig != nil && !dm.CCConfig.Tty {
+		stdcopy.NewStdWriter(output, stdcopy.Stdout).Write([]byte(dm.CAOutput))
+		stdcopy.NewStdWriter(output, stdcopy.Stderr).Write([]byte(dm.CAErrOutput))
+	} else {
+		output.WriteString(dm.CAOutput)
+	}
+
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(output)}, nil
+}
+
+//Mock function of the Docker SDK ContainerStart function
+func (dm *DockMock) ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error {
+	if dm.ErrorAt == "ContainerStart" {
+		return errors.New(dm.ErrorMsg)
+	}
+
+	dm.CStartContainer = containerID
+	return nil
+}
+
+//Mock function of the Docker SDK ContainerResize function
+func (dm *DockMock) ContainerResize(ctx context.Context, containerID string, options types.ResizeOptions) error {
+	if dm.ErrorAt == "ContainerResize" {
+		return errors.New(dm.ErrorMsg)
+	}
+
+	dm.CRSContainer = containerID
+	dm.CRSOptions = options
+	return nil
+}
+
+//Mock function of the Docker SDK ContainerWait function
+func (dm *DockMock) ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error) {
+	statusCh := make(chan container.ContainerWaitOKBody, 1)
+	errCh := make(chan error, 1)
+
+	dm.CWContainer = containerID
+	dm.CWCondition = condition
+
+	if dm.ErrorAt == "ContainerWait" {
+		errCh <- errors.New(dm.ErrorMsg)
+	} else {
+		statusCh <- container.ContainerWaitOKBody{StatusCode: dm.CWStatusCode}
+	}
+
+	return statusCh, errCh
+}
+
 //CopyTool Mock
 type MockCopyTool struct {
 	Error    bool
@@ -0,0 +1,411 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//Formats that packageless can output in
+const (
+	OutputMarkdown = "markdown"
+	OutputPlain    = "plain"
+	OutputJSON     = "json"
+)
+
+//OutputFormats lists every format that packageless can output in
+var OutputFormats = []string{OutputMarkdown, OutputPlain, OutputJSON}
+
+//Types of the events emitted while a subcommand runs
+const (
+	//A subcommand started working on a pim or a task
+	EventStarted = "started"
+
+	//A subcommand finished working on a pim or a task
+	EventFinished = "finished"
+
+	//A step of the work on a pim started
+	EventStepStarted = "step_started"
+
+	//A step of the work on a pim finished successfully
+	EventStepFinished = "step_finished"
+
+	//An informational message
+	EventMessage = "message"
+
+	//A problem that did not stop the subcommand
+	EventWarning = "warning"
+
+	//An error that stopped the subcommand
+	EventError = "error"
+
+	//The data describing the outcome of the subcommand, it is part of the final result
+	EventResult = "result"
+)
+
+//Codes identifying the kind of an error in the machine-readable output
+const (
+	ErrorCodeFailed           = "failed"
+	ErrorCodeInvalidArguments = "invalid_arguments"
+	ErrorCodeUnknownCommand   = "unknown_command"
+	ErrorCodeInvalidConfig    = "invalid_config"
+	ErrorCodeBatchFailed      = "batch_failed"
+	ErrorCodeDrifted          = "drifted"
+)
+
+//Event - Something that happened while a subcommand was running
+type Event struct {
+	Type    string      `json:"type"`
+	Command string      `json:"command,omitempty"`
+	Pim     string      `json:"pim,omitempty"`
+	Step    string      `json:"step,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Code    string      `json:"code,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+
+	//Markdown to show instead of the message, for events that are better shown as a table for example
+	Markdown string `json:"-"`
+}
+
+//Result - The final result object of the JSON output
+type Result struct {
+	Type     string      `json:"type"`
+	Command  string      `json:"command,omitempty"`
+	Success  bool        `json:"success"`
+	ExitCode int         `json:"exit_code"`
+	Data     interface{} `json:"data,omitempty"`
+}
+
+//CodedError - Error with a code identifying what kind of error it is
+type CodedError struct {
+	Code string
+	Err  error
+}
+
+//Error - Returns the message of the wrapped error
+func (ce *CodedError) Error() string {
+	return ce.Err.Error()
+}
+
+//Unwrap - Returns the wrapped error
+func (ce *CodedError) Unwrap() error {
+	return ce.Err
+}
+
+//WithErrorCode - Adds a code to an error, nil errors stay nil
+func WithErrorCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &CodedError{Code: code, Err: err}
+}
+
+//ErrorCode - Gets the code of an error, errors without a code are reported as failed
+func ErrorCode(err error) string {
+	var coded *CodedError
+
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+
+	return ErrorCodeFailed
+}
+
+//Output - Interface for the different formats the events and messages of packageless are output in
+type Output interface {
+	//Emit - Outputs an event of the subcommand
+	Emit(event Event)
+
+	//Info - Outputs an informational message written in markdown
+	Info(markdown string)
+
+	//Error - Outputs an error message written in markdown
+	Error(markdown string)
+
+	//Finish - Outputs the final result of the subcommand, err being the error it failed with if any
+	Finish(exitCode int, err error)
+
+	//Redirect - Writes what would go to stdout to the writer instead, e.g. while a pim writes to stdout
+	Redirect(out io.Writer)
+}
+
+//NewOutput - Creates the output for the format.
+//The command is the subcommand being ran, quiet only outputs errors and noColor disables the colors of the human readable formats.
+func NewOutput(format string, command string, quiet bool, noColor bool) (Output, error) {
+	switch format {
+	case "", OutputMarkdown:
+		return &MarkdownOutput{Quiet: quiet, NoColor: noColor, Out: os.Stdout}, nil
+	case OutputPlain:
+		return &PlainOutput{Quiet: quiet, Out: os.Stdout, Err: os.Stderr}, nil
+	case OutputJSON:
+		return &JSONOutput{Command: command, Out: os.Stdout}, nil
+	}
+
+	return nil, fmt.Errorf("Output format %s is unsupported. Supported formats: %s", format, strings.Join(OutputFormats, ", "))
+}
+
+//Color schemes of the markdown messages
+var (
+	infoColors  = []string{"45", "232", "231"}
+	errorColors = []string{"88", "255", "231"}
+)
+
+//isErrorEvent - Checks if the event describes a problem so it should be shown as an error
+func isErrorEvent(event Event) bool {
+	return event.Type == EventWarning || event.Type == EventError || event.Code != ""
+}
+
+//MarkdownOutput - Outputs events and messages as rendered markdown
+type MarkdownOutput struct {
+	Quiet   bool
+	NoColor bool
+	Out     io.Writer
+}
+
+//Emit - Renders the markdown of the event
+func (mo *MarkdownOutput) Emit(event Event) {
+	markdown := event.Markdown
+
+	if markdown == "" {
+		markdown = eventMarkdown(event)
+	}
+
+	//Some events, like finished steps, are not shown
+	if markdown == "" {
+		return
+	}
+
+	if isErrorEvent(event) {
+		mo.Error(markdown)
+		return
+	}
+
+	mo.Info(markdown)
+}
+
+//Info - Renders markdown with the color scheme of info messages
+func (mo *MarkdownOutput) Info(markdown string) {
+	//Only errors are output in quiet mode
+	if mo.Quiet {
+		return
+	}
+
+	mo.render(markdown, infoColors)
+}
+
+//Error - Renders markdown with the color scheme of error messages
+func (mo *MarkdownOutput) Error(markdown string) {
+	mo.render(markdown, errorColors)
+}
+
+//Finish - Renders the error the subcommand failed with
+func (mo *MarkdownOutput) Finish(exitCode int, err error) {
+	if err != nil {
+		mo.Emit(Event{Type: EventError, Code: ErrorCode(err), Message: err.Error()})
+	}
+}
+
+//Redirect - Renders to the writer instead of stdout
+func (mo *MarkdownOutput) Redirect(out io.Writer) {
+	mo.Out = out
+}
+
+//render - Renders markdown with the color scheme, falling back to the raw markdown
+func (mo *MarkdownOutput) render(markdown string, colors []string) {
+	//See https://no-color.org
+	if mo.NoColor || os.Getenv("NO_COLOR") != "" {
+		colors = nil
+	}
+
+	err := FprintMarkdown(mo.out(), markdown, colors)
+
+	if err != nil {
+		fmt.Fprintln(mo.out(), markdown)
+	}
+}
+
+//out - Gets the writer to render to
+func (mo *MarkdownOutput) out() io.Writer {
+	if mo.Out == nil {
+		return os.Stdout
+	}
+
+	return mo.Out
+}
+
+//eventMarkdown - Creates the markdown of an event from its message
+func eventMarkdown(event Event) string {
+	if event.Message == "" {
+		return ""
+	}
+
+	switch event.Type {
+	case EventStarted:
+		if event.Pim != "" {
+			return fmt.Sprintf("**%s**: *%s*", event.Message, event.Pim)
+		}
+
+		return fmt.Sprintf("**%s**", event.Message)
+	case EventStepStarted:
+		return fmt.Sprintf("- *%s*", event.Message)
+	case EventStepFinished:
+		return ""
+	case EventFinished:
+		return fmt.Sprintf("***\n\n*%s* **%s**", event.Pim, event.Message)
+	case EventError:
+		return fmt.Sprintf("# ERROR\n**Encountered an error:** *%s*\n", event.Message)
+	}
+
+	return fmt.Sprintf("*%s*", event.Message)
+}
+
+//PlainOutput - Outputs events and messages as plain text, errors are written to Err
+type PlainOutput struct {
+	Quiet bool
+	Out   io.Writer
+	Err   io.Writer
+}
+
+//Emit - Writes the text of the event
+func (po *PlainOutput) Emit(event Event) {
+	text := eventText(event)
+
+	if text == "" {
+		return
+	}
+
+	if isErrorEvent(event) {
+		fmt.Fprintln(po.Err, text)
+		return
+	}
+
+	if !po.Quiet {
+		fmt.Fprintln(po.Out, text)
+	}
+}
+
+//Info - Writes markdown without any styling
+func (po *PlainOutput) Info(markdown string) {
+	if po.Quiet {
+		return
+	}
+
+	err := FprintMarkdown(po.Out, markdown, nil)
+
+	if err != nil {
+		fmt.Fprintln(po.Out, markdown)
+	}
+}
+
+//Error - Writes markdown without any styling to Err
+func (po *PlainOutput) Error(markdown string) {
+	err := FprintMarkdown(po.Err, markdown, nil)
+
+	if err != nil {
+		fmt.Fprintln(po.Err, markdown)
+	}
+}
+
+//Finish - Writes the error the subcommand failed with
+func (po *PlainOutput) Finish(exitCode int, err error) {
+	if err != nil {
+		po.Emit(Event{Type: EventError, Code: ErrorCode(err), Message: err.Error()})
+	}
+}
+
+//Redirect - Writes the informational text to the writer instead of stdout
+func (po *PlainOutput) Redirect(out io.Writer) {
+	po.Out = out
+}
+
+//eventText - Creates the plain text of an event from its message
+func eventText(event Event) string {
+	if event.Message == "" {
+		return ""
+	}
+
+	switch event.Type {
+	case EventStarted:
+		return strings.TrimSpace(event.Message + " " + event.Pim)
+	case EventStepStarted:
+		return "  " + event.Message
+	case EventStepFinished:
+		return ""
+	case EventFinished:
+		return strings.TrimSpace(event.Pim + " " + event.Message)
+	case EventWarning:
+		return "Warning: " + event.Message
+	case EventError:
+		return "Error: " + event.Message
+	}
+
+	return event.Message
+}
+
+//JSONOutput - Outputs every event as a JSON object on its own line, followed by a final result object
+type JSONOutput struct {
+	//Subcommand that the events are emitted by
+	Command string
+
+	Out io.Writer
+
+	//Data of the last result event, it is output as part of the final result
+	data interface{}
+}
+
+//Emit - Writes the event as a JSON object, result events are kept for the final result
+func (jo *JSONOutput) Emit(event Event) {
+	if event.Command == "" {
+		event.Command = jo.Command
+	}
+
+	if event.Type == EventResult {
+		jo.data = event.Data
+		return
+	}
+
+	jo.write(event)
+}
+
+//Info - Writes the markdown as a message event
+func (jo *JSONOutput) Info(markdown string) {
+	jo.Emit(Event{Type: EventMessage, Message: markdown})
+}
+
+//Error - Writes the markdown as a warning event, the error a subcommand fails with is output by Finish
+func (jo *JSONOutput) Error(markdown string) {
+	jo.Emit(Event{Type: EventWarning, Message: markdown})
+}
+
+//Finish - Writes the error the subcommand failed with and the final result object
+func (jo *JSONOutput) Finish(exitCode int, err error) {
+	if err != nil {
+		jo.Emit(Event{Type: EventError, Code: ErrorCode(err), Message: err.Error()})
+	}
+
+	jo.write(Result{
+		Type:     EventResult,
+		Command:  jo.Command,
+		Success:  err == nil && exitCode == 0,
+		ExitCode: exitCode,
+		Data:     jo.data,
+	})
+}
+
+//Redirect - Writes the events and the final result object to the writer instead of stdout
+func (jo *JSONOutput) Redirect(out io.Writer) {
+	jo.Out = out
+}
+
+//write - Writes a value as a single line of JSON
+func (jo *JSONOutput) write(value interface{}) {
+	encoder := json.NewEncoder(jo.Out)
+	encoder.SetEscapeHTML(false)
+
+	//The output is the only way to report a failure so there is nothing to do with the error
+	encoder.Encode(value)
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+//Test the JSON output writes an object per event followed by the final result
+func TestJSONOutput(t *testing.T) {
+	var buf bytes.Buffer
+
+	jo := &JSONOutput{Command: "install", Out: &buf}
+
+	jo.Emit(Event{Type: EventStepStarted, Pim: "python:latest", Step: "pull_image", Message: "Pulling image"})
+	jo.Emit(Event{Type: EventResult, Data: map[string]string{"version": "1.0"}})
+	jo.Finish(1, WithErrorCode(ErrorCodeInvalidConfig, errors.New("Invalid configuration")))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 3 {
+		t.Fatalf("JSONOutput: Expected 3 lines | Received: %d: %s", len(lines), buf.String())
+	}
+
+	var event Event
+
+	err := json.Unmarshal([]byte(lines[0]), &event)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Event{Type: EventStepStarted, Command: "install", Pim: "python:latest", Step: "pull_image", Message: "Pulling image"}
+
+	if event.Type != expected.Type || event.Command != expected.Command || event.Pim != expected.Pim || event.Step != expected.Step || event.Message != expected.Message {
+		t.Fatalf("JSONOutput: Expected event: %+v | Received: %+v", expected, event)
+	}
+
+	err = json.Unmarshal([]byte(lines[1]), &event)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if event.Type != EventError || event.Code != ErrorCodeInvalidConfig || event.Message != "Invalid configuration" {
+		t.Fatalf("JSONOutput: Expected an error event with the code %s | Received: %+v", ErrorCodeInvalidConfig, event)
+	}
+
+	var result struct {
+		Type     string            `json:"type"`
+		Command  string            `json:"command"`
+		Success  bool              `json:"success"`
+		ExitCode int               `json:"exit_code"`
+		Data     map[string]string `json:"data"`
+	}
+
+	err = json.Unmarshal([]byte(lines[2]), &result)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Type != EventResult || result.Command != "install" || result.Success || result.ExitCode != 1 || result.Data["version"] != "1.0" {
+		t.Fatalf("JSONOutput: Unexpected final result: %s", lines[2])
+	}
+}
+
+//Test the JSON output writes everything after a redirect to the new writer, like run does while a pim writes to stdout
+func TestJSONOutputRedirect(t *testing.T) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	jo := &JSONOutput{Command: "run", Out: &stdout}
+
+	jo.Redirect(&stderr)
+	jo.Emit(Event{Type: EventStepStarted, Pim: "python:latest", Step: "run_container"})
+	jo.Finish(0, nil)
+
+	if stdout.Len() != 0 {
+		t.Fatalf("JSONOutputRedirect: Expected nothing on the original writer | Received: %s", stdout.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("JSONOutputRedirect: Expected 2 lines on the new writer | Received: %d: %s", len(lines), stderr.String())
+	}
+}
+
+//Test the plain output writes messages to Out and problems to Err
+func TestPlainOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	po := &PlainOutput{Out: &out, Err: &errOut}
+
+	po.Emit(Event{Type: EventStarted, Pim: "python:latest", Message: "Installing"})
+	po.Emit(Event{Type: EventStepStarted, Step: "pull_image", Message: "Pulling image"})
+	po.Emit(Event{Type: EventStepFinished, Step: "pull_image", Message: "Pulling image"})
+	po.Emit(Event{Type: EventWarning, Message: "Could not update the repository index"})
+	po.Finish(1, errors.New("Failed"))
+
+	expectedOut := "Installing python:latest\n  Pulling image\n"
+
+	if out.String() != expectedOut {
+		t.Fatalf("PlainOutput: Expected output: %q | Received: %q", expectedOut, out.String())
+	}
+
+	expectedErr := "Warning: Could not update the repository index\nError: Failed\n"
+
+	if errOut.String() != expectedErr {
+		t.Fatalf("PlainOutput: Expected error output: %q | Received: %q", expectedErr, errOut.String())
+	}
+}
+
+//Test the quiet plain output only writes problems
+func TestPlainOutputQuiet(t *testing.T) {
+	var out, errOut bytes.Buffer
+
+	po := &PlainOutput{Quiet: true, Out: &out, Err: &errOut}
+
+	po.Emit(Event{Type: EventStarted, Pim: "python:latest", Message: "Installing"})
+	po.Info("*Info*")
+	po.Emit(Event{Type: EventWarning, Message: "Warning"})
+
+	if out.Len() != 0 {
+		t.Fatalf("PlainOutput: Expected no output | Received: %q", out.String())
+	}
+
+	if errOut.String() != "Warning: Warning\n" {
+		t.Fatalf("PlainOutput: Expected the warning to be written | Received: %q", errOut.String())
+	}
+}
+
+//Test the markdown output does not show finished steps
+func TestMarkdownOutputSkipsFinishedSteps(t *testing.T) {
+	var buf bytes.Buffer
+
+	mo := &MarkdownOutput{NoColor: true, Out: &buf}
+
+	mo.Emit(Event{Type: EventStepFinished, Step: "pull_image", Message: "Pulling image"})
+
+	if buf.Len() != 0 {
+		t.Fatalf("MarkdownOutput: Expected no output | Received: %q", buf.String())
+	}
+
+	mo.Emit(Event{Type: EventStepStarted, Step: "pull_image", Message: "Pulling image"})
+
+	if !strings.Contains(buf.String(), "Pulling image") {
+		t.Fatalf("MarkdownOutput: Expected the step to be shown | Received: %q", buf.String())
+	}
+}
+
+//Test the codes of errors
+func TestErrorCode(t *testing.T) {
+	if WithErrorCode(ErrorCodeFailed, nil) != nil {
+		t.Fatal("WithErrorCode: Expected a nil error to stay nil")
+	}
+
+	err := WithErrorCode(ErrorCodeUnknownCommand, errors.New("Unknown subcommand"))
+
+	if ErrorCode(err) != ErrorCodeUnknownCommand {
+		t.Fatalf("ErrorCode: Expected: %s | Received: %s", ErrorCodeUnknownCommand, ErrorCode(err))
+	}
+
+	if err.Error() != "Unknown subcommand" {
+		t.Fatalf("ErrorCode: Expected the message of the wrapped error | Received: %s", err.Error())
+	}
+
+	if ErrorCode(errors.New("Other")) != ErrorCodeFailed {
+		t.Fatalf("ErrorCode: Expected errors without a code to be %s", ErrorCodeFailed)
+	}
+}
+
+//Test an unknown output format is rejected
+func TestNewOutputUnknownFormat(t *testing.T) {
+	_, err := NewOutput("xml", "version", false, false)
+
+	if err == nil {
+		t.Fatal("NewOutput: Expected an error for an unknown format")
+	}
+
+	for _, format := range OutputFormats {
+		_, err := NewOutput(format, "version", false, false)
+
+		if err != nil {
+			t.Fatalf("NewOutput: Unexpected error for the format %s: %s", format, err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+//PluginPrefix is the prefix of the name of the executables that provide plugin subcommands
+const PluginPrefix = "packageless-"
+
+//Plugin - An external executable that provides a subcommand
+type Plugin struct {
+	//Name of the subcommand, the name of the executable without the prefix
+	Name string
+
+	//Path to the executable
+	Path string
+}
+
+//FindPlugins looks for the plugin executables in the directories.
+//When multiple directories contain a plugin with the same name, the one in the first directory is used.
+func (u *Utility) FindPlugins(dirs []string) ([]Plugin, error) {
+	found := make(map[string]Plugin)
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+
+		files, err := ioutil.ReadDir(dir)
+
+		//Directories that do not exist or can not be read are skipped, like the shell does for PATH
+		if err != nil {
+			continue
+		}
+
+		for _, file := range files {
+			name, ok := pluginName(file)
+
+			if !ok {
+				continue
+			}
+
+			if _, exists := found[name]; !exists {
+				found[name] = Plugin{Name: name, Path: filepath.Join(dir, file.Name())}
+			}
+		}
+	}
+
+	plugins := []Plugin{}
+
+	for _, plugin := range found {
+		plugins = append(plugins, plugin)
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	return plugins, nil
+}
+
+//pluginName gets the name of the plugin that the file provides if it is a plugin executable
+func pluginName(file os.FileInfo) (string, bool) {
+	if file.IsDir() || !strings.HasPrefix(file.Name(), PluginPrefix) {
+		return "", false
+	}
+
+	name := strings.TrimPrefix(file.Name(), PluginPrefix)
+
+	if runtime.GOOS == "windows" {
+		//Windows decides if a file is executable by its extension
+		ext := strings.ToLower(filepath.Ext(name))
+
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	} else if file.Mode()&0111 == 0 {
+		return "", false
+	}
+
+	return name, name != ""
+}
+
+//RunPlugin runs the plugin executable with the arguments, attached to the terminal of packageless.
+//The environment variables are added to the environment of packageless. The exit code of the plugin is returned.
+func (u *Utility) RunPlugin(path string, args []string, env []string) (int, error) {
+	u.Verbosef("running the plugin %s", path)
+
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,9 @@
+package utils
+
+import "golang.org/x/sys/unix"
+
+//Requests to get and set the attributes of a terminal
+const (
+	ioctlReadTermios  = unix.TCGETS
+	ioctlWriteTermios = unix.TCSETS
+)
@@ -0,0 +1,32 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package utils
+
+import (
+	"errors"
+	"os"
+)
+
+//Signals that packageless sends on to the container it runs, by their name in the Docker Engine API
+var forwardedSignals = map[os.Signal]string{
+	os.Interrupt: "SIGINT",
+}
+
+//Signals received when the terminal is resized, there are none on this operating system
+var resizeSignals []os.Signal
+
+//isTerminal checks if the file descriptor is a terminal, which is not supported on this operating system
+func isTerminal(fd uintptr) bool {
+	return false
+}
+
+//makeRaw puts the terminal in raw mode, which is not supported on this operating system
+func makeRaw(fd uintptr) (func(), error) {
+	return nil, errors.New("utils: raw terminals are not supported on this operating system")
+}
+
+//terminalSize gets the size of the terminal, which is not supported on this operating system
+func terminalSize(fd uintptr) (uint, uint, error) {
+	return 0, 0, errors.New("utils: terminal sizes are not supported on this operating system")
+}
@@ -0,0 +1,68 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package utils
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+//Signals that packageless sends on to the container it runs, by their name in the Docker Engine API
+var forwardedSignals = map[os.Signal]string{
+	unix.SIGINT:  "SIGINT",
+	unix.SIGTERM: "SIGTERM",
+	unix.SIGQUIT: "SIGQUIT",
+	unix.SIGHUP:  "SIGHUP",
+}
+
+//Signals received when the terminal is resized
+var resizeSignals = []os.Signal{unix.SIGWINCH}
+
+//isTerminal checks if the file descriptor is a terminal
+func isTerminal(fd uintptr) bool {
+	_, err := unix.IoctlGetTermios(int(fd), ioctlReadTermios)
+	return err == nil
+}
+
+//makeRaw puts the terminal in raw mode, so that input is passed on as it is typed without being echoed,
+//and returns the function restoring the previous mode
+func makeRaw(fd uintptr) (func(), error) {
+	termios, err := unix.IoctlGetTermios(int(fd), ioctlReadTermios)
+
+	if err != nil {
+		return nil, err
+	}
+
+	previous := *termios
+
+	termios.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	termios.Oflag &^= unix.OPOST
+	termios.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	termios.Cflag &^= unix.CSIZE | unix.PARENB
+	termios.Cflag |= unix.CS8
+	termios.Cc[unix.VMIN] = 1
+	termios.Cc[unix.VTIME] = 0
+
+	err = unix.IoctlSetTermios(int(fd), ioctlWriteTermios, termios)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		unix.IoctlSetTermios(int(fd), ioctlWriteTermios, &previous)
+	}, nil
+}
+
+//terminalSize gets the height and width of the terminal
+func terminalSize(fd uintptr) (uint, uint, error) {
+	size, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint(size.Row), uint(size.Col), nil
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+//Signals that packageless sends on to the container it runs, by their name in the Docker Engine API
+var forwardedSignals = map[os.Signal]string{
+	os.Interrupt:    "SIGINT",
+	syscall.SIGTERM: "SIGTERM",
+}
+
+//Windows does not signal when the console is resized, the size of the container terminal is only set when it starts
+var resizeSignals []os.Signal
+
+//isTerminal checks if the handle is a console
+func isTerminal(fd uintptr) bool {
+	var mode uint32
+	return windows.GetConsoleMode(windows.Handle(fd), &mode) == nil
+}
+
+//makeRaw puts the console in raw mode, so that input is passed on as it is typed without being echoed,
+//and returns the function restoring the previous mode
+func makeRaw(fd uintptr) (func(), error) {
+	var previous uint32
+
+	err := windows.GetConsoleMode(windows.Handle(fd), &previous)
+
+	if err != nil {
+		return nil, err
+	}
+
+	raw := previous &^ (windows.ENABLE_ECHO_INPUT | windows.ENABLE_PROCESSED_INPUT | windows.ENABLE_LINE_INPUT)
+	raw |= windows.ENABLE_VIRTUAL_TERMINAL_INPUT
+
+	err = windows.SetConsoleMode(windows.Handle(fd), raw)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		windows.SetConsoleMode(windows.Handle(fd), previous)
+	}, nil
+}
+
+//terminalSize gets the height and width of the visible window of the console
+func terminalSize(fd uintptr) (uint, uint, error) {
+	var info windows.ConsoleScreenBufferInfo
+
+	err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info)
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint(info.Window.Bottom - info.Window.Top + 1), uint(info.Window.Right - info.Window.Left + 1), nil
+}
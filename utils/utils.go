@@ -6,14 +6,20 @@ import (
 	"errors"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
 	"github.com/hashicorp/hcl2/hcl"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
@@ -26,38 +32,87 @@ type Client interface {
 	CopyFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, types.ContainerPathStat, error)
 	ContainerRemove(ctx context.Context, container string, options types.ContainerRemoveOptions) error
 	ImageRemove(ctx context.Context, imageID string, options types.ImageRemoveOptions) ([]types.ImageDeleteResponseItem, error)
+	Ping(ctx context.Context) (types.Ping, error)
+	ServerVersion(ctx context.Context) (types.Version, error)
+	ContainerList(ctx context.Context, options types.ContainerListOptions) ([]types.Container, error)
+	ContainerStop(ctx context.Context, containerID string, timeout *time.Duration) error
+	ContainerKill(ctx context.Context, containerID string, signal string) error
+	DistributionInspect(ctx context.Context, image string, encodedRegistryAuth string) (registry.DistributionInspect, error)
+	ImageTag(ctx context.Context, source string, target string) error
+	ContainerAttach(ctx context.Context, container string, options types.ContainerAttachOptions) (types.HijackedResponse, error)
+	ContainerStart(ctx context.Context, containerID string, options types.ContainerStartOptions) error
+	ContainerResize(ctx context.Context, containerID string, options types.ResizeOptions) error
+	ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.ContainerWaitOKBody, <-chan error)
 }
 
 //Tools interface so that we can create a mock of our utility functions in our unit tests
 type Tools interface {
 	MakeDir(path string) error
 	OpenFile(path string) (*os.File, error)
+	WriteFile(path string, content string) error
+	ReadFile(path string) (string, error)
+	OpenEditor(path string) error
 	RemoveDir(path string) error
+	CopyDir(source string, dest string) error
 	UpgradeDir(path string) error
 	ParseBody(body hcl.Body, out interface{}) (interface{}, error)
 	GetHCLBody(filepath string) (hcl.Body, error)
+	HCLDiagnostics(path string, out interface{}) hcl.Diagnostics
 	PullImage(name string, cli Client) error
 	ImageExists(imageID string, cli Client) (bool, error)
+	FindImage(imageID string, cli Client) (types.ImageSummary, bool, error)
 	CreateContainer(image string, cli Client) (string, error)
 	CopyFromContainer(source string, dest string, containerID string, cli Client, cp Copier) error
 	RemoveContainer(containerID string, cli Client) error
-	RunContainer(image string, ports []string, volumes []string, containerName string, args []string) (string, error)
+	RunContainer(opts RunOptions, cli Client) (int, error)
+	ListPimContainers(cli Client) ([]types.Container, error)
+	ListTemporaryContainers(cli Client) ([]types.Container, error)
+	ListImages(cli Client) ([]types.ImageSummary, error)
+	StopContainer(containerID string, timeout time.Duration, cli Client) error
 	RemoveImage(image string, cli Client) error
+	RemoteDigest(image string, cli Client) (string, error)
+	TagImage(source string, target string, cli Client) error
+	DockerVersion(cli Client) (types.Version, error)
 	AddAliasWin(name string, ed string) error
 	RemoveAliasWin(name string, ed string) error
 	AddAliasUnix(name string, ed string) error
 	RemoveAliasUnix(name string, ed string) error
+	AliasExistsWin(name string, ed string) (bool, error)
+	AliasExistsUnix(name string, ed string) (bool, error)
 	FetchPimConfig(baseUrl string, pimName string, savePath string) error
+	FetchIndex(baseUrl string, savePath string) error
+	CheckRepositoryHost(baseUrl string) error
 	FileExists(path string) bool
+	DirWritable(path string) error
 	RemoveFile(path string) error
+	DirSize(path string) (int64, error)
+	TempDir(pattern string) (string, error)
 	GetListOfInstalledPimConfigs(pimConfigDir string) ([]string, error)
+	ListDir(path string) ([]string, error)
 	Getwd() (string, error)
+	FindFileUp(dir string, name string) string
+	PortAvailable(protocol string, port int) bool
+	FindPlugins(dirs []string) ([]Plugin, error)
+	RunPlugin(path string, args []string, env []string) (int, error)
 	RenderInfoMarkdown(input string)
 	RenderErrorMarkdown(input string)
+	Emit(event Event)
+	RedirectOutput(out io.Writer)
 }
 
 //Utility Tool struct with its functions
-type Utility struct{}
+type Utility struct {
+	//Output what packageless is doing
+	Verbose bool
+
+	//Format that the events and messages are output in, markdown is used if it is not set
+	Output Output
+
+	//Standard input, output and error the containers are attached to, os.Stdin, os.Stdout and os.Stderr are used if they are not set
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
 
 func NewUtility() *Utility {
 	util := &Utility{}
@@ -128,6 +183,49 @@ func (u *Utility) OverwriteFile(path string) (*os.File, error) {
 	return file, nil
 }
 
+//WriteFile writes the content to the specified file, replacing the file if it already exists
+func (u *Utility) WriteFile(path string, content string) error {
+	return ioutil.WriteFile(path, []byte(content), 0644)
+}
+
+//ReadFile returns the content of the specified file
+func (u *Utility) ReadFile(path string) (string, error) {
+	content, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+//OpenEditor opens the specified file in the editor set by the VISUAL or EDITOR environment variables and waits for it to be closed
+func (u *Utility) OpenEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor == "" {
+		editor = "vi"
+
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	//The editor can include arguments, e.g. "code --wait"
+	args := strings.Fields(editor)
+
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
 //RemoveDir removes the specified directory
 func (u *Utility) RemoveDir(path string) error {
 	if _, err := os.Stat(path); err != nil {
@@ -147,6 +245,71 @@ func (u *Utility) RemoveDir(path string) error {
 	return nil
 }
 
+//CopyDir copies the directory and everything in it to the destination, keeping the permissions of the files.
+//Symbolic links are copied as links and the destination is replaced if it already exists
+func (u *Utility) CopyDir(source string, dest string) error {
+	u.Verbosef("copying %s to %s", source, dest)
+
+	err := os.RemoveAll(dest)
+
+	if err != nil {
+		return err
+	}
+
+	return filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, path)
+
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, rel)
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+
+			if err != nil {
+				return err
+			}
+
+			return os.Symlink(link, target)
+		case !info.Mode().IsRegular():
+			//Sockets, pipes and devices can not be copied
+			return nil
+		}
+
+		in, err := os.Open(path)
+
+		if err != nil {
+			return err
+		}
+
+		defer in.Close()
+
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(out, in)
+
+		if err != nil {
+			out.Close()
+			return err
+		}
+
+		return out.Close()
+	})
+}
+
 //UpgradeDir resets the directory by removing it if it exists and then recreating it
 func (u *Utility) UpgradeDir(path string) error {
 	if _, err := os.Stat(path); err != nil {
@@ -177,29 +340,73 @@ func (u *Utility) UpgradeDir(path string) error {
 	return nil
 }
 
+//IndexFile is the name of the repository index file listing the pims that are available in a repository
+const IndexFile = "index.hcl"
+
 //FetchPimConfig will get download the latest pim configuration for specified pim
 func (u *Utility) FetchPimConfig(baseUrl string, pimName string, savePath string) error {
-	pimFile := pimName + ".hcl"
-	url := baseUrl + pimFile
-	resp, err := http.Get(url)
+	err := u.fetchRepositoryFile(baseUrl, pimName+".hcl", savePath)
 
-	if err != nil {
-		return err
+	if os.IsNotExist(err) {
+		return errors.New("Could not find pim configuration for pim: " + pimName)
 	}
 
-	if resp.StatusCode != 200 {
-		return errors.New("Could not find pim configuration for pim: " + pimName)
+	return err
+}
+
+//FetchIndex will download the latest repository index to the specified directory
+func (u *Utility) FetchIndex(baseUrl string, savePath string) error {
+	err := u.fetchRepositoryFile(baseUrl, IndexFile, savePath)
+
+	if os.IsNotExist(err) {
+		return errors.New("Could not find the repository index at: " + baseUrl + IndexFile)
+	}
+
+	return err
+}
+
+//fetchRepositoryFile downloads a file from the repository to the specified directory.
+//The repository can either be a http(s) URL, a file:// URL or a path to a local directory.
+//An error satisfying os.IsNotExist is returned if the repository does not have the file.
+func (u *Utility) fetchRepositoryFile(baseUrl string, fileName string, savePath string) error {
+	var reader io.ReadCloser
+
+	u.Verbosef("fetching %s%s to %s", baseUrl, fileName, savePath)
+
+	if strings.HasPrefix(baseUrl, "http://") || strings.HasPrefix(baseUrl, "https://") {
+		resp, err := http.Get(baseUrl + fileName)
+
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode != 200 {
+			resp.Body.Close()
+			return os.ErrNotExist
+		}
+
+		reader = resp.Body
+	} else {
+		file, err := os.Open(strings.TrimPrefix(baseUrl, "file://") + fileName)
+
+		if err != nil {
+			return err
+		}
+
+		reader = file
 	}
 
-	defer resp.Body.Close()
+	defer reader.Close()
 
-	file, err := u.OverwriteFile(savePath + pimFile)
+	file, err := u.OverwriteFile(savePath + fileName)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = io.Copy(file, resp.Body)
+	defer file.Close()
+
+	_, err = io.Copy(file, reader)
 
 	if err != nil {
 		return err
@@ -208,6 +415,40 @@ func (u *Utility) FetchPimConfig(baseUrl string, pimName string, savePath string
 	return nil
 }
 
+//CheckRepositoryHost checks that the repository can be reached.
+//For a http(s) repository any response from the host means it is reachable, for a local repository the directory must exist
+func (u *Utility) CheckRepositoryHost(baseUrl string) error {
+	if strings.HasPrefix(baseUrl, "http://") || strings.HasPrefix(baseUrl, "https://") {
+		client := http.Client{Timeout: 10 * time.Second}
+
+		resp, err := client.Get(baseUrl + IndexFile)
+
+		if err != nil {
+			return err
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode >= 500 {
+			return errors.New("The repository host responded with status: " + resp.Status)
+		}
+
+		return nil
+	}
+
+	info, err := os.Stat(strings.TrimPrefix(baseUrl, "file://"))
+
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return errors.New("The repository " + baseUrl + " is not a directory")
+	}
+
+	return nil
+}
+
 //FileExists - checks to see if a file exists
 func (u *Utility) FileExists(path string) bool {
 	returnVal := false
@@ -241,6 +482,47 @@ func (u *Utility) RemoveFile(path string) error {
 	return nil
 }
 
+//DirWritable - checks that files can be created in the specified directory
+func (u *Utility) DirWritable(path string) error {
+	file, err := ioutil.TempFile(path, ".packageless-write-check")
+
+	if err != nil {
+		return err
+	}
+
+	file.Close()
+
+	return os.Remove(file.Name())
+}
+
+//DirSize - returns the total size in bytes of the files within the specified directory.
+//A directory that does not exist has a size of 0
+func (u *Utility) DirSize(path string) (int64, error) {
+	var size int64
+
+	if !u.FileExists(path) {
+		return 0, nil
+	}
+
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
 //GetListOfInstalledPimConfigs - returns a string array of the names of the pims
 //with configuration files currently in the pim configuration directory
 func (u *Utility) GetListOfInstalledPimConfigs(pimConfigDir string) ([]string, error) {
@@ -258,11 +540,91 @@ func (u *Utility) GetListOfInstalledPimConfigs(pimConfigDir string) ([]string, e
 	return pimNames, nil
 }
 
+//ListDir returns the names of the files and directories in the directory, a directory that does not exist is empty
+func (u *Utility) ListDir(path string) ([]string, error) {
+	fileInfo, err := ioutil.ReadDir(path)
+
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, file := range fileInfo {
+		names = append(names, file.Name())
+	}
+
+	return names, nil
+}
+
+//TempDir creates a new temporary directory and returns its path with a trailing separator
+func (u *Utility) TempDir(pattern string) (string, error) {
+	dir, err := ioutil.TempDir("", pattern)
+
+	if err != nil {
+		return "", err
+	}
+
+	return dir + string(filepath.Separator), nil
+}
+
 //Getwd returns a rooted path name corresponding to the current directory
 func (u *Utility) Getwd() (string, error) {
 	return os.Getwd()
 }
 
+//FindFileUp looks for a file with the name in the directory and its parents, returning the path of the nearest one.
+//The path is empty if none of the directories has the file
+func (u *Utility) FindFileUp(dir string, name string) string {
+	for {
+		path := filepath.Join(dir, name)
+
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(dir)
+
+		//The root directory is its own parent
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+//PortAvailable checks if a port on the host is free to be published by a container.
+//Only tcp and udp ports can be checked, ports of other protocols are assumed to be free
+func (u *Utility) PortAvailable(protocol string, port int) bool {
+	address := ":" + strconv.Itoa(port)
+
+	switch protocol {
+	case "tcp":
+		listener, err := net.Listen("tcp", address)
+
+		if err != nil {
+			return false
+		}
+
+		listener.Close()
+	case "udp":
+		conn, err := net.ListenPacket("udp", address)
+
+		if err != nil {
+			return false
+		}
+
+		conn.Close()
+	}
+
+	return true
+}
+
 //Create an interface to house the CopyFiles implementation. This will allow us to make a mock of the CopyFiles Function.
 type Copier interface {
 	CopyFiles(reader io.ReadCloser, dest string, source string) error
@@ -358,3 +720,12 @@ func (cp *CopyTool) CopyFiles(reader io.ReadCloser, dest string, source string)
 
 	return nil
 }
+
+//AliasLine returns the line that sets the alias for the specified package name in the shell of the current operating system
+func AliasLine(name string, ed string) string {
+	if runtime.GOOS == "windows" {
+		return strings.TrimSuffix(aliasLineWin(name, ed), "\n")
+	}
+
+	return strings.TrimSuffix(aliasLineUnix(name, ed), "\n")
+}
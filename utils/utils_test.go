@@ -0,0 +1,371 @@
+package utils
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+//Create a local repository containing a pim configuration and an index
+func createTestRepository(t *testing.T) string {
+	repo, err := ioutil.TempDir("", "packageless-repo")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(repo) })
+
+	err = ioutil.WriteFile(filepath.Join(repo, "python.hcl"), []byte(`pim "python" {}`), 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(repo, IndexFile), []byte(`pim "python" {
+	description = "An interpreted programming language"
+	versions = ["latest", "3.9"]
+}`), 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return repo + "/"
+}
+
+//Create a directory to save fetched files to
+func createSaveDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "packageless-save")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	return dir + "/"
+}
+
+//Test fetching files from a repository that is a local directory
+func TestFetchFromLocalDirectory(t *testing.T) {
+	repo := createTestRepository(t)
+	save := createSaveDir(t)
+
+	util := NewUtility()
+
+	err := util.FetchPimConfig(repo, "python", save)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !util.FileExists(save + "python.hcl") {
+		t.Fatal("FetchPimConfig: Expected the pim configuration to have been saved but it was not")
+	}
+
+	err = util.FetchIndex("file://"+repo, save)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//Make sure the fetched index can be parsed
+	body, err := util.GetHCLBody(save + IndexFile)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parseOut, err := util.ParseBody(body, RepositoryIndex{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := parseOut.(RepositoryIndex)
+
+	if len(index.Pims) != 1 || index.Pims[0].Name != "python" || len(index.Pims[0].Versions) != 2 {
+		t.Fatalf("FetchIndex: Index was not fetched correctly | Received: %v", index)
+	}
+}
+
+//Test fetching files from a repository served over HTTP
+func TestFetchFromHTTP(t *testing.T) {
+	repo := createTestRepository(t)
+	save := createSaveDir(t)
+
+	server := httptest.NewServer(http.FileServer(http.Dir(repo)))
+	defer server.Close()
+
+	util := NewUtility()
+
+	err := util.FetchPimConfig(server.URL+"/", "python", save)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = util.FetchIndex(server.URL+"/", save)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !util.FileExists(save+"python.hcl") || !util.FileExists(save+IndexFile) {
+		t.Fatal("Fetch: Expected the pim configuration and index to have been saved but they were not")
+	}
+}
+
+//Test fetching a pim configuration that the repository does not have
+func TestFetchPimConfigNotFound(t *testing.T) {
+	repo := createTestRepository(t)
+	save := createSaveDir(t)
+
+	server := httptest.NewServer(http.FileServer(http.Dir(repo)))
+	defer server.Close()
+
+	util := NewUtility()
+
+	expectedErr := "Could not find pim configuration for pim: ruby"
+
+	for _, baseUrl := range []string{repo, server.URL + "/"} {
+		err := util.FetchPimConfig(baseUrl, "ruby", save)
+
+		if err == nil {
+			t.Fatalf("FetchPimConfig: Expected Error: %s | Received No Error", expectedErr)
+		}
+
+		if err.Error() != expectedErr {
+			t.Fatalf("FetchPimConfig: Expected Error: %s | Received: %s", expectedErr, err.Error())
+		}
+	}
+}
+
+//Test checking if directories are writable
+func TestDirWritable(t *testing.T) {
+	dir := createSaveDir(t)
+
+	util := NewUtility()
+
+	err := util.DirWritable(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 0 {
+		t.Fatal("DirWritable: Expected the directory to be left empty")
+	}
+
+	err = util.DirWritable(dir + "missing")
+
+	if err == nil {
+		t.Fatal("DirWritable: Expected to receive an error for a missing directory, but did not receive one.")
+	}
+}
+
+//Test checking if the repository host can be reached
+func TestCheckRepositoryHost(t *testing.T) {
+	repo := createTestRepository(t)
+
+	server := httptest.NewServer(http.FileServer(http.Dir(repo)))
+
+	util := NewUtility()
+
+	for _, baseUrl := range []string{repo, "file://" + repo, server.URL + "/"} {
+		err := util.CheckRepositoryHost(baseUrl)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server.Close()
+
+	for _, baseUrl := range []string{repo + "missing/", server.URL + "/"} {
+		err := util.CheckRepositoryHost(baseUrl)
+
+		if err == nil {
+			t.Fatalf("CheckRepositoryHost: Expected to receive an error for %s, but did not receive one.", baseUrl)
+		}
+	}
+}
+
+//Test plugins are found by their prefix and the first directory takes precedence
+func TestFindPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are found by their extension on windows")
+	}
+
+	first := t.TempDir()
+	second := t.TempDir()
+
+	files := map[string]os.FileMode{
+		filepath.Join(first, "packageless-team-sync"):  0755,
+		filepath.Join(second, "packageless-team-sync"): 0755,
+		filepath.Join(second, "packageless-lint"):      0755,
+		filepath.Join(second, "packageless-notes"):     0644,
+		filepath.Join(second, "other-tool"):            0755,
+	}
+
+	for path, mode := range files {
+		err := ioutil.WriteFile(path, []byte("#!/bin/sh\nexit 3\n"), mode)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	util := NewUtility()
+
+	plugins, err := util.FindPlugins([]string{first, filepath.Join(first, "missing"), second})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Plugin{
+		{Name: "lint", Path: filepath.Join(second, "packageless-lint")},
+		{Name: "team-sync", Path: filepath.Join(first, "packageless-team-sync")},
+	}
+
+	if !reflect.DeepEqual(expected, plugins) {
+		t.Fatalf("FindPlugins: Expected: %v | Received: %v", expected, plugins)
+	}
+
+	exitCode, err := util.RunPlugin(plugins[0].Path, nil, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exitCode != 3 {
+		t.Fatalf("RunPlugin: Expected the exit code 3 of the plugin | Received: %d", exitCode)
+	}
+}
+
+//Test copying a directory replaces the destination with the files of the source
+func TestCopyDir(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "data")
+	dest := filepath.Join(t.TempDir(), "snapshot")
+
+	err := os.MkdirAll(filepath.Join(source, "nested"), 0755)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(source, "nested", "file.txt"), []byte("data"), 0600)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.MkdirAll(dest, 0755)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(dest, "stale.txt"), []byte("stale"), 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	util := NewUtility()
+
+	err = util.CopyDir(source, dest)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(dest, "nested", "file.txt"))
+
+	if err != nil || string(content) != "data" {
+		t.Fatalf("CopyDir: Expected the file to be copied | Received: %s %v", content, err)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filepath.Join(dest, "nested", "file.txt"))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if info.Mode().Perm() != 0600 {
+			t.Fatalf("CopyDir: Expected the permissions of the file to be kept | Received: %v", info.Mode())
+		}
+	}
+
+	if util.FileExists(filepath.Join(dest, "stale.txt")) {
+		t.Fatal("CopyDir: Expected the files that were in the destination to be removed")
+	}
+}
+
+//Test the nearest file is found in the directory or its parents
+func TestFindFileUp(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "project", "src")
+
+	err := os.MkdirAll(nested, 0755)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	util := NewUtility()
+
+	if path := util.FindFileUp(nested, ManifestFile); path != "" {
+		t.Fatalf("FindFileUp: Expected no file to be found | Received: %s", path)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(root, "project", ManifestFile), []byte(""), 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := filepath.Join(root, "project", ManifestFile)
+
+	if path := util.FindFileUp(nested, ManifestFile); path != expected {
+		t.Fatalf("FindFileUp: Expected: %s | Received: %s", expected, path)
+	}
+}
+
+//Test a port that is listened on is reported as not available
+func TestPortAvailable(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	util := NewUtility()
+
+	if util.PortAvailable("tcp", port) {
+		t.Fatalf("PortAvailable: Expected port %d to be in use", port)
+	}
+
+	listener.Close()
+
+	if !util.PortAvailable("tcp", port) {
+		t.Fatalf("PortAvailable: Expected port %d to be available", port)
+	}
+}